@@ -0,0 +1,84 @@
+/**
+ * Benchmark prefix search against a glob find over the same names
+ */
+
+import { existsSync, unlinkSync } from 'fs';
+import { CodeIndex, MemoryFileSystem } from '../src/index.js';
+
+const FILES = 1000; // 100,000 functions in all
+const FUNCS_PER_FILE = 100;
+const VERBS = ['Get', 'Set', 'Load', 'Save', 'Find', 'Delete', 'Parse', 'Render'];
+const QUERIES = ['get', 'Load', 'parseU', 'renderPage1', 'zzz'];
+const ROUNDS = 200;
+
+function generate(): MemoryFileSystem {
+  const files: Record<string, string> = {};
+  for (let f = 0; f < FILES; f++) {
+    const lines = [`package pkg${f}`, ''];
+    for (let i = 0; i < FUNCS_PER_FILE; i++) {
+      const verb = VERBS[(f + i) % VERBS.length];
+      const noun = ['User', 'Order', 'Page', 'Item'][i % 4];
+      lines.push(`func ${verb}${noun}${f}x${i}() {}`);
+    }
+    files[`pkg${f}/file.go`] = lines.join('\n') + '\n';
+  }
+  return new MemoryFileSystem(files);
+}
+
+function time(label: string, fn: () => Promise<unknown>): Promise<number> {
+  return (async () => {
+    const start = performance.now();
+    for (let i = 0; i < ROUNDS; i++) await fn();
+    const perCall = (performance.now() - start) / ROUNDS;
+    console.log(`  ${label.padEnd(32)} ${perCall.toFixed(3)} ms/call`);
+    return perCall;
+  })();
+}
+
+async function main() {
+  console.log('=== Prefix Search Benchmark ===\n');
+
+  const dbPath = '.codeindex/bench-prefix.db';
+  if (existsSync(dbPath)) {
+    unlinkSync(dbPath);
+  }
+
+  const fs = generate();
+  const index = await CodeIndex.create({ rootDir: '/bench', dbPath, languages: ['go'], fs });
+  await index.reindexAll();
+  console.log(`Indexed ${FILES * FUNCS_PER_FILE} functions\n`);
+
+  let start = performance.now();
+  await index.prefixSearch('a', 1);
+  console.log(`First prefix search (builds the index): ${(performance.now() - start).toFixed(1)} ms\n`);
+
+  for (const query of QUERIES) {
+    console.log(`Query "${query}" (limit 20):`);
+    const prefix = await time('prefixSearch', () => index.prefixSearch(query, 20));
+    const glob = await time('find (glob, then slice)', async () => (await index.find(`${query}*`)).slice(0, 20));
+    console.log(`  speedup: ${(glob / prefix).toFixed(1)}x\n`);
+
+    const expected = (await index.find(`${query}*`)).map(s => s.symbolId).sort((a, b) => a! - b!);
+    const actual = (await index.prefixSearch(query, Number.MAX_SAFE_INTEGER)).map(s => s.symbolId).sort((a, b) => a! - b!);
+    if (JSON.stringify(expected) !== JSON.stringify(actual)) {
+      console.error(`  ✗ prefixSearch("${query}") and find("${query}*") disagree\n`);
+      process.exitCode = 1;
+    }
+  }
+
+  // A change to the index invalidates the name index
+  fs.writeFile('pkg0/extra.go', 'package pkg0\n\nfunc ZzzTop() {}\n');
+  await index.updateFile('pkg0/extra.go');
+  start = performance.now();
+  const found = await index.prefixSearch('zzz', 5);
+  console.log(`After indexing a new file: ${found.map(s => s.name).join(', ')} (${(performance.now() - start).toFixed(1)} ms, rebuilt)`);
+  if (found.length !== 1 || found[0].name !== 'ZzzTop') {
+    console.error('✗ prefix index was not rebuilt after a change');
+    process.exitCode = 1;
+  }
+
+  index.close();
+  console.log(process.exitCode ? '\n❌ Prefix search benchmark found mismatches' : '\n✓ Prefix search benchmark completed');
+}
+
+main().catch(console.error);
@@ -65,7 +65,7 @@ async function demoNestedStruct() {
 
   console.log('\n✨ 演示完成！');
   console.log(`\n💡 提示: 使用不同的深度值运行:`);
-  console.log(`   node examples/demo-nested-struct.ts 0  # 不索引嵌套字段`);
+  console.log(`   node examples/demo-nested-struct.ts 0  # 不限制嵌套深度`);
   console.log(`   node examples/demo-nested-struct.ts 1  # 索引深度 1`);
   console.log(`   node examples/demo-nested-struct.ts 3  # 索引深度 3 (默认)`);
   console.log(`   node examples/demo-nested-struct.ts 5  # 索引深度 5`);
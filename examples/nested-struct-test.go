@@ -1,6 +1,11 @@
 // Test nested structs indexing
 package main
 
+import (
+	"bytes"
+	"sync"
+)
+
 // Address represents a physical address
 type Address struct {
 	Street  string
@@ -44,6 +49,13 @@ type Employee struct {
 	OfficeLocation Address
 }
 
+// SafeBuffer embeds types from other packages
+type SafeBuffer struct {
+	sync.Mutex    // 限定类型嵌入
+	*bytes.Buffer // 指针形式的限定类型嵌入
+	Name          string
+}
+
 // ComplexStruct with mixed nested patterns
 type ComplexStruct struct {
 	ID   int
@@ -148,6 +148,27 @@ type Validator interface {
 	Validate() error
 }
 
+// UserName returns the name of v if it holds a *User
+func UserName(v interface{}) (string, bool) {
+	u, ok := v.(*User)
+	if !ok {
+		return "", false
+	}
+	return u.Name, true
+}
+
+// Describe names the kind of value v holds
+func Describe(v interface{}) string {
+	switch x := v.(type) {
+	case *User:
+		return "user " + x.Name
+	case Point:
+		return "point"
+	default:
+		return "unknown"
+	}
+}
+
 // Validate implements Validator interface for User
 func (u *User) Validate() error {
 	if u.Name == "" {
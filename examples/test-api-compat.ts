@@ -0,0 +1,85 @@
+/**
+ * Test API breaking-change detection against the exported surface of sample-code.go
+ */
+
+import { readFileSync, existsSync, unlinkSync } from 'fs';
+import { CodeIndex, breakingChanges } from '../src/index.js';
+
+const edits: Array<[string, string]> = [
+  // breaking: parameter added
+  ['func ValidateEmail(email string) bool', 'func ValidateEmail(email string, strict bool) bool'],
+  // breaking: exported var removed
+  ['\tDebugMode     bool\n', ''],
+  // breaking: field retyped; not breaking: field added
+  ['\tEmail    string\n', '\tEmail    []string\n\tPhone    string\n'],
+  // breaking: method added to an existing interface
+  ['\tValidate() error\n}', '\tValidate() error\n\tName() string\n}'],
+  // not breaking: parameter renamed
+  ['func FormatUserName(name string) string', 'func FormatUserName(fullName string) string'],
+  // not breaking: new function
+  ['// Constants\n', 'func Version() string { return "1" }\n\n// Constants\n'],
+];
+
+const expected: Record<string, boolean> = {
+  'example.ValidateEmail': true,
+  'example.DebugMode': true,
+  'example.User.Email': true,
+  'example.User.Phone': false,
+  'example.Validator.Name': true,
+  'example.FormatUserName': false,
+  'example.Version': false,
+};
+
+async function main() {
+  console.log('=== API Compatibility Test ===\n');
+
+  const dbPath = '.codeindex/api-compat.db';
+  if (existsSync(dbPath)) {
+    unlinkSync(dbPath);
+  }
+
+  const index = await CodeIndex.create({
+    rootDir: process.cwd(),
+    dbPath,
+    languages: ['go'],
+    include: ['examples/sample-code.go'],
+  });
+  await index.reindexAll(() => {});
+  const before = index.exportJSON();
+
+  let source = readFileSync('examples/sample-code.go', 'utf-8');
+  for (const [from, to] of edits) {
+    source = source.replace(from, to);
+  }
+  index.indexSource('examples/sample-code.go', source);
+  const after = index.exportJSON();
+
+  const changes = breakingChanges(before, after);
+  let failures = 0;
+
+  for (const change of changes) {
+    const mark = change.breaking ? 'BREAKING' : 'ok';
+    console.log(`   ${change.change} ${change.kind} ${change.qualifiedName}: ${change.reason} [${mark}]`);
+  }
+  console.log();
+
+  for (const [qualifiedName, breaking] of Object.entries(expected)) {
+    const change = changes.find(c => c.qualifiedName === qualifiedName);
+    if (!change || change.breaking !== breaking) {
+      console.log(`   ✗ ${qualifiedName}: expected breaking=${breaking}, got ${change ? change.breaking : 'no change'}`);
+      failures++;
+    }
+  }
+  const unexpected = changes.filter(c => !(c.qualifiedName in expected));
+  for (const change of unexpected) {
+    console.log(`   ✗ unexpected change: ${change.qualifiedName}`);
+    failures++;
+  }
+
+  console.log(failures === 0 ? '✅ All changes classified correctly' : `❌ ${failures} mismatches`);
+  process.exitCode = failures === 0 ? 0 : 1;
+
+  index.close();
+}
+
+main().catch(console.error);
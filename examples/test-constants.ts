@@ -0,0 +1,88 @@
+/**
+ * Test typed and untyped Go constants
+ */
+
+import { existsSync, unlinkSync } from 'fs';
+import { CodeIndex } from '../src/index.js';
+
+const source = `package config
+
+import (
+	"time"
+	"unsafe"
+)
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+const (
+	MaxUsers   = 1000
+	Ratio      = 3.14
+	Debug      = false
+	Name       = "config"
+	Timeout    = time.Duration(5)
+	Limit      = MaxUsers * 2
+	NameLen    = len(Name)
+	WordSize   = unsafe.Sizeof(MaxUsers)
+	Smallest   = min(1, 2.5)
+	Imaginary  = imag(2i)
+	Typed      int64 = 7
+	FromTyped  = Typed + 1
+)
+`;
+
+// [qualified name, expected type, expected default type of an untyped constant]
+const expected: Array<[string, string | undefined, string | undefined]> = [
+  ['config.Red', 'Color', undefined],
+  ['config.Green', 'Color', undefined],
+  ['config.Blue', 'Color', undefined],
+  ['config.MaxUsers', undefined, 'int'],
+  ['config.Ratio', undefined, 'float64'],
+  ['config.Debug', undefined, 'bool'],
+  ['config.Name', undefined, 'string'],
+  ['config.Timeout', 'time.Duration', undefined],
+  ['config.Limit', undefined, 'int'],
+  ['config.NameLen', 'int', undefined],
+  ['config.WordSize', 'uintptr', undefined],
+  ['config.Smallest', undefined, undefined],
+  ['config.Imaginary', undefined, 'float64'],
+  ['config.Typed', 'int64', undefined],
+  ['config.FromTyped', 'int64', undefined],
+];
+
+async function main() {
+  console.log('=== Go Constant Types Test ===\n');
+
+  const dbPath = '.codeindex/constants.db';
+  if (existsSync(dbPath)) {
+    unlinkSync(dbPath);
+  }
+
+  const index = await CodeIndex.create({ rootDir: process.cwd(), dbPath, languages: ['go'] });
+  index.indexSource('config/config.go', source);
+
+  let failures = 0;
+  for (const [qualifiedName, type, defaultType] of expected) {
+    const details = (await index.lookup(qualifiedName))?.details;
+    const untyped = type === undefined;
+    const ok = details !== undefined &&
+      details.type === type &&
+      Boolean(details.untyped) === untyped &&
+      details.defaultType === defaultType;
+    const got = details?.untyped ? `untyped (default ${details.defaultType ?? '?'})` : details?.type ?? 'not found';
+    console.log(`   ${ok ? '✓' : '✗'} ${qualifiedName}: ${got}`);
+    if (!ok) failures++;
+  }
+
+  console.log(failures === 0 ? '\n✅ Constant types recorded correctly' : `\n❌ ${failures} failures`);
+  process.exitCode = failures === 0 ? 0 : 1;
+
+  index.close();
+}
+
+main().catch(console.error);
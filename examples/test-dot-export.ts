@@ -0,0 +1,70 @@
+/**
+ * Test the edges of the Graphviz DOT type graph
+ */
+
+import { existsSync, unlinkSync } from 'fs';
+import { Writable } from 'stream';
+import { CodeIndex } from '../src/index.js';
+
+// ctx, Size and User appear as names in Handler, Buffer and Meta; only real type references are edges
+const source = `package app
+
+const Size = 4
+
+type User struct{}
+type Request struct{}
+type Response struct{}
+type Tag struct{}
+
+type Handler struct {
+	Serve  func(User Request, n int) (Response, error)
+	Buffer [Size]byte
+	Meta   struct {
+		User string
+		Tags []Tag
+	}
+}
+`;
+
+async function main() {
+  console.log('=== DOT Export Test ===\n');
+
+  const dbPath = '.codeindex/dot-export.db';
+  if (existsSync(dbPath)) {
+    unlinkSync(dbPath);
+  }
+
+  const index = await CodeIndex.create({ rootDir: process.cwd(), dbPath, languages: ['go'] });
+  index.indexSource('app/app.go', source);
+
+  let dot = '';
+  index.writeDOT(new Writable({
+    write(chunk, _encoding, done) {
+      dot += chunk.toString();
+      done();
+    },
+  }));
+  console.log(dot);
+
+  const edge = (target: string) => `"app.Handler" -> "app.${target}";`;
+  const checks: Array<[string, boolean]> = [
+    ['edge to the func parameter type Request', dot.includes(edge('Request'))],
+    ['edge to the func result type Response', dot.includes(edge('Response'))],
+    ['edge to the inline struct field type Tag', dot.includes(edge('Tag'))],
+    ['no edge to the parameter name User', !dot.includes(edge('User'))],
+    ['no edge to the array length Size', !dot.includes(edge('Size'))],
+  ];
+
+  let failures = 0;
+  for (const [label, ok] of checks) {
+    console.log(`   ${ok ? '✓' : '✗'} ${label}`);
+    if (!ok) failures++;
+  }
+
+  console.log(failures === 0 ? '\n✅ DOT edges follow type references only' : `\n❌ ${failures} failures`);
+  process.exitCode = failures === 0 ? 0 : 1;
+
+  index.close();
+}
+
+main().catch(console.error);
@@ -96,6 +96,20 @@ async function main() {
     console.log(`   ✓ Found: ${maxUsersConst.kind} ${maxUsersConst.qualifiedName}\n`);
   }
 
+  // Type assertions and type switch cases are usages of the type
+  console.log('10. Finding usages of type "User"...');
+  const usages = await index.usagesOfType('User');
+  console.log(`   ✓ Found ${usages.length} usage(s)`);
+  for (const name of ['UserName', 'Describe']) {
+    if (!usages.some(symbol => symbol.name === name)) {
+      console.error(`     ✗ ${name} uses User in a type assertion or type switch but is not a usage`);
+      process.exitCode = 1;
+      continue;
+    }
+    console.log(`     - ${name}`);
+  }
+  console.log();
+
   index.close();
   console.log('=== Test Complete ===');
 }
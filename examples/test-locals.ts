@@ -0,0 +1,82 @@
+/**
+ * Test which Go locals includeLocals indexes
+ */
+
+import { existsSync, unlinkSync } from 'fs';
+import { CodeIndex } from '../src/index.js';
+
+const source = `package worker
+
+func fetch() (int, error) { return 0, nil }
+
+func Run(items []string) error {
+	const limit = 3
+	var total int
+	count, err := fetch()
+	total, err2 := fetch()
+	if err := check(); err != nil {
+		return err
+	}
+	for i := 0; i < limit; i++ {
+		total += i
+	}
+	switch n := len(items); n {
+	case 0:
+		return nil
+	}
+	for _, item := range items {
+		_ = item
+	}
+	handler := func(x int) error {
+		doubled := x * 2
+		_ = doubled
+		return nil
+	}
+retry:
+	_ = handler(count)
+	_ = err2
+	goto retry
+	return err
+}
+
+func check() error { return nil }
+`;
+
+async function main() {
+  console.log('=== Go Locals Test ===\n');
+
+  const dbPath = '.codeindex/locals.db';
+  if (existsSync(dbPath)) {
+    unlinkSync(dbPath);
+  }
+
+  const index = await CodeIndex.create({ rootDir: process.cwd(), dbPath, languages: ['go'], includeLocals: true });
+  index.indexSource('worker/run.go', source);
+
+  const names = async (scope: string) => (await index.localsOf(scope)).map(s => `${s.kind} ${s.name}`).sort();
+  // total is declared by var, so `total, err2 :=` only declares err2; header and range names are left out
+  const expected: Array<[string, string[]]> = [
+    ['worker.Run', ['constant limit', 'function handler', 'label retry', 'variable count', 'variable err', 'variable err2', 'variable total']],
+    ['worker.Run.handler', ['variable doubled']],
+  ];
+
+  let failures = 0;
+  for (const [scope, want] of expected) {
+    const got = await names(scope);
+    const ok = got.join(',') === want.join(',');
+    console.log(`   ${ok ? '✓' : '✗'} localsOf(${scope}): ${got.join(', ')}`);
+    if (!ok) failures++;
+  }
+
+  const exported = await index.lookup('worker.Run.count');
+  const ok = exported !== null && !exported.exported && exported.details?.scope === 'worker.Run';
+  console.log(`   ${ok ? '✓' : '✗'} worker.Run.count is unexported and scoped to worker.Run`);
+  if (!ok) failures++;
+
+  console.log(failures === 0 ? '\n✅ Locals indexed as expected' : `\n❌ ${failures} failures`);
+  process.exitCode = failures === 0 ? 0 : 1;
+
+  index.close();
+}
+
+main().catch(console.error);
@@ -72,6 +72,15 @@ async function testNestedStruct(maxDepth: number) {
   console.log(`  ${level4Data ? '✓' : '✗'} Level1.Level2.Level3.Level4.Data (深度 3): ${level4Data ? '找到' : '未找到'}`);
   console.log(`  ${level5Data ? '✓' : '✗'} Level1.Level2.Level3.Level4.Level5.Data (深度 4): ${level5Data ? '找到' : '未找到'}`);
 
+  // Embedded fields of qualified types are named after the type
+  const mutex = fields.find(s => s.qualifiedName === 'main.SafeBuffer.Mutex');
+  const buffer = fields.find(s => s.qualifiedName === 'main.SafeBuffer.Buffer');
+  const embeddingOk =
+    mutex?.details?.type === 'sync.Mutex' && mutex.details.typeRef?.importPath === 'sync' && !mutex.details.isPointer &&
+    buffer?.details?.type === '*bytes.Buffer' && buffer.details.typeRef?.importPath === 'bytes' && buffer.details.isPointer === true;
+  console.log(`\n🔗 限定类型嵌入 (SafeBuffer):`);
+  console.log(`  ${embeddingOk ? '✓' : '✗'} Mutex (sync.Mutex), Buffer (*bytes.Buffer): ${embeddingOk ? '正确' : '不正确'}`);
+
   // Show all fields for debugging
   if (process.argv.includes('--verbose')) {
     console.log(`\n📋 所有索引的字段:`);
@@ -97,6 +106,10 @@ async function testNestedStruct(maxDepth: number) {
     console.log(`\n❌ 测试失败: 应该能找到深度 3 的字段`);
     passed = false;
   }
+  if (!embeddingOk) {
+    console.log(`\n❌ 测试失败: 限定类型的嵌入字段名、类型或导入路径不正确`);
+    passed = false;
+  }
   if (maxDepth < 3 && addressStreet) {
     console.log(`\n❌ 测试失败: 不应该索引超过最大深度的字段`);
     passed = false;
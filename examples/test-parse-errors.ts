@@ -0,0 +1,66 @@
+/**
+ * Test indexing of Go files with syntax errors
+ */
+
+import { existsSync, unlinkSync } from 'fs';
+import { CodeIndex, SourceSyntaxError } from '../src/index.js';
+
+// The second function is missing its closing parenthesis
+const source = `package shop
+
+type Cart struct {
+	Items []string
+}
+
+func Broken(a int {
+	return
+}
+
+func Total(c Cart) int { return len(c.Items) }
+`;
+
+async function main() {
+  console.log('=== Parse Error Recovery Test ===\n');
+
+  const dbPath = '.codeindex/parse-errors.db';
+  if (existsSync(dbPath)) {
+    unlinkSync(dbPath);
+  }
+
+  const index = await CodeIndex.create({ rootDir: process.cwd(), dbPath, languages: ['go'] });
+  index.indexSource('shop/cart.go', source);
+
+  const errors = await index.parseErrors('shop/cart.go');
+  console.log('1. Recorded errors:');
+  for (const error of errors) {
+    console.log(`   ${error.path}:${error.line}:${error.column}: ${error.message}`);
+  }
+
+  console.log('\n2. Declarations around the error:');
+  let failures = errors.length === 0 ? 1 : 0;
+  for (const name of ['shop.Cart', 'shop.Cart.Items', 'shop.Total']) {
+    const symbol = await index.lookup(name);
+    console.log(`   ${symbol ? '✓' : '✗'} ${name}`);
+    if (!symbol) failures++;
+  }
+  index.close();
+
+  console.log('\n3. strictParse:');
+  unlinkSync(dbPath);
+  const strict = await CodeIndex.create({ rootDir: process.cwd(), dbPath, languages: ['go'], strictParse: true });
+  try {
+    strict.indexSource('shop/cart.go', source);
+    console.log('   ✗ no error thrown');
+    failures++;
+  } catch (error) {
+    const ok = error instanceof SourceSyntaxError;
+    console.log(`   ${ok ? '✓' : '✗'} ${error instanceof Error ? error.message : error}`);
+    if (!ok) failures++;
+  }
+  strict.close();
+
+  console.log(failures === 0 ? '\n✅ Broken file indexed partially' : `\n❌ ${failures} failures`);
+  process.exitCode = failures === 0 ? 0 : 1;
+}
+
+main().catch(console.error);
@@ -0,0 +1,66 @@
+/**
+ * Test that an index snapshot keeps answering with the data it was taken from
+ */
+
+import { existsSync, unlinkSync } from 'fs';
+import { CodeIndex } from '../src/index.js';
+
+const before = `package shop
+
+type Cart struct {
+	Items []string
+}
+
+func Total(c Cart) int { return len(c.Items) }
+`;
+
+// Total is renamed and a new type is added after the snapshot
+const after = `package shop
+
+type Cart struct {
+	Items []string
+}
+
+type Order struct {
+	Cart Cart
+}
+
+func Sum(c Cart) int { return len(c.Items) }
+`;
+
+async function main() {
+  console.log('=== Index Snapshot Test ===\n');
+
+  const dbPath = '.codeindex/snapshot.db';
+  if (existsSync(dbPath)) {
+    unlinkSync(dbPath);
+  }
+
+  const index = await CodeIndex.create({ rootDir: process.cwd(), dbPath, languages: ['go'] });
+  index.indexSource('shop/cart.go', before);
+
+  const snapshot = index.snapshot();
+  index.indexSource('shop/cart.go', after);
+
+  const checks: Array<[string, boolean]> = [
+    ['snapshot still finds shop.Total', snapshot.lookup('shop.Total') !== null],
+    ['snapshot does not see shop.Sum', snapshot.lookup('shop.Sum') === null],
+    ['snapshot does not see shop.Order', snapshot.lookup('shop.Order') === null],
+    ['live index finds shop.Sum', (await index.lookup('shop.Sum')) !== null],
+    ['live index dropped shop.Total', (await index.lookup('shop.Total')) === null],
+  ];
+
+  let failures = 0;
+  for (const [label, ok] of checks) {
+    console.log(`   ${ok ? '✓' : '✗'} ${label}`);
+    if (!ok) failures++;
+  }
+
+  console.log(failures === 0 ? '\n✅ Snapshot kept the old index' : `\n❌ ${failures} failures`);
+  process.exitCode = failures === 0 ? 0 : 1;
+
+  snapshot.close();
+  index.close();
+}
+
+main().catch(console.error);
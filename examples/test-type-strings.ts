@@ -0,0 +1,64 @@
+/**
+ * Test rendering of Go composite field types
+ */
+
+import { TreeSitterParser } from '../src/parser/tree-sitter-wrapper.js';
+import { GoExtractor } from '../src/extractor/go-extractor.js';
+
+const source = `package shapes
+
+type Pipes struct {
+	In    <-chan int
+	Out   chan<- int
+	Both  chan   string
+	Nested chan<- <-chan int
+	Index map[string][]*User
+	Raw   [...]byte
+	Buf   [16]byte
+	OnDone func(
+		ctx context.Context,
+		n int,
+	) (string, error)
+	Opts  func(...Option)
+	Pairs []struct{ K, V string }
+}
+`;
+
+const expected: Record<string, string> = {
+  In: '<-chan int',
+  Out: 'chan<- int',
+  Both: 'chan string',
+  Nested: 'chan<- <-chan int',
+  Index: 'map[string][]*User',
+  Raw: '[...]byte',
+  Buf: '[16]byte',
+  OnDone: 'func(ctx context.Context, n int) (string, error)',
+  Opts: 'func(...Option)',
+  Pairs: '[]struct{ K, V string }',
+};
+
+async function main() {
+  console.log('=== Go Type String Test ===\n');
+
+  const parser = new TreeSitterParser();
+  await parser.init(['go']);
+  const { tree } = parser.parse(source, 'go');
+  const { symbols } = new GoExtractor().extract(tree, source, 'go');
+
+  let failures = 0;
+  for (const [name, type] of Object.entries(expected)) {
+    const field = symbols.find(s => s.kind === 'field' && s.qualifiedName === `shapes.Pipes.${name}`);
+    const actual = field?.details?.type;
+    if (actual === type) {
+      console.log(`   ✓ ${name}: ${actual}`);
+    } else {
+      console.log(`   ✗ ${name}: expected "${type}", got "${actual}"`);
+      failures++;
+    }
+  }
+
+  console.log(failures === 0 ? '\n✅ All types rendered correctly' : `\n❌ ${failures} mismatches`);
+  process.exitCode = failures === 0 ? 0 : 1;
+}
+
+main().catch(console.error);
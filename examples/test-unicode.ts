@@ -0,0 +1,80 @@
+/**
+ * Test indexing and lookup of Unicode Go identifiers
+ */
+
+import { existsSync, unlinkSync } from 'fs';
+import { CodeIndex } from '../src/index.js';
+
+// café spelled with a combining acute accent (e + U+0301), as some editors write it
+const decomposed = 'cafe\u0301';
+
+const source = `package menu
+
+type Menü struct {
+	Größe int
+}
+
+func ${decomposed}() string { return "espresso" }
+
+func Café() {}
+
+// 名字 is not exported: CJK letters have no case
+func 名字() string { return "菜单" }
+
+func (m *Menü) 价格() int { return m.Größe }
+`;
+
+const lookups: Array<[string, boolean | null]> = [
+  // [qualified name as the user types it (NFC), expected exported; null = must not be found]
+  ['menu.café', false],
+  ['menu.Café', true],
+  ['menu.名字', false],
+  ['menu.Menü', true],
+  ['menu.Menü.Größe', true],
+  ['menu.Menü.价格', false],
+  ['menu.cafe', null],
+];
+
+async function main() {
+  console.log('=== Unicode Identifier Test ===\n');
+
+  const dbPath = '.codeindex/unicode.db';
+  if (existsSync(dbPath)) {
+    unlinkSync(dbPath);
+  }
+
+  const index = await CodeIndex.create({
+    rootDir: process.cwd(),
+    dbPath,
+    languages: ['go'],
+  });
+  index.indexSource('menu/menu.go', source);
+
+  let failures = 0;
+  for (const [qualifiedName, exported] of lookups) {
+    const symbol = await index.lookup(qualifiedName.normalize('NFC'));
+    const ok = exported === null ? symbol === null : symbol !== null && symbol.exported === exported;
+    console.log(`   ${ok ? '✓' : '✗'} ${qualifiedName}: ${symbol ? `exported=${symbol.exported}` : 'not found'}`);
+    if (!ok) failures++;
+  }
+
+  // the decomposed spelling finds the same symbol, and case-insensitive search folds non-ASCII letters
+  const viaDecomposed = await index.lookup(`menu.${decomposed}`);
+  const viaFind = await index.find('MENÜ');
+  const viaMethods = await index.methodsOf('Menu\u0308');
+  for (const [label, ok] of [
+    ['lookup with combining accent', viaDecomposed?.name === 'café'],
+    ['case-insensitive find MENÜ', viaFind.some(s => s.name === 'Menü')],
+    ['methodsOf with combining diaeresis', viaMethods.some(s => s.name === '价格')],
+  ] as const) {
+    console.log(`   ${ok ? '✓' : '✗'} ${label}`);
+    if (!ok) failures++;
+  }
+
+  console.log(failures === 0 ? '\n✅ Unicode identifiers indexed correctly' : `\n❌ ${failures} failures`);
+  process.exitCode = failures === 0 ? 0 : 1;
+
+  index.close();
+}
+
+main().catch(console.error);
@@ -6,23 +6,45 @@
 
 import { Command } from 'commander';
 import { CodeIndex } from '../index.js';
+import { diffIndexes, formatIndexDiff } from '../export/index-diff.js';
+import { breakingChanges } from '../export/api-compat.js';
+import { serve } from '../server/http-server.js';
+import type { IndexDocument } from '../export/json-exporter.js';
 import type { Language, SymbolKind } from '../core/types.js';
-import { existsSync, writeFileSync, readFileSync } from 'fs';
+import { existsSync, writeFileSync, readFileSync, createWriteStream } from 'fs';
 import { join } from 'path';
 
 const program = new Command();
 
+// Files that failed were logged as they happened and the rest are indexed,
+// so a run with failed files still completes
+function reportFailedFiles(error: unknown): void {
+  if (!(error instanceof AggregateError)) {
+    throw error;
+  }
+  console.error(error.message);
+}
+
+// The library only records the links a scan left alone; say so once per command
+function reportSkippedSymlinks(index: CodeIndex): void {
+  const skipped = index.skippedSymlinks();
+  if (skipped.length > 0) {
+    console.warn(`Skipped ${skipped.length} symbolic link(s) while scanning`);
+  }
+}
+
 // Simple progress bar helper
 function createProgressBar(total: number, label: string = 'Progress') {
   let current = 0;
   const barLength = 40;
   
-  const render = () => {
+  const render = (detail = '') => {
     const percent = Math.floor((current / total) * 100);
     const filled = Math.floor((current / total) * barLength);
     const empty = barLength - filled;
     const bar = '█'.repeat(filled) + '░'.repeat(empty);
-    process.stdout.write(`\r${label}: [${bar}] ${percent}% (${current}/${total})`);
+    // \x1b[K clears what a longer detail left on the line
+    process.stdout.write(`\r${label}: [${bar}] ${percent}% (${current}/${total})${detail ? ` ${detail}` : ''}\x1b[K`);
   };
   
   return {
@@ -33,9 +55,9 @@ function createProgressBar(total: number, label: string = 'Progress') {
         process.stdout.write('\n');
       }
     },
-    update: (value: number) => {
+    update: (value: number, detail?: string) => {
       current = value;
-      render();
+      render(current >= total ? '' : detail);
       if (current >= total) {
         process.stdout.write('\n');
       }
@@ -94,13 +116,33 @@ program
 program
   .command('index')
   .description('Build or rebuild the code index')
+  .option('--since <ref>', 'Only index files changed since this git revision (e.g. origin/main)')
   .option('--config <path>', 'Config file path', 'codeindex.config.json')
   .option('--root <dir>', 'Root directory to index')
   .option('--db <path>', 'Database path')
   .option('--lang <languages...>', 'Languages to index')
   .option('--include <patterns...>', 'Include patterns')
   .option('--exclude <patterns...>', 'Exclude patterns')
-  .option('--max-nested-depth <n>', 'Maximum depth for nested struct indexing')
+  .option('--ignore <patterns...>', 'gitignore-style patterns to skip (vendor/ and hidden directories are skipped by default; use !vendor/ to keep them)')
+  .option('--max-nested-depth <n>', 'Maximum depth for nested struct indexing (0 = unlimited)')
+  .option('--all-platforms', 'Index Go files for every platform, ignoring build constraints')
+  .option('--exported-only', 'Index only exported Go symbols')
+  .option('--field-alignment', 'Flag Go structs whose fields could be reordered to take less memory')
+  .option('--exclude-generated', 'Skip generated Go files (// Code generated ... DO NOT EDIT.)')
+  .option('--max-file-size <bytes>', 'Skip files larger than this many bytes (default 10MB, 0 = no limit)')
+  .option('--max-symbols-per-file <n>', 'Index at most this many symbols per file (default 0 = no limit)')
+  .option('--include-source', 'Store the source text of every symbol')
+  .option('--include-doc-in-source', 'With --include-source, include the comments above each declaration')
+  .option('--normalize-form <form>', 'Unicode normalization of identifiers: NFC (default), NFD, NFKC, NFKD or none')
+  .option('--follow-symlinks', 'Follow symbolic links while scanning (links forming a cycle are skipped)')
+  .option('--strict-parse', 'Fail on the first file with syntax errors instead of indexing what parsed')
+  .option('--include-comments', 'Record every comment with its position, for comment-preserving rewrites')
+  .option('--include-locals', 'Also index local variables, constants, function literals and labels in Go function bodies')
+  .option('--root-package <modulePath>', 'Go module path for full import paths of local types (default: read from go.mod)')
+  .option('--name-forms', 'Store snake_case forms of symbol names for convention-independent search')
+  .option('--complexity', 'Compute the cyclomatic complexity of Go functions and methods')
+  .option('--include-kinds <kinds...>', 'Only index symbols of these kinds (e.g. struct interface method)')
+  .option('--exclude-tests', 'Skip Go _test.go files')
   .action(async (options) => {
     try {
       const startTime = Date.now();
@@ -117,7 +159,7 @@ program
       const languages = options.lang || loadedConfig.languages || ['ts', 'js'];
       const include = options.include || loadedConfig.include || ['**/*'];
       const exclude = options.exclude || loadedConfig.exclude || ['**/node_modules/**', '**/dist/**', '**/.git/**'];
-      const maxNestedDepth = options.maxNestedDepth ? parseInt(options.maxNestedDepth) : (loadedConfig.maxNestedStructDepth || 3);
+      const maxNestedDepth = options.maxNestedDepth ? parseInt(options.maxNestedDepth) : (loadedConfig.maxNestedStructDepth ?? 3);
       
       const index = await CodeIndex.create({
         rootDir,
@@ -125,13 +167,45 @@ program
         languages: languages as Language[],
         include,
         exclude,
+        ignore: options.ignore || loadedConfig.ignore,
         maxNestedStructDepth: maxNestedDepth,
+        buildContext: loadedConfig.buildContext,
+        allPlatforms: options.allPlatforms || loadedConfig.allPlatforms,
+        concurrency: loadedConfig.concurrency,
+        exportedOnly: options.exportedOnly || loadedConfig.exportedOnly,
+        fieldAlignment: options.fieldAlignment || loadedConfig.fieldAlignment,
+        excludeGenerated: options.excludeGenerated || loadedConfig.excludeGenerated,
+        maxFileSize: options.maxFileSize ? parseInt(options.maxFileSize) : loadedConfig.maxFileSize,
+        maxSymbolsPerFile: options.maxSymbolsPerFile ? parseInt(options.maxSymbolsPerFile) : loadedConfig.maxSymbolsPerFile,
+        includeSource: options.includeSource || loadedConfig.includeSource,
+        includeDocInSource: options.includeDocInSource || loadedConfig.includeDocInSource,
+        normalizeForm: options.normalizeForm || loadedConfig.normalizeForm,
+        followSymlinks: options.followSymlinks || loadedConfig.followSymlinks,
+        strictParse: options.strictParse || loadedConfig.strictParse,
+        includeComments: options.includeComments || loadedConfig.includeComments,
+        includeLocals: options.includeLocals || loadedConfig.includeLocals,
+        modulePath: options.rootPackage || loadedConfig.modulePath,
+        nameForms: options.nameForms || loadedConfig.nameForms,
+        computeComplexity: options.complexity || loadedConfig.computeComplexity,
+        includeKinds: options.includeKinds || loadedConfig.includeKinds,
+        includeTests: options.excludeTests ? false : loadedConfig.includeTests,
       });
 
+      if (options.since) {
+        const delta = await index.indexChangedSince(options.since);
+        index.close();
+        const elapsed = ((Date.now() - startTime) / 1000).toFixed(2);
+        console.log(
+          `✓ Indexed changes since ${options.since}: ${delta.added.length} added, ` +
+            `${delta.removed.length} removed, ${delta.changed.length} changed (${elapsed}s)`
+        );
+        return;
+      }
+
       let progressBar: ReturnType<typeof createProgressBar> | null = null;
       let hasStarted = false;
       
-      await index.reindexAll((current, total) => {
+      await index.reindexAll((current, total, path) => {
         if (!progressBar && total > 0) {
           if (!hasStarted) {
             console.log(`Found ${total} files to index`);
@@ -140,9 +214,10 @@ program
           progressBar = createProgressBar(total, 'Indexing');
         }
         if (progressBar) {
-          progressBar.update(current);
+          progressBar.update(current, path);
         }
-      });
+      }).catch(reportFailedFiles);
+      reportSkippedSymlinks(index);
       
       if (!hasStarted) {
         console.log('No files to index');
@@ -168,7 +243,26 @@ program
   .option('--lang <languages...>', 'Languages to index')
   .option('--include <patterns...>', 'Include patterns')
   .option('--exclude <patterns...>', 'Exclude patterns')
-  .option('--max-nested-depth <n>', 'Maximum depth for nested struct indexing')
+  .option('--ignore <patterns...>', 'gitignore-style patterns to skip (vendor/ and hidden directories are skipped by default; use !vendor/ to keep them)')
+  .option('--max-nested-depth <n>', 'Maximum depth for nested struct indexing (0 = unlimited)')
+  .option('--all-platforms', 'Index Go files for every platform, ignoring build constraints')
+  .option('--exported-only', 'Index only exported Go symbols')
+  .option('--field-alignment', 'Flag Go structs whose fields could be reordered to take less memory')
+  .option('--exclude-generated', 'Skip generated Go files (// Code generated ... DO NOT EDIT.)')
+  .option('--max-file-size <bytes>', 'Skip files larger than this many bytes (default 10MB, 0 = no limit)')
+  .option('--max-symbols-per-file <n>', 'Index at most this many symbols per file (default 0 = no limit)')
+  .option('--include-source', 'Store the source text of every symbol')
+  .option('--include-doc-in-source', 'With --include-source, include the comments above each declaration')
+  .option('--normalize-form <form>', 'Unicode normalization of identifiers: NFC (default), NFD, NFKC, NFKD or none')
+  .option('--follow-symlinks', 'Follow symbolic links while scanning (links forming a cycle are skipped)')
+  .option('--strict-parse', 'Fail on the first file with syntax errors instead of indexing what parsed')
+  .option('--include-comments', 'Record every comment with its position, for comment-preserving rewrites')
+  .option('--include-locals', 'Also index local variables, constants, function literals and labels in Go function bodies')
+  .option('--root-package <modulePath>', 'Go module path for full import paths of local types (default: read from go.mod)')
+  .option('--name-forms', 'Store snake_case forms of symbol names for convention-independent search')
+  .option('--complexity', 'Compute the cyclomatic complexity of Go functions and methods')
+  .option('--include-kinds <kinds...>', 'Only index symbols of these kinds (e.g. struct interface method)')
+  .option('--exclude-tests', 'Skip Go _test.go files')
   .action(async (options) => {
     try {
       const startTime = Date.now();
@@ -185,7 +279,7 @@ program
       const languages = options.lang || loadedConfig.languages || ['ts', 'js'];
       const include = options.include || loadedConfig.include || ['**/*'];
       const exclude = options.exclude || loadedConfig.exclude || ['**/node_modules/**', '**/dist/**', '**/.git/**'];
-      const maxNestedDepth = options.maxNestedDepth ? parseInt(options.maxNestedDepth) : (loadedConfig.maxNestedStructDepth || 3);
+      const maxNestedDepth = options.maxNestedDepth ? parseInt(options.maxNestedDepth) : (loadedConfig.maxNestedStructDepth ?? 3);
       
       const index = await CodeIndex.create({
         rootDir,
@@ -193,14 +287,35 @@ program
         languages: languages as Language[],
         include,
         exclude,
+        ignore: options.ignore || loadedConfig.ignore,
         maxNestedStructDepth: maxNestedDepth,
+        buildContext: loadedConfig.buildContext,
+        allPlatforms: options.allPlatforms || loadedConfig.allPlatforms,
+        concurrency: loadedConfig.concurrency,
+        exportedOnly: options.exportedOnly || loadedConfig.exportedOnly,
+        fieldAlignment: options.fieldAlignment || loadedConfig.fieldAlignment,
+        excludeGenerated: options.excludeGenerated || loadedConfig.excludeGenerated,
+        maxFileSize: options.maxFileSize ? parseInt(options.maxFileSize) : loadedConfig.maxFileSize,
+        maxSymbolsPerFile: options.maxSymbolsPerFile ? parseInt(options.maxSymbolsPerFile) : loadedConfig.maxSymbolsPerFile,
+        includeSource: options.includeSource || loadedConfig.includeSource,
+        includeDocInSource: options.includeDocInSource || loadedConfig.includeDocInSource,
+        normalizeForm: options.normalizeForm || loadedConfig.normalizeForm,
+        followSymlinks: options.followSymlinks || loadedConfig.followSymlinks,
+        strictParse: options.strictParse || loadedConfig.strictParse,
+        includeComments: options.includeComments || loadedConfig.includeComments,
+        includeLocals: options.includeLocals || loadedConfig.includeLocals,
+        modulePath: options.rootPackage || loadedConfig.modulePath,
+        nameForms: options.nameForms || loadedConfig.nameForms,
+        computeComplexity: options.complexity || loadedConfig.computeComplexity,
+        includeKinds: options.includeKinds || loadedConfig.includeKinds,
+        includeTests: options.excludeTests ? false : loadedConfig.includeTests,
       });
 
       console.log('Clearing existing index...');
       let progressBar: ReturnType<typeof createProgressBar> | null = null;
       let hasStarted = false;
       
-      await index.rebuild((current, total) => {
+      await index.rebuild((current, total, path) => {
         if (!progressBar && total > 0) {
           if (!hasStarted) {
             console.log(`Found ${total} files to rebuild`);
@@ -209,9 +324,10 @@ program
           progressBar = createProgressBar(total, 'Rebuilding');
         }
         if (progressBar) {
-          progressBar.update(current);
+          progressBar.update(current, path);
         }
-      });
+      }).catch(reportFailedFiles);
+      reportSkippedSymlinks(index);
       
       if (!hasStarted) {
         console.log('No files to rebuild');
@@ -237,7 +353,26 @@ program
   .option('--lang <languages...>', 'Languages to index')
   .option('--include <patterns...>', 'Include patterns')
   .option('--exclude <patterns...>', 'Exclude patterns')
-  .option('--max-nested-depth <n>', 'Maximum depth for nested struct indexing')
+  .option('--ignore <patterns...>', 'gitignore-style patterns to skip (vendor/ and hidden directories are skipped by default; use !vendor/ to keep them)')
+  .option('--max-nested-depth <n>', 'Maximum depth for nested struct indexing (0 = unlimited)')
+  .option('--all-platforms', 'Index Go files for every platform, ignoring build constraints')
+  .option('--exported-only', 'Index only exported Go symbols')
+  .option('--field-alignment', 'Flag Go structs whose fields could be reordered to take less memory')
+  .option('--exclude-generated', 'Skip generated Go files (// Code generated ... DO NOT EDIT.)')
+  .option('--max-file-size <bytes>', 'Skip files larger than this many bytes (default 10MB, 0 = no limit)')
+  .option('--max-symbols-per-file <n>', 'Index at most this many symbols per file (default 0 = no limit)')
+  .option('--include-source', 'Store the source text of every symbol')
+  .option('--include-doc-in-source', 'With --include-source, include the comments above each declaration')
+  .option('--normalize-form <form>', 'Unicode normalization of identifiers: NFC (default), NFD, NFKC, NFKD or none')
+  .option('--follow-symlinks', 'Follow symbolic links while scanning (links forming a cycle are skipped)')
+  .option('--strict-parse', 'Fail on the first file with syntax errors instead of indexing what parsed')
+  .option('--include-comments', 'Record every comment with its position, for comment-preserving rewrites')
+  .option('--include-locals', 'Also index local variables, constants, function literals and labels in Go function bodies')
+  .option('--root-package <modulePath>', 'Go module path for full import paths of local types (default: read from go.mod)')
+  .option('--name-forms', 'Store snake_case forms of symbol names for convention-independent search')
+  .option('--complexity', 'Compute the cyclomatic complexity of Go functions and methods')
+  .option('--include-kinds <kinds...>', 'Only index symbols of these kinds (e.g. struct interface method)')
+  .option('--exclude-tests', 'Skip Go _test.go files')
   .option('--debounce <ms>', 'Debounce delay in milliseconds', '500')
   .option('--batch-interval <minutes>', 'Batch index interval in minutes', '10')
   .option('--min-change-lines <n>', 'Minimum lines changed to trigger indexing', '5')
@@ -256,7 +391,7 @@ program
       const languages = options.lang || loadedConfig.languages || ['ts', 'js'];
       const include = options.include || loadedConfig.include || ['**/*'];
       const exclude = options.exclude || loadedConfig.exclude || ['**/node_modules/**', '**/dist/**', '**/.git/**'];
-      const maxNestedDepth = options.maxNestedDepth ? parseInt(options.maxNestedDepth) : (loadedConfig.maxNestedStructDepth || 3);
+      const maxNestedDepth = options.maxNestedDepth ? parseInt(options.maxNestedDepth) : (loadedConfig.maxNestedStructDepth ?? 3);
       
       // 从配置文件读取 watcher 配置，CLI 参数优先
       const watcherConfig = loadedConfig.watcher || {};
@@ -270,7 +405,28 @@ program
         languages: languages as Language[],
         include,
         exclude,
+        ignore: options.ignore || loadedConfig.ignore,
         maxNestedStructDepth: maxNestedDepth,
+        buildContext: loadedConfig.buildContext,
+        allPlatforms: options.allPlatforms || loadedConfig.allPlatforms,
+        concurrency: loadedConfig.concurrency,
+        exportedOnly: options.exportedOnly || loadedConfig.exportedOnly,
+        fieldAlignment: options.fieldAlignment || loadedConfig.fieldAlignment,
+        excludeGenerated: options.excludeGenerated || loadedConfig.excludeGenerated,
+        maxFileSize: options.maxFileSize ? parseInt(options.maxFileSize) : loadedConfig.maxFileSize,
+        maxSymbolsPerFile: options.maxSymbolsPerFile ? parseInt(options.maxSymbolsPerFile) : loadedConfig.maxSymbolsPerFile,
+        includeSource: options.includeSource || loadedConfig.includeSource,
+        includeDocInSource: options.includeDocInSource || loadedConfig.includeDocInSource,
+        normalizeForm: options.normalizeForm || loadedConfig.normalizeForm,
+        followSymlinks: options.followSymlinks || loadedConfig.followSymlinks,
+        strictParse: options.strictParse || loadedConfig.strictParse,
+        includeComments: options.includeComments || loadedConfig.includeComments,
+        includeLocals: options.includeLocals || loadedConfig.includeLocals,
+        modulePath: options.rootPackage || loadedConfig.modulePath,
+        nameForms: options.nameForms || loadedConfig.nameForms,
+        computeComplexity: options.complexity || loadedConfig.computeComplexity,
+        includeKinds: options.includeKinds || loadedConfig.includeKinds,
+        includeTests: options.excludeTests ? false : loadedConfig.includeTests,
         batchIntervalMinutes, // 传递批量索引间隔
         minChangeLines, // 传递最小变更行数
       });
@@ -318,6 +474,7 @@ program
         rootDir,
         dbPath,
         languages: languages as Language[],
+        normalizeForm: loadedConfig.normalizeForm,
       });
 
       const symbols = await index.findSymbols({
@@ -352,6 +509,87 @@ program
     }
   });
 
+// Stats command
+program
+  .command('stats')
+  .description('Show an overview of the index: files, symbols by kind, exported symbols')
+  .option('--config <path>', 'Config file path', 'codeindex.config.json')
+  .option('--json', 'Output as JSON')
+  .option('--db <path>', 'Database path')
+  .action(async (options) => {
+    try {
+      // Load config file if present
+      const configPath = join(process.cwd(), options.config || 'codeindex.config.json');
+      const loadedConfig = existsSync(configPath)
+        ? JSON.parse(readFileSync(configPath, 'utf-8'))
+        : {};
+
+      const index = await CodeIndex.create({
+        rootDir: loadedConfig.rootDir || '.',
+        dbPath: options.db || loadedConfig.dbPath || '.codeindex/sqlite.db',
+        languages: (loadedConfig.languages || ['ts', 'js']) as Language[],
+        normalizeForm: loadedConfig.normalizeForm,
+      });
+
+      const stats = await index.stats();
+
+      if (options.json) {
+        console.log(JSON.stringify(stats, null, 2));
+      } else {
+        console.log(`Files:            ${stats.files}`);
+        console.log(`Symbols:          ${stats.symbols} (${stats.exported} exported, ${stats.unexported} unexported)`);
+        const byKind = Object.entries(stats.byKind) as Array<[string, number]>;
+        for (const [kind, count] of byKind.sort(([, a], [, b]) => b - a)) {
+          console.log(`  ${kind.padEnd(16)}${count}`);
+        }
+        console.log(`Lines covered:    ${stats.linesCovered}`);
+        console.log(`Max struct depth: ${stats.maxStructDepth}`);
+      }
+
+      index.close();
+    } catch (error) {
+      console.error('Error reading stats:', error);
+      process.exit(1);
+    }
+  });
+
+// Serve command
+program
+  .command('serve')
+  .description('Serve read-only JSON queries over HTTP (/symbols, /symbols/{qualifiedName}, /stats)')
+  .option('--config <path>', 'Config file path', 'codeindex.config.json')
+  .option('--db <path>', 'Database path')
+  .option('--port <port>', 'Port to listen on', '8080')
+  .option('--host <host>', 'Interface to bind', '127.0.0.1')
+  .action(async (options) => {
+    try {
+      // Load config file if present
+      const configPath = join(process.cwd(), options.config || 'codeindex.config.json');
+      const loadedConfig = existsSync(configPath)
+        ? JSON.parse(readFileSync(configPath, 'utf-8'))
+        : {};
+
+      const index = await CodeIndex.create({
+        rootDir: loadedConfig.rootDir || '.',
+        dbPath: options.db || loadedConfig.dbPath || '.codeindex/sqlite.db',
+        languages: (loadedConfig.languages || ['ts', 'js']) as Language[],
+        normalizeForm: loadedConfig.normalizeForm,
+      });
+
+      const server = await serve(index, { port: parseInt(options.port), host: options.host });
+      console.log(`Serving index on http://${options.host}:${options.port}`);
+
+      process.on('SIGINT', () => {
+        server.close();
+        index.close();
+        process.exit(0);
+      });
+    } catch (error) {
+      console.error('Error starting server:', error);
+      process.exit(1);
+    }
+  });
+
 // Call chain command
 program
   .command('call-chain')
@@ -379,6 +617,7 @@ program
         rootDir,
         dbPath,
         languages: languages as Language[],
+        normalizeForm: loadedConfig.normalizeForm,
       });
 
       const chain = await index.callChain({
@@ -408,6 +647,122 @@ program
     }
   });
 
+// Export command
+program
+  .command('export')
+  .description('Export the full index for use by other tools')
+  .option('--config <path>', 'Config file path', 'codeindex.config.json')
+  .option('--db <path>', 'Database path')
+  .option('--format <format>', 'Output format: json, ndjson, yaml, sqlite, dot, csv, fields-csv', 'json')
+  .option('--out <path>', 'Output file (defaults to stdout; required for sqlite)')
+  .action(async (options) => {
+    try {
+      // Load config file if present
+      const configPath = join(process.cwd(), options.config || 'codeindex.config.json');
+      const loadedConfig = existsSync(configPath)
+        ? JSON.parse(readFileSync(configPath, 'utf-8'))
+        : {};
+
+      const dbPath = options.db || loadedConfig.dbPath || '.codeindex/sqlite.db';
+      const rootDir = loadedConfig.rootDir || '.';
+      const languages = loadedConfig.languages || ['ts', 'js'];
+
+      if (!['json', 'ndjson', 'yaml', 'sqlite', 'dot', 'csv', 'fields-csv'].includes(options.format)) {
+        console.error(`Unsupported export format: ${options.format}`);
+        process.exit(1);
+      }
+      if (options.format === 'sqlite' && !options.out) {
+        console.error('--out is required for sqlite export');
+        process.exit(1);
+      }
+
+      const index = await CodeIndex.create({
+        rootDir,
+        dbPath,
+        languages: languages as Language[],
+        normalizeForm: loadedConfig.normalizeForm,
+      });
+
+      if (options.format === 'sqlite') {
+        index.exportSQLite(options.out);
+        console.log(`✓ Exported index to ${options.out}`);
+      } else {
+        const writers: Record<string, (out: NodeJS.WritableStream) => void | Promise<void>> = {
+          json: out => index.writeJSON(out),
+          ndjson: out => index.writeNDJSON(out),
+          yaml: out => index.writeYAML(out),
+          dot: out => index.writeDOT(out),
+          csv: out => index.writeCSV(out),
+          'fields-csv': out => index.writeFieldsCSV(out),
+        };
+        const write = writers[options.format];
+
+        if (options.out) {
+          const out = createWriteStream(options.out);
+          await write(out);
+          await new Promise<void>((resolve, reject) => {
+            out.on('error', reject);
+            out.end(resolve);
+          });
+          console.log(`✓ Exported index to ${options.out}`);
+        } else {
+          await write(process.stdout);
+        }
+      }
+
+      index.close();
+    } catch (error) {
+      console.error('Error during export:', error);
+      process.exit(1);
+    }
+  });
+
+// Diff command
+program
+  .command('diff <old> <new>')
+  .description('Compare two JSON index snapshots (from `export --format json`)')
+  .option('--json', 'Output the diff as JSON')
+  .option('--exit-code', 'Exit with status 1 when the snapshots differ')
+  .option('--breaking', 'Classify exported API changes; with --exit-code, fail only on breaking ones')
+  .action((oldPath: string, newPath: string, options) => {
+    try {
+      const load = (path: string) => JSON.parse(readFileSync(path, 'utf-8')) as IndexDocument;
+
+      if (options.breaking) {
+        const changes = breakingChanges(load(oldPath), load(newPath));
+        if (options.json) {
+          console.log(JSON.stringify(changes, null, 2));
+        } else {
+          for (const change of changes) {
+            const marker = change.breaking ? '✗ BREAKING' : '✓';
+            console.log(`${marker} ${change.change} ${change.kind} ${change.qualifiedName}: ${change.reason}`);
+            if (change.before) console.log(`    - ${change.before}`);
+            if (change.after) console.log(`    + ${change.after}`);
+          }
+        }
+        if (options.exitCode && changes.some(change => change.breaking)) {
+          process.exit(1);
+        }
+        return;
+      }
+
+      const diff = diffIndexes(load(oldPath), load(newPath));
+
+      if (options.json) {
+        console.log(JSON.stringify(diff, null, 2));
+      } else {
+        process.stdout.write(formatIndexDiff(diff));
+      }
+
+      if (options.exitCode && diff.files.length > 0) {
+        process.exit(1);
+      }
+    } catch (error) {
+      console.error('Error during diff:', error);
+      process.exit(2);
+    }
+  });
+
 // Summarize command
 program
   .command('summarize')
@@ -528,6 +883,7 @@ program
         rootDir,
         dbPath,
         languages: languages as Language[],
+        normalizeForm: loadedConfig.normalizeForm,
       });
 
       const properties = await index.objectProperties({ 
@@ -646,6 +1002,7 @@ program
         rootDir: resolvedRoot,
         dbPath: resolvedDb,
         languages: languages as Language[],
+        normalizeForm: loadedConfig.normalizeForm,
       });
 
       // Generate embeddings
@@ -813,6 +1170,7 @@ program
         rootDir: resolvedRoot,
         dbPath: resolvedDb,
         languages: languages as Language[],
+        normalizeForm: loadedConfig.normalizeForm,
       });
 
       console.log(`🔍 Searching for: "${query}"\n`);
@@ -2,6 +2,9 @@
  * Core data types for the code indexing system
  */
 
+import type { SourceFileSystem } from '../indexer/source-fs.js';
+import type { FileSet } from '../indexer/file-set.js';
+
 export type Language = 'ts' | 'tsx' | 'js' | 'jsx' | 'python' | 'go' | 'java' | 'rust' | 'html';
 
 export type SymbolKind = 
@@ -16,7 +19,8 @@ export type SymbolKind =
   | 'field'
   | 'module'
   | 'namespace'
-  | 'type';
+  | 'type'
+  | 'label';
 
 export type ReferenceKind = 
   | 'call'
@@ -27,6 +31,10 @@ export type ReferenceKind =
   | 'extend'
   | 'implement';
 
+/**
+ * Source ranges use 1-based lines and 1-based columns; the end position
+ * points just past the last character of the declaration.
+ */
 export interface Location {
   fileId: number;
   path: string;
@@ -43,6 +51,60 @@ export interface FileRecord {
   contentHash: string;
   mtime: number;
   size: number;
+  packageName?: string; // Go package clause
+  isGenerated?: boolean; // 含 `// Code generated ... DO NOT EDIT.` 标记的 Go 生成文件
+  isTest?: boolean; // Go 测试文件（_test.go）
+  isExternalTest?: boolean; // 属于外部测试包（package foo_test）的 _test.go 文件
+  packagePath?: string; // Go 包的导入路径（模块路径 + 目录），外部测试包加 _test 后缀；不知道模块路径时为相对 rootDir 的目录。区分同名的包，例如两个 util
+}
+
+export interface ImportRecord {
+  importId?: number;
+  fileId: number;
+  path: string;
+  alias?: string; // 导入别名；_ 和 . 也记录在这里
+  isBlank?: boolean; // import _ "pkg"
+  isDot?: boolean; // import . "pkg"
+}
+
+export interface Directive {
+  name: string; // 指令名，如 go:generate、go:embed、nolint、line
+  args: string; // 指令参数原文，没有参数时为空字符串
+  line: number; // 指令注释所在行（1-based）
+  symbol?: string; // 指令所附着声明的 qualifiedName
+}
+
+export interface FileDirective extends Directive {
+  path: string; // 指令所在文件
+}
+
+export interface ParseError {
+  line: number; // 1-based
+  column: number; // 1-based
+  message: string; // 例如 'unexpected "func ("'、'missing }'
+}
+
+export interface FileParseError extends ParseError {
+  path: string; // 出错的文件
+}
+
+/**
+ * A comment as written, with its source range (same convention as Location)
+ */
+export interface Comment {
+  startLine: number;
+  startCol: number;
+  endLine: number;
+  endCol: number;
+  text: string; // 原文，包括 // 或 /* */
+}
+
+export interface CommentGroup {
+  startLine: number; // 第一条注释的起始位置
+  startCol: number;
+  endLine: number; // 最后一条注释的结束位置
+  endCol: number;
+  comments: Comment[]; // 相邻且中间没有代码和空行的注释，按源码顺序
 }
 
 export interface SymbolRecord {
@@ -62,6 +124,139 @@ export interface SymbolRecord {
   chunkSummary?: string;
   summaryTokens?: number;
   summarizedAt?: number;
+  details?: SymbolDetails;
+}
+
+/**
+ * Structured, language-specific details attached to a symbol.
+ * Persisted as JSON alongside the symbol row.
+ */
+export interface SymbolDetails {
+  type?: string; // 结构体字段或显式声明类型的 var/const 的类型，嵌入字段保留指针，例如 '*Person'
+  inlineStruct?: InlineStruct; // 结构体字段或 var 的类型为匿名结构体（或其指针、切片、数组）时的字段结构
+  typeRef?: TypeRef; // 字段类型所指的命名类型（穿过指针、切片、数组、通道），预声明类型、map、func 等没有
+  isEmbedded?: boolean; // 匿名嵌入字段，name 为类型的基础名：Person、*Person、sync.Mutex、*bytes.Buffer、Box[T] 分别为 Person、Person、Mutex、Buffer、Box；type 保留限定名和指针，typeRef 记录导入路径
+  isPointer?: boolean; // 以指针形式嵌入，例如 *bytes.Buffer
+  fieldIndex?: number; // 字段在结构体中的声明顺序（从 0 开始），X, Y float64 展开为两个字段
+  tags?: Record<string, string>; // Go struct tag，例如 `json:"id"` -> { json: 'id' }
+  typeParams?: TypeParam[]; // Go 泛型类型参数
+  receiverType?: string; // 方法接收者的基础类型名，已去掉指针和类型实参，例如 (s *Stack[T]) -> 'Stack'
+  isPointerReceiver?: boolean; // 方法是否为指针接收者
+  receiverTypeParams?: string[]; // 泛型接收者的类型参数名，例如 (s *Stack[T]) -> ['T']；同 receiver.typeParams，为兼容保留
+  receiver?: Receiver; // 方法接收者的完整信息，可据此还原方法声明
+  packagePath?: string; // Go 符号所在包的导入路径，同 FileRecord.packagePath
+  scope?: string; // 局部符号（includeLocals）所在函数、方法或函数字面量的 qualifiedName，例如 'example.UserService.GetUser'
+  orphanReceiver?: boolean; // 方法的接收者类型不在本包中声明（不落库，由 indexPackage 和 orphanMethods 按整个包计算）
+  typeKind?: 'struct' | 'interface' | 'defined'; // Go 类型声明右侧的形态：struct、interface，其余（type Celsius float64、函数类型等）为 defined
+  isAlias?: boolean; // type A = B 形式的类型别名
+  underlying?: string; // 别名的右侧类型，或 defined 类型的底层类型，例如 'map[string]struct{}'
+  embeddedInterfaces?: string[]; // 接口中直接嵌入的接口，例如 ['io.Reader', 'Closer']（不展开）
+  truncated?: boolean; // 嵌套结构体因深度限制未被完整索引
+  params?: Param[]; // 函数/方法参数
+  results?: Param[]; // 函数/方法返回值
+  hasBody?: boolean; // Go 函数/方法是否有函数体；没有函数体的是由汇编（.s 文件）或 //go:linkname 提供实现的声明，例如 func add(a, b int) int
+  panics?: boolean; // Go 函数/方法体中（含其中的函数字面量）调用了内置的 panic，被 recover 捕获的也算
+  returnsError?: boolean; // Go 函数/方法的返回值中有 error 类型
+  cgoExport?: string; // 以 cgo 的 //export 指令导出给 C 的函数，值为导出的 C 名称
+  cgo?: boolean; // 来自 import "C" 的 cgo 文件；C.xxx 引用的 typeRef 以 'C' 为导入路径
+  cyclomatic?: number; // 函数/方法的圈复杂度：1 + if、for、非 default 的 case、&&、|| 的个数（仅 computeComplexity 选项开启时记录）
+  bodyRefs?: string[]; // 函数/方法体中用到的非局部名称（未解析），已去重排序，例如 ['User', 'ValidateEmail', 'fmt.Sprintf']
+  doc?: string; // 紧邻声明之前的文档注释，已去掉 // 标记，保留换行；结构体字段以外的声明没有前置注释时取行尾注释
+  comment?: string; // 结构体字段的行尾注释，例如 Address Address // 命名类型的嵌套 -> '命名类型的嵌套'
+  deprecated?: boolean; // 文档注释中有以 Deprecated: 开头的段落（Go 约定）
+  deprecationNote?: string; // Deprecated: 之后的说明文字，多行以空格连接
+  groupDoc?: string; // 分组声明 const (...) / var (...) / type (...) 整体的文档注释
+  initRefs?: string[]; // 包级变量初始化表达式中用到的名称（未解析），已去重排序，与 bodyRefs 同规则
+  initDeps?: string[]; // 包级变量初始化所依赖的本包变量名，含经由所调用函数间接读取的（仅在 indexPackage 结果中计算）
+  value?: string; // 常量的值表达式原文，例如 '1000'、'iota'（省略值时为继承的表达式）；变量为初始化表达式原文
+  typeInferred?: boolean; // 变量未声明类型，type 由初始化表达式推断而来，例如 var s = NewUserService() -> '*UserService'
+  untyped?: boolean; // 无类型常量，例如 const MaxUsers = 1000；有类型的常量（显式声明、类型转换、引用有类型常量或 len/cap/unsafe.Sizeof 等内建函数的结果，包括 iota 枚举中沿用的类型）记录在 type 中
+  defaultType?: string; // 无类型常量在需要类型时取的默认类型，例如 1000 -> 'int'、3.14 -> 'float64'；无法判断时没有
+  valueKnown?: boolean; // 常量的值能否静态确定
+  intValue?: number; // 可折叠的整数常量的值，例如 iota 枚举 0, 1, 2
+  stableId?: string; // 跨索引运行稳定的符号 ID，由包目录、kind、qualifiedName 派生，与位置无关（见 indexer/symbol-id.ts）
+  signatureHash?: string; // 声明的规范化哈希，与位置、注释、格式无关（见 indexer/symbol-hash.ts）
+  buildConstraint?: string; // 所在文件的构建约束（仅 allPlatforms 模式下记录），例如 'linux && amd64'
+  fieldAlignment?: FieldAlignmentWarning; // 字段重排可减小结构体大小（仅 fieldAlignment 选项开启时记录）
+  generated?: boolean; // 来自生成文件（见 FileRecord.isGenerated）
+  testScope?: boolean; // 来自 _test.go 文件，只在 go test 时编译
+  inMain?: boolean; // 声明在 package main 中（命令而非库）
+  source?: string; // 符号的源码原文，保留缩进和制表符（仅 includeSource 选项开启时记录）
+  metadata?: Record<string, unknown>; // SymbolPostProcessor 附加的自定义数据，索引本身不解读
+  testKind?: TestKind; // _test.go 中 go test 会运行的函数：TestXxx(t *testing.T)、BenchmarkXxx(b *testing.B)、FuzzXxx(f *testing.F)、ExampleXxx()
+}
+
+export type TestKind = 'test' | 'benchmark' | 'fuzz' | 'example';
+
+/**
+ * A struct whose fields, reordered, would take less memory (64-bit sizes)
+ */
+export interface FieldAlignmentWarning {
+  size: number; // 当前字段顺序下的大小（字节）
+  optimalSize: number; // 按对齐从大到小重排后的大小
+  optimalOrder: string[]; // 建议的字段顺序
+}
+
+export interface TypeRef {
+  name: string; // 类型名，例如 http.Client -> 'Client'
+  pkgQualifier?: string; // 包限定符，例如 'http'；本包类型没有
+  importPath?: string; // 按所在文件的 import 解析出的导入路径，例如 'net/http'；本包类型在已知模块路径时为本包的完整导入路径；无法解析时没有
+  isLocal: boolean; // 是否为本包声明的类型（未限定的名称）
+}
+
+export interface Receiver {
+  name: string; // 接收者变量名，例如 (p Point) -> 'p'；省略时为空字符串
+  type: string; // 去掉指针的接收者类型原文，例如 (s *Stack[T]) -> 'Stack[T]'
+  isPointer: boolean;
+  typeParams?: string[]; // 泛型接收者的类型参数名，例如 (s *Stack[T]) -> ['T']
+}
+
+export interface Param {
+  name: string; // 未命名参数或返回值为空字符串
+  type: string; // 类型原文，例如 []*User；可变参数为元素类型
+  isVariadic?: boolean;
+  inlineStruct?: InlineStruct; // 类型为匿名结构体（或其指针、切片、数组）时的字段结构
+}
+
+/**
+ * An anonymous struct type written in place, e.g. `func f(opts struct{ A int })`
+ * or `var cfg struct{ ... }`. Nested anonymous structs follow the same
+ * maxNestedStructDepth limit as nested struct fields.
+ */
+export interface InlineStruct {
+  fields: InlineField[];
+  truncated?: boolean; // 超出 maxNestedStructDepth，字段未展开
+}
+
+export interface InlineField {
+  name: string; // 嵌入字段为类型的基础名
+  type: string;
+  isEmbedded?: boolean;
+  isPointer?: boolean; // 以指针形式嵌入
+  tags?: Record<string, string>;
+  inlineStruct?: InlineStruct; // 字段本身是匿名结构体时的嵌套结构
+}
+
+/**
+ * One anonymous struct shape with every place it is written
+ */
+export interface AnonymousShape {
+  shape: string; // 规范化的结构体类型：字段名和类型按顺序，不含 tag，例如 'struct{ Name string; Email string }'
+  fields: InlineField[]; // 第一次出现时的字段（含 tag）
+  occurrences: ShapeOccurrence[];
+}
+
+export interface ShapeOccurrence {
+  path: string; // 所在文件（相对 rootDir）
+  qualifiedName: string; // 以该结构体为类型的字段、变量，或参数所属的函数/方法
+  startLine: number; // 该符号的起始位置
+  startCol: number;
+  param?: string; // 出现在参数或返回值中时为其名称，未命名的为 'param 0'、'result 1' 等
+}
+
+export interface TypeParam {
+  name: string;
+  constraint: string; // 约束原文，例如 any、comparable、~int | ~string
 }
 
 export interface CallRecord {
@@ -75,6 +270,17 @@ export interface CallRecord {
   siteEndCol: number;
 }
 
+export interface CallEdgeRecord {
+  edgeId?: number;
+  callerSymbolId: number;
+  calleeQualifiedName: string; // 被调用方的限定名，查询时再解析，被调用方所在文件可晚于调用方索引
+  siteFileId: number;
+  siteStartLine: number;
+  siteStartCol: number;
+  siteEndLine: number;
+  siteEndCol: number;
+}
+
 export interface ReferenceRecord {
   refId?: number;
   fromFileId: number;
@@ -86,16 +292,127 @@ export interface ReferenceRecord {
   refKind: ReferenceKind;
 }
 
+export type NormalizeForm = 'NFC' | 'NFD' | 'NFKC' | 'NFKD' | 'none';
+
 export interface IndexOptions {
   rootDir: string;
   dbPath: string;
   languages: Language[];
   include?: string[];
   exclude?: string[];
-  concurrency?: number;
-  maxNestedStructDepth?: number; // 嵌套结构体的最大索引深度，默认为 3
+  ignore?: string[]; // gitignore 风格的忽略规则（相对 rootDir），在 include/exclude 之后生效，后出现的规则优先，!pattern 可重新包含
+  defaultIgnores?: boolean; // 是否默认跳过 vendor/ 和隐藏目录（.git 等），默认 true；也可在 ignore 中用 !vendor/ 单独恢复
+  concurrency?: number; // 并发读取文件数，默认为 CPU 核数
+  maxNestedStructDepth?: number; // 嵌套结构体的最大索引深度，默认为 3，0 表示不限制
   batchIntervalMinutes?: number; // 批量索引间隔（分钟），默认 10
   minChangeLines?: number; // 最小变更行数才触发索引，默认 5
+  buildContext?: BuildContext; // Go 构建上下文，默认为当前主机的 GOOS/GOARCH
+  allPlatforms?: boolean; // 忽略构建约束索引所有 Go 文件，并在符号上记录约束
+  exportedOnly?: boolean; // 只索引导出的 Go 符号：未导出的类型连同其字段/方法一起跳过；嵌入字段始终保留，因为即使类型未导出也会提升其导出方法
+  fieldAlignment?: boolean; // 分析 Go 结构体字段排列，可通过重排减小内存占用时在结构体上记录 fieldAlignment
+  excludeGenerated?: boolean; // 完全跳过 Go 生成文件（// Code generated ... DO NOT EDIT.）
+  normalizeForm?: NormalizeForm; // 标识符的 Unicode 规范化形式，索引和查询前都会规范化，默认 NFC；'none' 表示按原样比较
+  followSymlinks?: boolean; // 是否跟随符号链接，默认 false；跟随时每个真实目录只遍历一次，避免链接成环
+  fs?: SourceFileSystem; // 读取源文件所用的文件系统，默认为 rootDir 下的本地文件系统；watch 只支持本地文件系统
+  fileSet?: FileSet; // 与其他工具共用的 FileSet，tokenRange 给出的位置都相对于它；不提供时自建一个。不同 FileSet 的位置不可比较，多个索引共用位置时须传入同一个
+  includeSource?: boolean; // 在 details.source 中保存每个符号的源码原文，默认关闭以节省空间
+  includeDocInSource?: boolean; // includeSource 开启时，源码连同紧邻其上的注释一起保存
+  maxSymbolsPerFile?: number; // 单个文件最多索引的符号数，达到后停止提取该文件并记入 warnings，默认 0 表示不限制
+  maxFileSize?: number; // 单个文件的最大字节数，更大的文件不解析并记入 skippedFiles，默认 10MB，0 表示不限制
+  includeTests?: boolean; // 是否索引 Go 的 _test.go 文件，默认 true；其中的符号带 details.testScope，文件带 isTest
+  includeKinds?: SymbolKind[]; // 只索引这些类型的符号，为空表示全部；Go 提取器直接跳过其余声明。方法按接收者类型名关联，不依赖类型本身被索引
+  computeComplexity?: boolean; // 计算 Go 函数/方法的圈复杂度，记录在 details.cyclomatic
+  nameForms?: boolean; // 为每个符号名额外保存 snake_case 检索形式（GetUserByEmail -> get_user_by_email），供 findNormalized 使用；开启后需 rebuild 才覆盖已索引的文件
+  modulePath?: string; // Go 模块路径，例如 'github.com/me/proj'，本包类型引用的 typeRef 据此带上完整 importPath；未设置时读取 rootDir 下的 go.mod
+  includeComments?: boolean; // 保存每个文件的全部注释及其位置（按注释组），供 comments 查询；与文档注释的提取互不影响
+  includeLocals?: boolean; // 同时索引 Go 函数体内的局部变量、常量、赋给变量的函数字面量和标签，挂在所在函数之下（details.scope）；会明显增加符号数量，默认关闭
+  strictParse?: boolean; // 遇到有语法错误的文件立即以 SourceSyntaxError 失败；默认索引能解析的部分并记录 parseErrors
+}
+
+/**
+ * Called after each file of an indexing run with the number of files done,
+ * the number found by the initial scan and the path (relative to rootDir)
+ * of the file just processed. Files may be read in parallel, but they are
+ * stored and reported one at a time, in path order, so calls never overlap.
+ */
+export type IndexProgress = (current: number, total: number, path: string) => void;
+
+export interface IndexRunOptions {
+  signal?: AbortSignal; // 中止信号：在两个文件之间检查，中止后以 signal.reason（默认为 AbortError）拒绝
+  keepPartial?: boolean; // 中止时保留已索引的文件；默认先读完全部文件再在一个事务中写入，中止时不做任何修改，索引保持运行前的状态
+}
+
+export interface SkippedSymlink {
+  path: string; // 符号链接路径（相对 rootDir）
+  target: string; // 链接指向的真实路径；断开的链接为链接内容
+  reason: 'not-followed' | 'already-visited' | 'broken'; // 未开启 followSymlinks / 目标目录已遍历过（包括会形成环的链接）/ 目标不存在
+}
+
+export interface IndexWarning {
+  path: string; // 相对 rootDir
+  message: string; // 例如 'stopped at maxSymbolsPerFile (50000 symbols); later declarations were not indexed'
+}
+
+export interface SkippedFile {
+  path: string; // 相对 rootDir
+  size: number; // 文件字节数
+  reason: string; // 跳过原因，例如 'exceeds maxFileSize (10485760 bytes)'
+}
+
+export interface BuildContext {
+  goos: string;
+  goarch: string;
+  tags?: string[]; // 额外启用的 build tag，例如 ['integration']
+  enableCgo?: boolean; // 是否满足 cgo 约束（对应 CGO_ENABLED）；默认上下文除 CGO_ENABLED=0 外为 true，自定义上下文默认 false
+}
+
+export interface IndexDelta {
+  added: SymbolRecord[];
+  removed: SymbolRecord[];
+  changed: SymbolRecord[]; // 位置、签名或 details 发生变化的符号（新记录）
+}
+
+/**
+ * What refreshAll did to each file, alongside the symbol changes. Paths are
+ * relative to rootDir and sorted, and a file is in at most one of reparsed,
+ * unchanged, deleted and errors.
+ */
+export interface RefreshReport extends IndexDelta {
+  reparsed: string[]; // 新增或内容有变、被重新解析的文件（包括因构建约束等被排除出索引的）
+  unchanged: string[]; // mtime 未变，或 mtime 变了但内容相同、未重新解析的文件
+  deleted: string[]; // 磁盘上已不存在、从索引中删除的文件
+  errors: Record<string, Error>; // 刷新失败的文件及其错误，这些文件在索引中保持原样
+}
+
+/**
+ * Called once per symbol by Indexer.indexStream; path is relative to rootDir.
+ * Throwing (or rejecting) stops the stream and the error is rethrown.
+ */
+export type SymbolVisitor = (
+  symbol: Omit<SymbolRecord, 'fileId' | 'symbolId'>,
+  path: string
+) => void | Promise<void>;
+
+/**
+ * Custom rule run on every extracted symbol before it is stored, registered
+ * with CodeIndex.use. It may change the symbol in place; custom data goes in
+ * details.metadata. path is relative to rootDir. Throwing fails the file the
+ * symbol came from, like a parse error.
+ */
+export interface SymbolPostProcessor {
+  process(symbol: Omit<SymbolRecord, 'fileId' | 'symbolId'>, path: string): void;
+}
+
+export interface PackageIndex {
+  dir: string;
+  packageName: string;
+  packagePath: string; // 包的导入路径，同 FileRecord.packagePath
+  files: string[]; // 包内文件（相对 rootDir）
+  imports: string[]; // 所有文件 import 路径的并集，已排序
+  symbols: SymbolRecord[];
+  parseErrors: FileParseError[]; // 包内文件的语法错误，按文件和位置排序
+  inits: SymbolRecord[]; // 包内全部 init 函数（可有多个），按文件和位置排序
+  initOrder: SymbolRecord[]; // 包级变量的初始化顺序：依赖（details.initDeps）在前，其余按文件和位置；循环依赖的变量排在最后
 }
 
 export interface QuerySymbolOptions {
@@ -105,6 +422,70 @@ export interface QuerySymbolOptions {
   kind?: SymbolKind;
 }
 
+export interface Conflict {
+  qualifiedName: string;
+  dir: string; // 冲突所在的包目录（相对 rootDir）
+  symbols: Array<{ path: string; symbol: SymbolRecord }>; // 所有重复声明，按索引顺序
+}
+
+export interface Implementer {
+  type: SymbolRecord; // 满足接口的具体类型
+  pointer: boolean; // 只有 *T 满足接口（需要指针接收者方法）时为 true；T 满足时 *T 必然也满足
+}
+
+export interface PromotedMember {
+  member: SymbolRecord; // 被提升的字段或方法
+  depth: number; // 嵌入深度，直接嵌入的类型的成员为 1
+  via: string[]; // 经过的嵌入字段名，例如 Employee 嵌入 Person -> ['Person']
+}
+
+export interface MergeConflict {
+  dir: string; // 被多个索引重复索引的包目录（相对合并后的 rootDir）
+  roots: string[]; // 索引了该目录的各个 rootDir，按传入顺序；合并结果保留第一个的内容
+}
+
+export interface FindOptions {
+  kinds?: SymbolKind[]; // 只返回这些类型的符号，为空表示不限制
+  caseSensitive?: boolean; // 名称匹配是否区分大小写，默认不区分
+}
+
+export interface UnusedExportedOptions {
+  kinds?: SymbolKind[]; // 只检查这些类型的符号，默认为所有 Go 声明（函数、方法、类型、常量、变量、字段）
+  includeTestFiles?: boolean; // 是否检查 _test.go 中声明的导出符号（测试辅助函数等），默认不检查
+}
+
+export interface SymbolPage {
+  symbols: SymbolRecord[]; // 当前页
+  total: number; // 所有页的匹配总数
+}
+
+export interface ScoredSymbol {
+  symbol: SymbolRecord;
+  score: number; // 排序所用的总分，越高越好：matchScore 加上 ScoreConfig 的加减分
+  matchScore: number; // 仅名称匹配的得分（fuzzyScore），便于调用方按自己的权重重新排序
+}
+
+/**
+ * Weights fuzzyFind adds to a name's match score. A character matched at a
+ * word start is worth about 26 points, so the defaults break ties between
+ * similar matches without lifting a poor match over a good one.
+ */
+export interface ScoreConfig {
+  exportedBoost?: number; // 导出符号的加分，默认 10
+  kindBoosts?: Partial<Record<SymbolKind, number>>; // 按 kind 加分，与默认值合并；默认类型 8、函数 6、方法 4，其余 0
+  testPenalty?: number; // _test.go 中符号（details.testScope）的扣分，默认 0
+}
+
+export interface IndexStats {
+  files: number;
+  symbols: number;
+  byKind: Partial<Record<SymbolKind, number>>; // 各类符号的数量，例如 { struct: 12, method: 40 }
+  exported: number;
+  unexported: number;
+  linesCovered: number; // 被符号覆盖的源码行数，嵌套符号（字段、类方法等）不重复计算
+  maxStructDepth: number; // Go 匿名嵌套结构体的最深层数，没有嵌套时为 0
+}
+
 export interface CallChainOptions {
   from: number; // symbolId
   direction?: 'forward' | 'backward';
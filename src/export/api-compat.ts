@@ -0,0 +1,230 @@
+/**
+ * Classification of exported API changes between two JSON index snapshots
+ *
+ * Symbols are matched across the whole snapshot by kind and qualified name,
+ * so moving a declaration to another file is not a change. Only the exported
+ * surface is considered: an exported symbol whose enclosing type (struct of a
+ * field, receiver of a method) is exported too.
+ *
+ * Breaking:
+ *   - removing an exported symbol, including a field or interface method
+ *   - changing the parameter, result or type parameter types of a function
+ *     or method, or making a value receiver a pointer receiver
+ *   - changing the type of a field, var or const, or the underlying type of
+ *     a defined type or alias
+ *   - adding a method to an exported interface (existing implementations no
+ *     longer satisfy it)
+ *
+ * Not breaking:
+ *   - adding an exported symbol or struct field
+ *   - renaming parameters or a receiver, which is not even reported
+ *   - changing struct tags or const values
+ *   - making a pointer receiver a value receiver (the method set only grows)
+ */
+
+import { canonicalSignature, symbolToString } from '../query/symbol-string.js';
+import { symbolKindOf } from './json-exporter.js';
+import type { ExportedSymbolKind, IndexDocument, SymbolDocument } from './json-exporter.js';
+import type { Language, Param, TypeParam } from '../core/types.js';
+
+export type ApiChangeKind = 'added' | 'removed' | 'changed';
+
+export interface ApiChange {
+  change: ApiChangeKind;
+  breaking: boolean;
+  kind: ExportedSymbolKind;
+  qualifiedName: string;
+  path: string; // 变更后所在文件；删除的符号为删除前所在文件
+  reason: string; // 例如 'parameter types changed'
+  before?: string; // 变更前的声明（symbolToString）
+  after?: string; // 变更后的声明
+}
+
+interface ApiSymbol {
+  doc: SymbolDocument;
+  path: string;
+  language: string;
+}
+
+/**
+ * Exported symbols of a snapshot keyed by kind + qualified name
+ */
+function exportedSurface(index: IndexDocument): Map<string, ApiSymbol> {
+  const all = new Map<string, ApiSymbol>();
+  const byName = new Map<string, SymbolDocument>();
+
+  for (const file of index.files) {
+    const visit = (docs: SymbolDocument[]) => {
+      for (const doc of docs) {
+        all.set(`${doc.kind}\u0000${doc.qualifiedName}`, { doc, path: file.path, language: file.language });
+        byName.set(doc.qualifiedName, doc);
+        visit(doc.fields ?? []);
+      }
+    };
+    visit(file.symbols);
+  }
+
+  const surface = new Map<string, ApiSymbol>();
+  for (const [key, symbol] of all) {
+    const { doc } = symbol;
+    const parent = byName.get(parentNameOf(doc));
+    const isMember = doc.kind === 'field' || doc.kind === 'method';
+    if (doc.exported && (!isMember || !parent || parent.exported)) {
+      surface.set(key, symbol);
+    }
+  }
+  return surface;
+}
+
+function declarationOf({ doc, language }: ApiSymbol, canonical = false): string {
+  if (language === 'go' && (doc.kind === 'struct' || doc.kind === 'interface')) {
+    return `type ${doc.name} ${doc.kind}`;
+  }
+  return (canonical ? canonicalSignature : symbolToString)({
+    language: language as Language,
+    kind: symbolKindOf(doc.kind),
+    name: doc.name,
+    qualifiedName: doc.qualifiedName,
+    signature: doc.signature,
+    details: doc.details,
+  });
+}
+
+function sameTypes(a: Param[] = [], b: Param[] = []): boolean {
+  return a.length === b.length && a.every((param, i) => param.type === b[i].type && !param.isVariadic === !b[i].isVariadic);
+}
+
+function sameTypeParams(a: TypeParam[] = [], b: TypeParam[] = []): boolean {
+  return a.length === b.length && a.every((param, i) => param.constraint === b[i].constraint);
+}
+
+function parentNameOf(doc: SymbolDocument): string {
+  return doc.qualifiedName.slice(0, doc.qualifiedName.lastIndexOf('.'));
+}
+
+/**
+ * A method of an interface that exists in both snapshots; adding one breaks
+ * every implementation outside the package
+ */
+function isMethodOfExistingInterface(doc: SymbolDocument, before: Map<string, ApiSymbol>, after: Map<string, ApiSymbol>): boolean {
+  const interfaceKey = `interface\u0000${parentNameOf(doc)}`;
+  return doc.kind === 'method' && before.has(interfaceKey) && after.has(interfaceKey);
+}
+
+/**
+ * Why a symbol present in both snapshots changed, and whether that breaks
+ * callers; null when nothing about its API changed.
+ */
+function classifyChange(previous: ApiSymbol, symbol: ApiSymbol): { breaking: boolean; reason: string } | null {
+  const after = symbol.doc;
+  const old = previous.doc.details ?? {};
+  const cur = after.details ?? {};
+
+  if (!sameTypeParams(old.typeParams, cur.typeParams)) {
+    return { breaking: true, reason: 'type parameters changed' };
+  }
+
+  switch (after.kind) {
+    case 'func':
+    case 'method':
+      if (!sameTypes(old.params, cur.params)) return { breaking: true, reason: 'parameter types changed' };
+      if (!sameTypes(old.results, cur.results)) return { breaking: true, reason: 'result types changed' };
+      if (!old.isPointerReceiver && cur.isPointerReceiver) {
+        return { breaking: true, reason: 'receiver changed from value to pointer' };
+      }
+      if (old.isPointerReceiver && !cur.isPointerReceiver) {
+        return { breaking: false, reason: 'receiver changed from pointer to value' };
+      }
+      break;
+
+    case 'field':
+      if (old.type !== cur.type || !old.isEmbedded !== !cur.isEmbedded) {
+        return { breaking: true, reason: 'field type changed' };
+      }
+      if (JSON.stringify(old.tags ?? {}) !== JSON.stringify(cur.tags ?? {})) {
+        return { breaking: false, reason: 'struct tags changed' };
+      }
+      break;
+
+    case 'type':
+      if (!old.isAlias !== !cur.isAlias || old.underlying !== cur.underlying) {
+        return { breaking: true, reason: 'underlying type changed' };
+      }
+      break;
+
+    case 'const':
+    case 'var':
+      if (old.type !== cur.type) return { breaking: true, reason: 'type changed' };
+      if (old.value !== cur.value) return { breaking: false, reason: 'value changed' };
+      break;
+  }
+
+  // Struct and interface bodies are covered by their members' own entries;
+  // anything else whose hash moved (e.g. type parameter names) is
+  // compatible. Receiver and parameter names take no part in either the
+  // hash or the canonical declaration, which also keeps hashes from
+  // snapshots made before they were left out from reporting a change.
+  const hashChanged = old.signatureHash !== undefined && old.signatureHash !== cur.signatureHash;
+  if (hashChanged && after.kind !== 'struct' && after.kind !== 'interface' &&
+    declarationOf(previous, true) !== declarationOf(symbol, true)) {
+    return { breaking: false, reason: 'declaration changed' };
+  }
+  return null;
+}
+
+/**
+ * Compare the exported API of two snapshots. Changes are sorted by qualified
+ * name and kind; filter on `breaking` for a semver check.
+ */
+export function breakingChanges(oldIndex: IndexDocument, newIndex: IndexDocument): ApiChange[] {
+  const before = exportedSurface(oldIndex);
+  const after = exportedSurface(newIndex);
+  const changes: ApiChange[] = [];
+
+  for (const [key, symbol] of after) {
+    const { doc, path } = symbol;
+    const previous = before.get(key);
+
+    if (!previous) {
+      const breaking = isMethodOfExistingInterface(doc, before, after);
+      changes.push({
+        change: 'added',
+        breaking,
+        kind: doc.kind,
+        qualifiedName: doc.qualifiedName,
+        path,
+        reason: breaking ? 'method added to interface' : `${doc.kind} added`,
+        after: declarationOf(symbol),
+      });
+      continue;
+    }
+
+    const classification = classifyChange(previous, symbol);
+    if (classification) {
+      changes.push({
+        change: 'changed',
+        ...classification,
+        kind: doc.kind,
+        qualifiedName: doc.qualifiedName,
+        path,
+        before: declarationOf(previous),
+        after: declarationOf(symbol),
+      });
+    }
+  }
+
+  for (const [key, symbol] of before) {
+    if (after.has(key)) continue;
+    changes.push({
+      change: 'removed',
+      breaking: true,
+      kind: symbol.doc.kind,
+      qualifiedName: symbol.doc.qualifiedName,
+      path: symbol.path,
+      reason: `${symbol.doc.kind} removed`,
+      before: declarationOf(symbol),
+    });
+  }
+
+  return changes.sort((a, b) => a.qualifiedName.localeCompare(b.qualifiedName) || a.kind.localeCompare(b.kind));
+}
@@ -0,0 +1,100 @@
+/**
+ * Flat CSV export of the index, for spreadsheets
+ *
+ * writeCSV emits one row per symbol:
+ *   file,package,kind,name,exported,start_line,end_line,doc
+ * writeFieldsCSV emits one row per leaf struct field, nested anonymous
+ * structs flattened into a dotted field path:
+ *   file,package,struct,field,type,tags,exported,line,doc
+ *
+ * Only the first line of a doc comment is kept. Values are quoted per
+ * RFC 4180 and rows end in CRLF. Files are sorted by path and symbols by
+ * position, like the JSON export.
+ */
+
+import type { CodeDatabase } from '../storage/database.js';
+import type { FileRecord, SymbolRecord } from '../core/types.js';
+import { compareByPosition, exportedKind } from './json-exporter.js';
+
+const SYMBOL_COLUMNS = ['file', 'package', 'kind', 'name', 'exported', 'start_line', 'end_line', 'doc'];
+const FIELD_COLUMNS = ['file', 'package', 'struct', 'field', 'type', 'tags', 'exported', 'line', 'doc'];
+
+/**
+ * Quote a value if it contains a comma, double quote or line break,
+ * doubling any double quotes inside
+ */
+export function csvValue(value: string | number | boolean): string {
+  const text = String(value);
+  return /[",\r\n]/.test(text) ? `"${text.replace(/"/g, '""')}"` : text;
+}
+
+function csvRow(values: Array<string | number | boolean>): string {
+  return values.map(csvValue).join(',') + '\r\n';
+}
+
+function firstLine(text: string | undefined): string {
+  return (text ?? '').split('\n')[0].trim();
+}
+
+function formatTags(tags: Record<string, string> | undefined): string {
+  return Object.entries(tags ?? {}).map(([key, value]) => `${key}:"${value}"`).join(' ');
+}
+
+function sortedFiles(db: CodeDatabase): FileRecord[] {
+  return db.getAllFiles().sort((a, b) => a.path.localeCompare(b.path));
+}
+
+/**
+ * Write one CSV row per symbol in the index
+ */
+export function writeCSV(db: CodeDatabase, out: NodeJS.WritableStream): void {
+  out.write(csvRow(SYMBOL_COLUMNS));
+  for (const file of sortedFiles(db)) {
+    for (const symbol of db.getSymbolsInFile(file.fileId!).sort(compareByPosition)) {
+      out.write(csvRow([
+        file.path,
+        file.packageName ?? '',
+        exportedKind(symbol.kind),
+        symbol.name,
+        Boolean(symbol.exported),
+        symbol.startLine,
+        symbol.endLine,
+        firstLine(symbol.details?.doc),
+      ]));
+    }
+  }
+}
+
+/**
+ * Write one CSV row per leaf field of each struct. A field holding an
+ * anonymous struct is not a leaf; its own fields are listed instead, under
+ * a path such as Address.Street.
+ */
+export function writeFieldsCSV(db: CodeDatabase, out: NodeJS.WritableStream): void {
+  out.write(csvRow(FIELD_COLUMNS));
+  for (const file of sortedFiles(db)) {
+    const symbols = db.getSymbolsInFile(file.fileId!).sort(compareByPosition);
+    const fields = symbols.filter(symbol => symbol.kind === 'field');
+    const containers = new Set(fields.map(field => field.qualifiedName.slice(0, field.qualifiedName.lastIndexOf('.'))));
+
+    for (const struct of symbols.filter(symbol => symbol.kind === 'struct')) {
+      const prefix = `${struct.qualifiedName}.`;
+      const leaves: SymbolRecord[] = fields.filter(
+        field => field.qualifiedName.startsWith(prefix) && !containers.has(field.qualifiedName)
+      );
+      for (const field of leaves) {
+        out.write(csvRow([
+          file.path,
+          file.packageName ?? '',
+          struct.name,
+          field.qualifiedName.slice(prefix.length),
+          field.details?.type ?? '',
+          formatTags(field.details?.tags),
+          Boolean(field.exported),
+          field.startLine,
+          firstLine(field.details?.doc ?? field.details?.comment),
+        ]));
+      }
+    }
+  }
+}
@@ -0,0 +1,238 @@
+/**
+ * Graphviz DOT export of the Go type dependency graph
+ *
+ * Nodes are Go types; an edge runs from a struct to each type its fields
+ * refer to. Pointer, slice, array, map and channel wrappers are looked
+ * through (`[]*User` points at User, `map[string]Address` at Address) and
+ * predeclared types are left out. Edges for embedded fields are dashed.
+ */
+
+import type { CodeDatabase } from '../storage/database.js';
+import type { SymbolRecord } from '../core/types.js';
+
+const PREDECLARED_TYPES = new Set([
+  'any', 'bool', 'byte', 'comparable', 'complex64', 'complex128', 'error', 'float32', 'float64',
+  'int', 'int8', 'int16', 'int32', 'int64', 'rune', 'string',
+  'uint', 'uint8', 'uint16', 'uint32', 'uint64', 'uintptr',
+]);
+
+const TYPE_KEYWORDS = new Set(['map', 'chan', 'func', 'struct', 'interface']);
+
+const TYPE_KINDS = new Set(['struct', 'interface', 'type']);
+
+interface Token {
+  text: string;
+  spaced: boolean; // whitespace precedes the token
+}
+
+function tokenize(typeText: string): Token[] {
+  const pattern = /\s*([\p{L}_][\p{L}\p{N}_]*|\d\w*|`[^`]*`|"(?:[^"\\]|\\.)*"|\.\.\.|<-|\S)/gu;
+  return Array.from(typeText.matchAll(pattern), match => ({ text: match[1], spaced: match[0] !== match[1] }));
+}
+
+function isIdentifier(token: Token | undefined): boolean {
+  return token !== undefined && /^[\p{L}_]/u.test(token.text) && !TYPE_KEYWORDS.has(token.text);
+}
+
+// Whether a type can begin at token, as opposed to a separator, closing bracket or tag
+function startsType(token: Token | undefined): boolean {
+  return token !== undefined && !/^[,;)\]}|`"]/.test(token.text);
+}
+
+/**
+ * Named types mentioned in a field type, e.g. `map[string][]*pkg.User` -> ['pkg.User'].
+ * The type is walked as Go type syntax (in typeString's canonical form), so
+ * parameter names of func types, field names of inline structs, method
+ * names of inline interfaces and array lengths are not taken for types.
+ */
+export function referencedTypeNames(typeText: string): string[] {
+  const tokens = tokenize(typeText);
+  const names: string[] = [];
+  let pos = 0;
+
+  const peek = (offset = 0) => tokens[pos + offset];
+  const accept = (text: string) => {
+    if (peek()?.text !== text) return false;
+    pos++;
+    return true;
+  };
+  // Skip to the `]` closing an array length, whatever expression it holds
+  const skipLength = () => {
+    for (let depth = 1; pos < tokens.length; pos++) {
+      const text = tokens[pos].text;
+      if (text === '[' || text === '(') depth++;
+      if ((text === ']' || text === ')') && --depth === 0) {
+        pos++;
+        return;
+      }
+    }
+  };
+
+  const type = (): void => {
+    const token = tokens[pos++];
+    if (!token) return;
+    switch (token.text) {
+      case '*':
+      case '~':
+        return type();
+      case '(':
+        type();
+        accept(')');
+        return;
+      case '[':
+        if (!accept(']')) skipLength();
+        return type();
+      case '<-':
+        accept('chan');
+        return type();
+      case 'chan':
+        accept('<-');
+        return type();
+      case 'map':
+        accept('[');
+        type();
+        accept(']');
+        return type();
+      case 'func':
+        return signature();
+      case 'struct':
+        return members(field);
+      case 'interface':
+        return members(interfaceElem);
+    }
+    if (!isIdentifier(token)) return;
+
+    let name = token.text;
+    if (peek()?.text === '.' && !peek()?.spaced && isIdentifier(peek(1))) {
+      name += `.${peek(1).text}`;
+      pos += 2;
+    }
+    names.push(name);
+    // Type arguments follow the name directly: Box[T], Pair[K, V]
+    if (peek()?.text === '[' && !peek()?.spaced) {
+      pos++;
+      do type(); while (accept(','));
+      accept(']');
+    }
+  };
+
+  // `{ a; b }` of a struct or interface, each member read by member
+  const members = (member: () => void) => {
+    if (!accept('{')) return;
+    while (pos < tokens.length && !accept('}')) {
+      member();
+      accept(';');
+    }
+  };
+
+  // `X, Y int`, `Name string \`json:"name"\`` or an embedded `*pkg.T`
+  const field = () => {
+    if (isIdentifier(peek()) && (peek(1)?.text === ',' || (peek(1)?.spaced && startsType(peek(1))))) {
+      pos++;
+      while (accept(',')) pos++;
+    }
+    type();
+    if (/^[`"]/.test(peek()?.text ?? '')) pos++;
+  };
+
+  // A method `Read(p []byte) (int, error)` or a type element `~int | ~string`
+  const interfaceElem = () => {
+    if (isIdentifier(peek()) && peek(1)?.text === '(' && !peek(1).spaced) {
+      pos++;
+      return signature();
+    }
+    do type(); while (accept('|'));
+  };
+
+  // `(ctx context.Context, n int) (T, error)`; the result follows after a space
+  const signature = () => {
+    parameters();
+    const next = peek();
+    if (!next?.spaced || !startsType(next)) return;
+    if (next.text === '(') parameters();
+    else type();
+  };
+
+  // A parameter list, where an entry is `name T`, `name ...T` or a lone T; once
+  // any entry has a name, the lone identifiers of `a, b int` are names too
+  const parameters = () => {
+    if (!accept('(')) return;
+    let named = false;
+    const bare: number[] = []; // positions in names of lone-identifier entries
+    while (pos < tokens.length && !accept(')')) {
+      if (isIdentifier(peek()) && peek(1)?.spaced && startsType(peek(1))) {
+        named = true;
+        pos++;
+      } else if (isIdentifier(peek()) && [',', ')'].includes(peek(1)?.text ?? '')) {
+        bare.push(names.length);
+      }
+      accept('...');
+      type();
+      accept(',');
+    }
+    if (named) {
+      for (const index of bare.reverse()) names.splice(index, 1);
+    }
+  };
+
+  while (pos < tokens.length) type();
+  return Array.from(new Set(names.filter(name => !PREDECLARED_TYPES.has(name))));
+}
+
+function quote(id: string): string {
+  return `"${id.replace(/\\/g, '\\\\').replace(/"/g, '\\"')}"`;
+}
+
+function packageOf(symbol: SymbolRecord): string {
+  return symbol.qualifiedName.slice(0, symbol.qualifiedName.lastIndexOf('.'));
+}
+
+/**
+ * Build the DOT source for all Go types in the index
+ */
+export function buildDOT(db: CodeDatabase): string {
+  const symbols = db.getAllSymbols().filter(symbol => symbol.language === 'go');
+  const types = new Map<string, SymbolRecord>();
+  for (const symbol of symbols) {
+    if (TYPE_KINDS.has(symbol.kind)) {
+      types.set(symbol.qualifiedName, symbol);
+    }
+  }
+
+  const nodes = new Set<string>(types.keys());
+  const edges = new Set<string>();
+
+  for (const field of symbols) {
+    if (field.kind !== 'field' || !field.details?.type) continue;
+
+    const owner = types.get(packageOf(field));
+    if (!owner || owner.kind !== 'struct') continue; // fields of anonymous nested structs are skipped
+
+    const typeParams = new Set((owner.details?.typeParams ?? []).map(param => param.name));
+    for (const name of referencedTypeNames(field.details.type)) {
+      if (typeParams.has(name)) continue;
+      // Unqualified names live in the struct's own package; pkg.T names are kept as written
+      const target = name.includes('.') ? name : `${packageOf(owner)}.${name}`;
+      nodes.add(target);
+      const style = field.details.isEmbedded ? ' [style=dashed]' : '';
+      edges.add(`  ${quote(owner.qualifiedName)} -> ${quote(target)}${style};`);
+    }
+  }
+
+  const lines = ['digraph types {', '  node [shape=box];'];
+  for (const node of Array.from(nodes).sort()) {
+    const kind = types.get(node)?.kind;
+    lines.push(`  ${quote(node)}${kind === 'interface' ? ' [shape=ellipse]' : ''};`);
+  }
+  lines.push(...Array.from(edges).sort());
+  lines.push('}');
+
+  return lines.join('\n') + '\n';
+}
+
+/**
+ * Write the type dependency graph as DOT to a stream
+ */
+export function writeDOT(db: CodeDatabase, out: NodeJS.WritableStream): void {
+  out.write(buildDOT(db));
+}
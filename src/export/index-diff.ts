@@ -0,0 +1,155 @@
+/**
+ * Differences between two JSON index snapshots (see json-exporter.ts)
+ *
+ * Symbols are matched by file, kind and qualified name; a symbol is modified
+ * when its signatureHash differs, so moving or reformatting a declaration is
+ * not a change. A symbol that moves to another file shows up as removed from
+ * one and added to the other. Struct fields are compared individually.
+ */
+
+import { symbolToString } from '../query/symbol-string.js';
+import { symbolKindOf } from './json-exporter.js';
+import type { FileDocument, IndexDocument, SymbolDocument } from './json-exporter.js';
+import type { Language } from '../core/types.js';
+
+export interface ModifiedSymbol {
+  before: SymbolDocument;
+  after: SymbolDocument;
+}
+
+export interface FileDiff {
+  path: string;
+  language: string;
+  added: SymbolDocument[];
+  removed: SymbolDocument[];
+  modified: ModifiedSymbol[];
+}
+
+export interface IndexDiff {
+  files: FileDiff[]; // 仅包含有变化的文件，按路径排序
+}
+
+/**
+ * Symbols of a file keyed by kind + qualified name; repeated keys (several
+ * `init` functions) get an occurrence suffix
+ */
+function symbolsByKey(file: FileDocument | undefined): Map<string, SymbolDocument> {
+  const symbols = new Map<string, SymbolDocument>();
+  const visit = (docs: SymbolDocument[]) => {
+    for (const doc of docs) {
+      const base = `${doc.kind}\u0000${doc.qualifiedName}`;
+      let key = base;
+      for (let n = 1; symbols.has(key); n++) key = `${base}\u0000${n}`;
+      symbols.set(key, doc);
+      visit(doc.fields ?? []);
+    }
+  };
+  visit(file?.symbols ?? []);
+  return symbols;
+}
+
+function withoutFields({ fields: _fields, ...doc }: SymbolDocument): SymbolDocument {
+  return doc;
+}
+
+function compareSymbols(a: SymbolDocument, b: SymbolDocument): number {
+  return a.qualifiedName.localeCompare(b.qualifiedName) || a.kind.localeCompare(b.kind) || a.startLine - b.startLine;
+}
+
+function isModified(before: SymbolDocument, after: SymbolDocument): boolean {
+  const beforeHash = before.details?.signatureHash;
+  const afterHash = after.details?.signatureHash;
+  if (beforeHash && afterHash) {
+    return beforeHash !== afterHash;
+  }
+  // Snapshots from before signature hashes existed: fall back to the signature text
+  const normalize = (doc: SymbolDocument) => (doc.signature ?? '').replace(/\s+/g, ' ').trim();
+  return normalize(before) !== normalize(after);
+}
+
+/**
+ * Compare two snapshots. Files and symbols are sorted, so the same pair of
+ * snapshots always produces the same diff.
+ */
+export function diffIndexes(oldIndex: IndexDocument, newIndex: IndexDocument): IndexDiff {
+  const oldFiles = new Map(oldIndex.files.map(file => [file.path, file]));
+  const newFiles = new Map(newIndex.files.map(file => [file.path, file]));
+  const paths = Array.from(new Set([...oldFiles.keys(), ...newFiles.keys()])).sort();
+
+  const files: FileDiff[] = [];
+  for (const path of paths) {
+    const before = symbolsByKey(oldFiles.get(path));
+    const after = symbolsByKey(newFiles.get(path));
+    const diff: FileDiff = {
+      path,
+      language: (newFiles.get(path) ?? oldFiles.get(path))!.language,
+      added: [],
+      removed: [],
+      modified: [],
+    };
+
+    for (const [key, doc] of after) {
+      const previous = before.get(key);
+      if (!previous) {
+        diff.added.push(withoutFields(doc));
+      } else if (isModified(previous, doc)) {
+        diff.modified.push({ before: withoutFields(previous), after: withoutFields(doc) });
+      }
+    }
+    for (const [key, doc] of before) {
+      if (!after.has(key)) diff.removed.push(withoutFields(doc));
+    }
+
+    if (diff.added.length + diff.removed.length + diff.modified.length > 0) {
+      diff.added.sort(compareSymbols);
+      diff.removed.sort(compareSymbols);
+      diff.modified.sort((a, b) => compareSymbols(a.after, b.after));
+      files.push(diff);
+    }
+  }
+
+  return { files };
+}
+
+/**
+ * One-line declaration of a snapshot symbol. Struct and interface bodies are
+ * left out: their member changes are listed as separate entries.
+ */
+function declarationOf(doc: SymbolDocument, language: string): string {
+  if (language === 'go' && (doc.kind === 'struct' || doc.kind === 'interface')) {
+    return `type ${doc.name} ${doc.kind}`;
+  }
+  return symbolToString({
+    language: language as Language,
+    kind: symbolKindOf(doc.kind),
+    name: doc.name,
+    qualifiedName: doc.qualifiedName,
+    signature: doc.signature,
+    details: doc.details,
+  });
+}
+
+/**
+ * Render a diff as text, one block per file:
+ *
+ *   pkg/user.go
+ *     + func NewUser(name string) *User
+ *     - func Legacy()
+ *     ~ example.UserService.GetUser
+ *         - func (s *UserService) GetUser(id int) *User
+ *         + func (s *UserService) GetUser(id int) (*User, error)
+ */
+export function formatIndexDiff(diff: IndexDiff): string {
+  const lines: string[] = [];
+  for (const file of diff.files) {
+    lines.push(file.path);
+    for (const doc of file.added) lines.push(`  + ${declarationOf(doc, file.language)}`);
+    for (const doc of file.removed) lines.push(`  - ${declarationOf(doc, file.language)}`);
+    for (const { before, after } of file.modified) {
+      lines.push(`  ~ ${after.qualifiedName}`);
+      lines.push(`      - ${declarationOf(before, file.language)}`);
+      lines.push(`      + ${declarationOf(after, file.language)}`);
+    }
+  }
+  return lines.length > 0 ? lines.join('\n') + '\n' : '';
+}
@@ -0,0 +1,203 @@
+/**
+ * JSON export of the full index
+ *
+ * Schema (version 1):
+ *   {
+ *     "version": 1,
+ *     "files": [
+ *       {
+ *         "path": "pkg/user.go",
+ *         "language": "go",
+ *         "package": "example",
+ *         "generated": true,          (only for generated Go files)
+ *         "test": true,               (only for Go _test.go files)
+ *         "externalTest": true,       (only for _test.go files of a package foo_test)
+ *         "imports": [{ "path": "fmt" }],
+ *         "symbols": [
+ *           { "kind": "struct", "name": "User", "qualifiedName": "example.User", ...,
+ *             "fields": [{ "kind": "field", "name": "ID", ... }] }
+ *         ]
+ *       }
+ *     ]
+ *   }
+ *
+ * Files are sorted by path and symbols by position, so exports of the same
+ * source are byte-for-byte identical across runs. Struct fields are nested
+ * under their parent struct (or anonymous struct field) in `fields`.
+ */
+
+import type { CodeDatabase } from '../storage/database.js';
+import type { ImportRecord, SymbolKind, SymbolRecord, SymbolDetails } from '../core/types.js';
+
+export const INDEX_DOCUMENT_VERSION = 1;
+
+export type ExportedSymbolKind =
+  | 'func'
+  | 'method'
+  | 'struct'
+  | 'interface'
+  | 'const'
+  | 'var'
+  | 'field'
+  | 'type'
+  | 'class'
+  | 'property'
+  | 'module'
+  | 'namespace'
+  | 'label';
+
+export interface SymbolDocument {
+  kind: ExportedSymbolKind;
+  name: string;
+  qualifiedName: string;
+  exported: boolean;
+  startLine: number;
+  startCol: number;
+  endLine: number;
+  endCol: number;
+  signature?: string;
+  details?: SymbolDetails;
+  fields?: SymbolDocument[];
+}
+
+export interface ImportDocument {
+  path: string;
+  alias?: string;
+  isBlank?: boolean;
+  isDot?: boolean;
+}
+
+export interface FileDocument {
+  path: string;
+  language: string;
+  package?: string;
+  packagePath?: string;
+  generated?: boolean;
+  test?: boolean;
+  externalTest?: boolean;
+  imports: ImportDocument[];
+  symbols: SymbolDocument[];
+}
+
+export interface IndexDocument {
+  version: number;
+  files: FileDocument[];
+}
+
+const KIND_NAMES: Record<SymbolKind, ExportedSymbolKind> = {
+  function: 'func',
+  method: 'method',
+  class: 'class',
+  interface: 'interface',
+  struct: 'struct',
+  variable: 'var',
+  constant: 'const',
+  property: 'property',
+  field: 'field',
+  module: 'module',
+  namespace: 'namespace',
+  type: 'type',
+  label: 'label',
+};
+
+export function exportedKind(kind: SymbolKind): ExportedSymbolKind {
+  return KIND_NAMES[kind];
+}
+
+/**
+ * Inverse of exportedKind, for reading snapshots back
+ */
+export function symbolKindOf(kind: ExportedSymbolKind): SymbolKind {
+  const entry = Object.entries(KIND_NAMES).find(([, name]) => name === kind);
+  return (entry ? entry[0] : kind) as SymbolKind;
+}
+
+export function compareByPosition(a: SymbolRecord, b: SymbolRecord): number {
+  return (
+    a.startLine - b.startLine ||
+    a.startCol - b.startCol ||
+    a.qualifiedName.localeCompare(b.qualifiedName)
+  );
+}
+
+export function toSymbolDocument(symbol: SymbolRecord): SymbolDocument {
+  const doc: SymbolDocument = {
+    kind: exportedKind(symbol.kind),
+    name: symbol.name,
+    qualifiedName: symbol.qualifiedName,
+    exported: Boolean(symbol.exported),
+    startLine: symbol.startLine,
+    startCol: symbol.startCol,
+    endLine: symbol.endLine,
+    endCol: symbol.endCol,
+  };
+  if (symbol.signature) doc.signature = symbol.signature;
+  if (symbol.details) doc.details = symbol.details;
+  return doc;
+}
+
+function toImportDocument(imp: ImportRecord): ImportDocument {
+  const doc: ImportDocument = { path: imp.path };
+  if (imp.alias) doc.alias = imp.alias;
+  if (imp.isBlank) doc.isBlank = true;
+  if (imp.isDot) doc.isDot = true;
+  return doc;
+}
+
+/**
+ * Arrange a file's symbols into a tree: fields hang off the struct (or
+ * anonymous struct field) whose qualified name prefixes theirs.
+ */
+function buildSymbolTree(symbols: SymbolRecord[]): SymbolDocument[] {
+  const sorted = [...symbols].sort(compareByPosition);
+  const containers = new Map<string, SymbolDocument>();
+  const roots: SymbolDocument[] = [];
+
+  for (const symbol of sorted) {
+    const doc = toSymbolDocument(symbol);
+    const parentName = symbol.qualifiedName.slice(0, symbol.qualifiedName.lastIndexOf('.'));
+    const parent = symbol.kind === 'field' ? containers.get(parentName) : undefined;
+
+    if (parent) {
+      (parent.fields ??= []).push(doc);
+    } else {
+      roots.push(doc);
+    }
+
+    if (symbol.kind === 'struct' || symbol.kind === 'field') {
+      containers.set(symbol.qualifiedName, doc);
+    }
+  }
+
+  return roots;
+}
+
+/**
+ * Build the serializable representation of everything in the database
+ */
+export function buildIndexDocument(db: CodeDatabase): IndexDocument {
+  const files = db.getAllFiles().sort((a, b) => a.path.localeCompare(b.path));
+
+  return {
+    version: INDEX_DOCUMENT_VERSION,
+    files: files.map(file => ({
+      path: file.path,
+      language: file.language,
+      ...(file.packageName ? { package: file.packageName } : {}),
+      ...(file.packagePath ? { packagePath: file.packagePath } : {}),
+      ...(file.isGenerated ? { generated: true } : {}),
+      ...(file.isTest ? { test: true } : {}),
+      ...(file.isExternalTest ? { externalTest: true } : {}),
+      imports: db.getImportsByFile(file.fileId!).map(toImportDocument),
+      symbols: buildSymbolTree(db.getSymbolsInFile(file.fileId!)),
+    })),
+  };
+}
+
+/**
+ * Serialize the full index as pretty-printed JSON
+ */
+export function writeJSON(db: CodeDatabase, out: NodeJS.WritableStream): void {
+  out.write(JSON.stringify(buildIndexDocument(db), null, 2));
+  out.write('\n');
+}
@@ -0,0 +1,33 @@
+/**
+ * Newline-delimited JSON export, one symbol per line
+ *
+ * Each line is the symbol's object from the JSON export (see
+ * json-exporter.ts) with the file and package it belongs to in front:
+ *
+ *   {"file":"pkg/user.go","package":"example","kind":"struct","name":"User",...}
+ *
+ * Struct fields get lines of their own instead of being nested in `fields`.
+ * Lines are written file by file, sorted by path and then by position, and
+ * the writer waits for the stream to drain when it is backed up, so only one
+ * file's symbols are held in memory at a time.
+ */
+
+import { once } from 'events';
+import type { CodeDatabase } from '../storage/database.js';
+import { compareByPosition, toSymbolDocument } from './json-exporter.js';
+
+/**
+ * Write every symbol of the index as one JSON object per line
+ */
+export async function writeNDJSON(db: CodeDatabase, out: NodeJS.WritableStream): Promise<void> {
+  const files = db.getAllFiles().sort((a, b) => a.path.localeCompare(b.path));
+
+  for (const file of files) {
+    const owner = { file: file.path, ...(file.packageName ? { package: file.packageName } : {}) };
+    for (const symbol of db.getSymbolsInFile(file.fileId!).sort(compareByPosition)) {
+      if (!out.write(`${JSON.stringify({ ...owner, ...toSymbolDocument(symbol) })}\n`)) {
+        await once(out, 'drain');
+      }
+    }
+  }
+}
@@ -0,0 +1,144 @@
+/**
+ * SQLite export of the index into a flat, query-friendly schema
+ *
+ *   symbols(id, file, name, qualified_name, kind, start_line, end_line, signature, doc)
+ *   fields(symbol_id -> symbols.id, name, type, is_embedded)
+ *   imports(file, path, alias)
+ *
+ * Unlike the working database, this schema is meant for ad-hoc SQL: one row
+ * per symbol, struct fields joined to their parent struct, and stable keys so
+ * re-exporting into the same file updates rows in place. Rows for symbols or
+ * imports that no longer exist are removed.
+ */
+
+import Database from 'better-sqlite3';
+import { mkdirSync } from 'fs';
+import { dirname } from 'path';
+import type { CodeDatabase } from '../storage/database.js';
+import { exportedKind } from './json-exporter.js';
+
+const SCHEMA = `
+  CREATE TABLE IF NOT EXISTS symbols (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file TEXT NOT NULL,
+    name TEXT NOT NULL,
+    qualified_name TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    start_line INTEGER NOT NULL,
+    end_line INTEGER NOT NULL,
+    signature TEXT,
+    doc TEXT,
+    UNIQUE (file, qualified_name, kind, start_line)
+  );
+
+  CREATE INDEX IF NOT EXISTS idx_symbols_name ON symbols(name);
+  CREATE INDEX IF NOT EXISTS idx_symbols_kind ON symbols(kind);
+
+  CREATE TABLE IF NOT EXISTS fields (
+    symbol_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT,
+    is_embedded INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (symbol_id, name),
+    FOREIGN KEY (symbol_id) REFERENCES symbols(id) ON DELETE CASCADE
+  );
+
+  CREATE INDEX IF NOT EXISTS idx_fields_name ON fields(name);
+
+  CREATE TABLE IF NOT EXISTS imports (
+    file TEXT NOT NULL,
+    path TEXT NOT NULL,
+    alias TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (file, path, alias)
+  );
+
+  CREATE INDEX IF NOT EXISTS idx_imports_path ON imports(path);
+`;
+
+/**
+ * Write the index into a SQLite database at dbPath, creating it if needed.
+ * Everything happens in one transaction.
+ */
+export function exportSQLite(source: CodeDatabase, dbPath: string): void {
+  mkdirSync(dirname(dbPath), { recursive: true });
+  const out = new Database(dbPath);
+
+  try {
+    out.pragma('foreign_keys = ON');
+    out.exec(SCHEMA);
+
+    const upsertSymbol = out.prepare(`
+      INSERT INTO symbols (file, name, qualified_name, kind, start_line, end_line, signature, doc)
+      VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+      ON CONFLICT (file, qualified_name, kind, start_line) DO UPDATE SET
+        name = excluded.name,
+        end_line = excluded.end_line,
+        signature = excluded.signature,
+        doc = excluded.doc
+      RETURNING id
+    `);
+    const upsertField = out.prepare(`
+      INSERT INTO fields (symbol_id, name, type, is_embedded)
+      VALUES (?, ?, ?, ?)
+      ON CONFLICT (symbol_id, name) DO UPDATE SET
+        type = excluded.type,
+        is_embedded = excluded.is_embedded
+    `);
+    const insertImport = out.prepare(`
+      INSERT OR IGNORE INTO imports (file, path, alias) VALUES (?, ?, ?)
+    `);
+
+    out.transaction(() => {
+      const symbolIds: number[] = [];
+      const fieldKeys: Array<[number, string]> = [];
+
+      out.exec('DELETE FROM imports');
+
+      for (const file of source.getAllFiles()) {
+        for (const imp of source.getImportsByFile(file.fileId!)) {
+          insertImport.run(file.path, imp.path, imp.alias ?? '');
+        }
+
+        // Parents come before their fields when ordered by position
+        const symbols = source
+          .getSymbolsInFile(file.fileId!)
+          .sort((a, b) => a.startLine - b.startLine || a.startCol - b.startCol);
+        const containerIds = new Map<string, number>(); // struct or field qualified name -> exported id
+
+        for (const symbol of symbols) {
+          const { id } = upsertSymbol.get(
+            file.path,
+            symbol.name,
+            symbol.qualifiedName,
+            exportedKind(symbol.kind),
+            symbol.startLine,
+            symbol.endLine,
+            symbol.signature ?? null,
+            symbol.details?.doc ?? symbol.details?.comment ?? null
+          ) as { id: number };
+          symbolIds.push(id);
+
+          if (symbol.kind === 'field') {
+            const parentName = symbol.qualifiedName.slice(0, symbol.qualifiedName.lastIndexOf('.'));
+            const parentId = containerIds.get(parentName);
+            if (parentId !== undefined) {
+              upsertField.run(parentId, symbol.name, symbol.details?.type ?? null, symbol.details?.isEmbedded ? 1 : 0);
+              fieldKeys.push([parentId, symbol.name]);
+            }
+          }
+          if (symbol.kind === 'struct' || symbol.kind === 'field') {
+            containerIds.set(symbol.qualifiedName, id);
+          }
+        }
+      }
+
+      // Drop rows left over from earlier exports
+      out.prepare('DELETE FROM fields WHERE (symbol_id, name) NOT IN (SELECT value ->> 0, value ->> 1 FROM json_each(?))')
+        .run(JSON.stringify(fieldKeys));
+      out.prepare('DELETE FROM symbols WHERE id NOT IN (SELECT value FROM json_each(?))')
+        .run(JSON.stringify(symbolIds));
+    })();
+  } finally {
+    out.close();
+  }
+}
@@ -0,0 +1,117 @@
+/**
+ * YAML export of the full index
+ *
+ * The document is the one the JSON exporter builds (see json-exporter.ts for
+ * the schema), so both formats carry the same data in the same order: keys
+ * appear as they do in the JSON output, files by path and symbols by
+ * position. Multi-line strings such as doc comments become literal block
+ * scalars (`doc: |-`); other strings are plain when YAML reads them back as
+ * the same string, and double-quoted otherwise.
+ */
+
+import type { CodeDatabase } from '../storage/database.js';
+import { buildIndexDocument } from './json-exporter.js';
+
+const INDENT = '  ';
+
+// Words YAML 1.1 readers take for booleans or null when unquoted
+const RESERVED = /^(?:true|false|yes|no|on|off|y|n|null|~)$/i;
+
+// Characters a literal block scalar cannot carry as they are
+const UNPRINTABLE = /[\r\x00-\x08\x0b\x0c\x0e-\x1f\x7f\x85\u2028\u2029\ufeff]/;
+
+function isPlain(text: string): boolean {
+  return /^[A-Za-z_][\w./-]*(?: [\w./-]+)*$/.test(text) && !RESERVED.test(text);
+}
+
+function scalar(value: unknown): string {
+  if (value === null || value === undefined) return 'null';
+  if (typeof value === 'string') return isPlain(value) ? value : JSON.stringify(value);
+  return String(value);
+}
+
+/**
+ * The lines of a literal block scalar for text, or null when text must be
+ * quoted instead: it has a single line, control characters, a first line
+ * starting with a space (which would set the indentation) or more than one
+ * trailing newline
+ */
+function blockScalar(text: string, indent: string): string[] | null {
+  if (!text.includes('\n') || UNPRINTABLE.test(text) || text.endsWith('\n\n')) return null;
+  const firstLine = text.split('\n').find(line => line !== '');
+  if (firstLine === undefined || firstLine.startsWith(' ')) return null;
+
+  const header = text.endsWith('\n') ? '|' : '|-';
+  const body = (text.endsWith('\n') ? text.slice(0, -1) : text).split('\n');
+  return [header, ...body.map(line => (line ? `${indent}${line}` : ''))];
+}
+
+function isEmptyContainer(value: unknown): boolean {
+  if (Array.isArray(value)) return value.length === 0;
+  return typeof value === 'object' && value !== null && Object.keys(value).length === 0;
+}
+
+/**
+ * Append value after `prefix` (a key with its colon, or a sequence dash):
+ * scalars and empty collections on the same line, block scalars and
+ * collections on the lines below, indented by indent
+ */
+function writeValue(prefix: string, value: unknown, indent: string, lines: string[]): void {
+  if (typeof value === 'string') {
+    const block = blockScalar(value, indent);
+    if (block) {
+      lines.push(`${prefix} ${block[0]}`, ...block.slice(1));
+      return;
+    }
+  }
+  if (typeof value !== 'object' || value === null) {
+    lines.push(`${prefix} ${scalar(value)}`);
+    return;
+  }
+  if (isEmptyContainer(value)) {
+    lines.push(`${prefix} ${Array.isArray(value) ? '[]' : '{}'}`);
+    return;
+  }
+  lines.push(prefix);
+  writeCollection(value, indent, lines);
+}
+
+function writeCollection(value: object, indent: string, lines: string[]): void {
+  if (Array.isArray(value)) {
+    for (const item of value) {
+      if (typeof item === 'object' && item !== null && !isEmptyContainer(item)) {
+        // The first entry of a nested collection shares the dash's line
+        const nested: string[] = [];
+        writeCollection(item, indent + INDENT, nested);
+        lines.push(`${indent}- ${nested[0].slice(indent.length + INDENT.length)}`, ...nested.slice(1));
+      } else {
+        writeValue(`${indent}-`, item, indent + INDENT, lines);
+      }
+    }
+    return;
+  }
+  for (const [key, item] of Object.entries(value)) {
+    if (item === undefined) continue;
+    writeValue(`${indent}${scalar(key)}:`, item, indent + INDENT, lines);
+  }
+}
+
+/**
+ * Render a JSON-compatible value as a YAML document
+ */
+export function toYAML(value: unknown): string {
+  const lines: string[] = [];
+  if (typeof value === 'object' && value !== null && !isEmptyContainer(value)) {
+    writeCollection(value, '', lines);
+  } else {
+    writeValue('---', value, INDENT, lines);
+  }
+  return `${lines.join('\n')}\n`;
+}
+
+/**
+ * Serialize the full index as YAML, with the schema of writeJSON
+ */
+export function writeYAML(db: CodeDatabase, out: NodeJS.WritableStream): void {
+  out.write(toYAML(buildIndexDocument(db)));
+}
@@ -0,0 +1,109 @@
+/**
+ * Names a Go function body refers to
+ */
+
+import type Parser from 'tree-sitter';
+
+// Declarations whose `name` children introduce names local to the function
+const LOCAL_DECLARATIONS = new Set([
+  'parameter_declaration',
+  'variadic_parameter_declaration',
+  'type_parameter_declaration',
+  'var_spec',
+  'const_spec',
+  'type_spec',
+  'type_alias',
+]);
+
+// Statements whose left-hand side can declare names: a := f(), for k, v := range m,
+// switch v := x.(type), case v := <-ch
+const LOCAL_ASSIGNMENTS: Record<string, string> = {
+  short_var_declaration: 'left',
+  range_clause: 'left',
+  type_switch_statement: 'alias',
+  receive_statement: 'left',
+};
+
+/**
+ * Every name declared anywhere in the function: receiver, parameters,
+ * results, type parameters and local variables, constants and types. Scopes
+ * are not tracked, so a name declared in one block counts for all of them.
+ */
+function localNames(fn: Parser.SyntaxNode): Set<string> {
+  const names = new Set<string>();
+  const visit = (node: Parser.SyntaxNode): void => {
+    if (LOCAL_DECLARATIONS.has(node.type)) {
+      for (const name of node.childrenForFieldName('name')) names.add(name.text);
+    }
+    const assigned = LOCAL_ASSIGNMENTS[node.type] && node.childForFieldName(LOCAL_ASSIGNMENTS[node.type]);
+    if (assigned) {
+      for (const name of assigned.type === 'identifier' ? [assigned] : assigned.namedChildren) {
+        if (name.type === 'identifier') names.add(name.text);
+      }
+    }
+    for (const child of node.namedChildren) {
+      visit(child);
+    }
+  };
+  visit(fn);
+  return names;
+}
+
+// The key of `User{Name: n}` names a field, not a declaration the body uses
+function isCompositeKey(node: Parser.SyntaxNode): boolean {
+  const element = node.parent?.type === 'literal_element' ? node.parent : node;
+  const keyed = element.parent;
+  return keyed?.type === 'keyed_element' && keyed.namedChildren[0]?.startIndex === element.startIndex;
+}
+
+/**
+ * Names the body of a function or method uses that may refer to
+ * declarations outside it, sorted and deduplicated: plain names (`User`,
+ * `ValidateEmail`, `DebugMode`) and qualified ones (`fmt.Sprintf`,
+ * `http.Client`), types included wherever the body names them, as in
+ * `v.(*User)` and `case *User:`. Locally declared names, among them the
+ * variable a type switch binds, are left out. Which of them are
+ * declarations in the index is decided at query time (see
+ * QueryEngine.bodyReferences).
+ */
+export function bodyRefs(fn: Parser.SyntaxNode): string[] {
+  const body = fn.childForFieldName('body');
+  if (!body) return [];
+  return collectRefs(body, localNames(fn));
+}
+
+/**
+ * Names an expression uses, as bodyRefs collects them: the initializer of
+ * a package-level variable, say. Names declared in function literals inside
+ * it are left out.
+ */
+export function expressionRefs(expr: Parser.SyntaxNode): string[] {
+  return collectRefs(expr, localNames(expr));
+}
+
+function collectRefs(root: Parser.SyntaxNode, locals: Set<string>): string[] {
+  const names = new Set<string>();
+  const visit = (node: Parser.SyntaxNode): void => {
+    if (node.type === 'selector_expression') {
+      const operand = node.childForFieldName('operand');
+      const field = node.childForFieldName('field');
+      if (operand?.type === 'identifier' && field && !locals.has(operand.text)) {
+        names.add(`${operand.text}.${field.text}`);
+        return;
+      }
+    } else if (node.type === 'qualified_type') {
+      const pkg = node.childForFieldName('package');
+      const name = node.childForFieldName('name');
+      if (pkg && name) names.add(`${pkg.text}.${name.text}`);
+      return;
+    } else if (node.type === 'identifier' || node.type === 'type_identifier') {
+      if (!locals.has(node.text) && !isCompositeKey(node)) names.add(node.text);
+      return;
+    }
+    for (const child of node.namedChildren) {
+      visit(child);
+    }
+  };
+  visit(root);
+  return Array.from(names).sort();
+}
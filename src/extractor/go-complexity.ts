@@ -0,0 +1,31 @@
+/**
+ * Cyclomatic complexity of Go functions
+ */
+
+import type Parser from 'tree-sitter';
+
+// Statements and clauses that add a branch; default cases do not
+const DECISION_NODES = new Set(['if_statement', 'for_statement', 'expression_case', 'type_case', 'communication_case']);
+
+/**
+ * 1 plus the number of decision points in a function or method body: each
+ * if, for, non-default case of a switch, type switch or select, and each
+ * && and ||. Function literals inside the body count towards it, as gocyclo
+ * counts them. A declaration without a body (an assembly stub) scores 1.
+ */
+export function cyclomaticComplexity(fnNode: Parser.SyntaxNode): number {
+  let complexity = 1;
+  const visit = (node: Parser.SyntaxNode): void => {
+    if (DECISION_NODES.has(node.type)) {
+      complexity++;
+    } else if (node.type === 'binary_expression') {
+      const operator = node.childForFieldName('operator')?.type;
+      if (operator === '&&' || operator === '||') complexity++;
+    }
+    for (const child of node.namedChildren) visit(child);
+  };
+
+  const body = fnNode.childForFieldName('body');
+  if (body) visit(body);
+  return complexity;
+}
@@ -0,0 +1,37 @@
+/**
+ * Go deprecation notices (https://go.dev/wiki/Deprecated)
+ */
+
+import type { SymbolDetails } from '../core/types.js';
+
+/**
+ * The deprecation notice in a doc comment: a paragraph starting with
+ * "Deprecated:", which is either the first paragraph or one preceded by a
+ * blank comment line. Returns the paragraph text after the marker, with its
+ * lines joined by spaces, or null when the comment has no such paragraph;
+ * "deprecated" in the middle of a sentence does not count.
+ */
+export function deprecationNote(doc: string): string | null {
+  const lines = doc.split('\n');
+
+  for (let i = 0; i < lines.length; i++) {
+    const startsParagraph = i === 0 || lines[i - 1].trim() === '';
+    if (!startsParagraph || !lines[i].startsWith('Deprecated:')) continue;
+
+    const paragraph = [lines[i].slice('Deprecated:'.length)];
+    for (let j = i + 1; j < lines.length && lines[j].trim() !== ''; j++) {
+      paragraph.push(lines[j]);
+    }
+    return paragraph.map(line => line.trim()).filter(Boolean).join(' ');
+  }
+  return null;
+}
+
+/**
+ * deprecated/deprecationNote details for a symbol with the given doc comment
+ */
+export function deprecationDetails(doc: string | undefined): Pick<SymbolDetails, 'deprecated' | 'deprecationNote'> {
+  const note = doc === undefined ? null : deprecationNote(doc);
+  if (note === null) return {};
+  return note ? { deprecated: true, deprecationNote: note } : { deprecated: true };
+}
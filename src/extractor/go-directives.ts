@@ -0,0 +1,32 @@
+/**
+ * Recognition of Go directive comments such as //go:generate or //nolint
+ */
+
+// Directives without a namespace prefix: cgo's //export and //extern,
+// //line and golangci-lint's //nolint
+const PLAIN_DIRECTIVES = new Set(['export', 'extern', 'line', 'nolint']);
+
+/**
+ * Split a line comment into a directive name and its arguments, or null when
+ * it is an ordinary comment. Like the go tool, only comments with no space
+ * after the slashes count: `//go:generate stringer -type=Kind` is a
+ * directive, `// go:generate ...` is not. Names are either namespaced
+ * (go:embed, lint:ignore) or one of the plain names above; a linter list is
+ * split off nolint, so `//nolint:errcheck,gosec` gives nolint with args
+ * 'errcheck,gosec'.
+ */
+export function parseDirective(text: string): { name: string; args: string } | null {
+  if (!text.startsWith('//')) return null;
+  const match = /^(\S+)(?:\s+(.*))?$/.exec(text.slice(2));
+  if (!match) return null;
+  const [, word, rest = ''] = match;
+
+  if (word === 'nolint' || word.startsWith('nolint:')) {
+    const linters = word.slice('nolint:'.length);
+    return { name: 'nolint', args: [linters, rest.trim()].filter(Boolean).join(' ') };
+  }
+  if (PLAIN_DIRECTIVES.has(word) || /^[a-z0-9]+:[a-z0-9]/.test(word)) {
+    return { name: word, args: rest.trim() };
+  }
+  return null;
+}
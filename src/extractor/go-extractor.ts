@@ -8,7 +8,22 @@ import type {
   Language,
   SymbolKind,
   ReferenceKind,
+  TypeParam,
+  SymbolDetails,
+  Param,
+  InlineStruct,
+  InlineField,
+  Directive,
 } from '../core/types.js';
+import { parseStructTag, tagLiteralValue } from './go-struct-tag.js';
+import { typeString } from './go-type-string.js';
+import { resolveTypeRef } from './go-type-ref.js';
+import { deprecationDetails } from './go-deprecation.js';
+import { parseDirective } from './go-directives.js';
+import { functionResultTypes, inferExpressionType } from './go-type-infer.js';
+import { bodyRefs, expressionRefs } from './go-body-refs.js';
+import { cyclomaticComplexity } from './go-complexity.js';
+import { callsPanic, returnsError } from './go-failure.js';
 
 export interface ExtractionResult {
   symbols: Omit<SymbolRecord, 'fileId' | 'symbolId'>[];
@@ -28,15 +43,114 @@ export interface ExtractionResult {
     endLine: number;
     endCol: number;
   }>;
+  packageName: string;
+  imports: Array<{
+    path: string;
+    alias?: string;
+  }>;
+  callEdges: Array<{
+    callerQualifiedName: string;
+    calleeQualifiedName: string;
+    siteStartLine: number;
+    siteStartCol: number;
+    siteEndLine: number;
+    siteEndCol: number;
+  }>;
+  usedNames: string[]; // exported-looking identifiers used other than where they are declared, deduplicated
+  directives: Directive[];
+  truncated?: boolean; // symbol extraction stopped at maxSymbols, leaving declarations out
+}
+
+// Top-level declarations, which extraction stops before once maxSymbols is reached
+const DECLARATIONS = new Set([
+  'function_declaration',
+  'method_declaration',
+  'type_declaration',
+  'var_declaration',
+  'const_declaration',
+]);
+
+// Declarations whose `name` child declares rather than uses an identifier
+const DECLARING_PARENTS = new Set([
+  'function_declaration',
+  'method_declaration',
+  'method_elem',
+  'type_spec',
+  'type_alias',
+  'field_declaration',
+  'var_spec',
+  'const_spec',
+]);
+
+const COMPARISON_OPERATORS = new Set(['==', '!=', '<', '<=', '>', '>=', '&&', '||']);
+
+// Builtins allowed in constant expressions whose result is a typed constant
+const CONST_BUILTIN_TYPES: Record<string, string> = {
+  len: 'int',
+  cap: 'int',
+  'unsafe.Sizeof': 'uintptr',
+  'unsafe.Alignof': 'uintptr',
+  'unsafe.Offsetof': 'uintptr',
+};
+
+// Builtins whose result is untyped for untyped operands, with its default type
+const CONST_BUILTIN_DEFAULTS: Record<string, string> = {
+  real: 'float64',
+  imag: 'float64',
+  complex: 'complex128',
+  min: '',
+  max: '',
+};
+
+// Predeclared functions; calls to them are never edges in the call graph
+const GO_BUILTIN_FUNCS = new Set([
+  'append', 'cap', 'clear', 'close', 'complex', 'copy', 'delete', 'imag', 'len',
+  'make', 'max', 'min', 'new', 'panic', 'print', 'println', 'real', 'recover',
+]);
+
+/**
+ * Go's rule: a name is exported when its first character is an upper-case
+ * letter (Unicode class Lu), so Café is exported while café, _x and 名字 are not
+ */
+function isExportedName(name: string): boolean {
+  return /^\p{Lu}/u.test(name);
 }
 
 export class GoExtractor {
-  private maxNestedStructDepth: number = 3; // 默认最大深度为 3
+  private maxNestedStructDepth: number = 3; // 默认最大深度为 3，0 表示不限制
+  private constValues = new Map<string, bigint>(); // 当前文件中已求值的整数常量，用于折叠引用它们的表达式
+  private constTypes = new Map<string, SymbolDetails>(); // 当前文件中常量的 type 或 untyped/defaultType，供引用它们的常量沿用
+  private resultTypes = new Map<string, string>(); // 当前文件中只有一个返回值的函数的返回类型，用于推断 var x = f() 的类型
+  private computeComplexity: boolean = false; // 是否计算函数/方法的圈复杂度
+  private includeKinds: Set<SymbolKind> | null = null; // 只提取这些类型的符号，null 表示全部
+  private includeLocals: boolean = false; // 是否提取函数体内的局部声明和标签
+  private maxSymbols: number = 0; // 每个文件最多提取的符号数，达到后不再提取后面的声明，0 表示不限制
+  private truncated = false; // 当前文件是否因 maxSymbols 停止了提取
 
-  constructor(maxNestedStructDepth?: number) {
+  constructor(
+    maxNestedStructDepth?: number,
+    computeComplexity?: boolean,
+    includeKinds?: SymbolKind[],
+    includeLocals?: boolean,
+    maxSymbols?: number
+  ) {
     if (maxNestedStructDepth !== undefined && maxNestedStructDepth >= 0) {
       this.maxNestedStructDepth = maxNestedStructDepth;
     }
+    this.computeComplexity = computeComplexity ?? false;
+    if (includeKinds && includeKinds.length > 0) {
+      this.includeKinds = new Set(includeKinds);
+    }
+    this.includeLocals = includeLocals ?? false;
+    this.maxSymbols = Math.max(0, maxSymbols ?? 0);
+  }
+
+  /**
+   * Whether any of kinds is to be extracted. Declarations of kinds nobody
+   * asked for are skipped before any of their details are computed.
+   */
+  private wants(...kinds: SymbolKind[]): boolean {
+    return !this.includeKinds || kinds.some(kind => this.includeKinds!.has(kind));
   }
 
   extract(tree: Parser.Tree, source: string, language: Language): ExtractionResult {
@@ -46,6 +160,10 @@ export class GoExtractor {
 
     const rootNode = tree.rootNode;
     const sourceLines = source.split('\n');
+    this.constValues.clear();
+    this.constTypes.clear();
+    this.resultTypes = functionResultTypes(rootNode);
+    this.truncated = false;
 
     // Extract package name
     const packageNode = rootNode.children.find(n => n.type === 'package_clause');
@@ -59,11 +177,68 @@ export class GoExtractor {
 
     // Extract symbols
     this.extractSymbols(rootNode, symbols, language, sourceLines, packageName);
+    if (this.maxSymbols > 0 && symbols.length > this.maxSymbols) {
+      // The last declaration (a struct and its fields, say) went over the limit
+      symbols.length = this.maxSymbols;
+      this.truncated = true;
+    }
 
     // Extract calls and references
     this.extractCallsAndReferences(rootNode, calls, references, sourceLines);
 
-    return { symbols, calls, references };
+    const imports = this.extractImports(rootNode);
+    const callEdges = this.extractCallEdges(rootNode, packageName);
+    const usedNames = this.extractUsedNames(rootNode);
+    const directives = this.extractDirectives(rootNode, symbols);
+    this.resolveFieldTypeRefs(symbols, imports);
+    // A cgo file's Go declarations index like any other; the C pseudo-package is only noted
+    const cgo = imports.some(imp => imp.path === 'C');
+    for (const directive of directives) {
+      if (directive.name === 'export' && directive.symbol) {
+        const symbol = symbols.find(s => s.qualifiedName === directive.symbol);
+        if (symbol) symbol.details = { ...symbol.details, cgoExport: directive.args || symbol.name };
+      }
+    }
+    for (const symbol of symbols) {
+      const deprecation = deprecationDetails(symbol.details?.doc);
+      if (deprecation.deprecated) {
+        symbol.details = { ...symbol.details, ...deprecation };
+      }
+      if (packageName === 'main') {
+        symbol.details = { ...symbol.details, inMain: true };
+      }
+      if (cgo) {
+        symbol.details = { ...symbol.details, cgo: true };
+      }
+    }
+
+    return {
+      symbols, calls, references, packageName, imports, callEdges, usedNames, directives,
+      ...(this.truncated ? { truncated: true } : {}),
+    };
+  }
+
+  private extractImports(rootNode: Parser.SyntaxNode): ExtractionResult['imports'] {
+    const imports: ExtractionResult['imports'] = [];
+
+    for (const decl of rootNode.children.filter(n => n.type === 'import_declaration')) {
+      // import "fmt" has a single import_spec, import (...) wraps them in import_spec_list
+      const specList = decl.namedChildren.find(c => c.type === 'import_spec_list');
+      const specs = (specList ? specList.namedChildren : decl.namedChildren).filter(c => c.type === 'import_spec');
+
+      for (const spec of specs) {
+        const pathNode = spec.childForFieldName('path');
+        if (!pathNode) continue;
+
+        const path = tagLiteralValue(pathNode.text);
+        if (path === null) continue;
+
+        const nameNode = spec.childForFieldName('name');
+        imports.push(nameNode ? { path, alias: nameNode.text } : { path });
+      }
+    }
+
+    return imports;
   }
 
   private extractSymbols(
@@ -73,15 +248,32 @@ export class GoExtractor {
     sourceLines: string[],
     scope: string
   ): void {
+    // Past maxSymbols, later declarations are skipped before any of their details are computed
+    if (this.maxSymbols > 0 && symbols.length >= this.maxSymbols) {
+      if (DECLARATIONS.has(node.type)) this.truncated = true;
+      return;
+    }
+
     // Function declarations
-    if (node.type === 'function_declaration') {
+    if (node.type === 'function_declaration' && this.wants('function')) {
       const nameNode = node.childForFieldName('name');
       if (nameNode) {
         const name = nameNode.text;
         const qualifiedName = scope ? `${scope}.${name}` : name;
         
         // Check if it's exported (starts with uppercase in Go)
-        const exported = name.length > 0 && name[0] === name[0].toUpperCase();
+        const exported = isExportedName(name);
+        const details: SymbolDetails = this.extractParamsAndResults(node);
+        const typeParams = this.extractTypeParams(node);
+        if (typeParams.length > 0) details.typeParams = typeParams;
+        const doc = this.extractDocComment(node);
+        if (doc) details.doc = doc;
+        const refs = bodyRefs(node);
+        if (refs.length > 0) details.bodyRefs = refs;
+        if (this.computeComplexity) details.cyclomatic = cyclomaticComplexity(node);
+        details.hasBody = node.childForFieldName('body') !== null;
+        details.panics = callsPanic(node);
+        details.returnsError = returnsError(details.results);
         
         symbols.push({
           language,
@@ -89,26 +281,49 @@ export class GoExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: this.extractSignature(node, sourceLines),
           exported,
+          details: this.nonEmptyDetails(details),
         });
+        if (this.includeLocals) this.extractLocals(node, qualifiedName, symbols, language);
       }
     }
 
     // Method declarations
-    if (node.type === 'method_declaration') {
+    if (node.type === 'method_declaration' && this.wants('method')) {
       const nameNode = node.childForFieldName('name');
       const receiverNode = node.childForFieldName('receiver');
       
       if (nameNode && receiverNode) {
         const name = nameNode.text;
-        const receiverType = this.extractReceiverType(receiverNode);
+        const receiver = this.extractReceiverType(receiverNode);
+        const receiverType = receiver.baseType;
         const qualifiedName = receiverType ? `${scope}.${receiverType}.${name}` : `${scope}.${name}`;
         
-        const exported = name.length > 0 && name[0] === name[0].toUpperCase();
+        const exported = isExportedName(name);
+        const details: SymbolDetails = this.extractParamsAndResults(node);
+        if (receiverType) {
+          details.receiverType = receiverType;
+          details.isPointerReceiver = receiver.isPointer;
+          details.receiver = {
+            name: receiver.name,
+            type: receiver.typeText,
+            isPointer: receiver.isPointer,
+            ...(receiver.typeParams.length > 0 ? { typeParams: receiver.typeParams } : {}),
+          };
+        }
+        if (receiver.typeParams.length > 0) details.receiverTypeParams = receiver.typeParams;
+        const doc = this.extractDocComment(node);
+        if (doc) details.doc = doc;
+        const refs = bodyRefs(node);
+        if (refs.length > 0) details.bodyRefs = refs;
+        if (this.computeComplexity) details.cyclomatic = cyclomaticComplexity(node);
+        details.hasBody = node.childForFieldName('body') !== null;
+        details.panics = callsPanic(node);
+        details.returnsError = returnsError(details.results);
         
         symbols.push({
           language,
@@ -116,55 +331,82 @@ export class GoExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: this.extractSignature(node, sourceLines),
           exported,
+          details: this.nonEmptyDetails(details),
         });
+        if (this.includeLocals) this.extractLocals(node, qualifiedName, symbols, language);
       }
     }
 
-    // Type declarations (struct, interface)
-    if (node.type === 'type_declaration') {
-      // type_declaration contains type_spec as named children
+    // Type declarations (struct, interface, alias)
+    // Struct fields and interface methods hang off their type, so the type is
+    // extracted whenever one of them is wanted (the indexer drops it again)
+    if (node.type === 'type_declaration' && this.wants('struct', 'interface', 'type', 'field', 'method')) {
+      // type_declaration contains type_spec (`type T int`) or type_alias (`type T = int`) as named children
       for (const child of node.namedChildren) {
-        if (child.type === 'type_spec') {
+        if (child.type === 'type_spec' || child.type === 'type_alias') {
           const nameNode = child.childForFieldName('name');
           const typeNode = child.childForFieldName('type');
           
           if (nameNode && typeNode) {
             const name = nameNode.text;
             const qualifiedName = scope ? `${scope}.${name}` : name;
-            const exported = name.length > 0 && name[0] === name[0].toUpperCase();
+            const exported = isExportedName(name);
+            const typeParams = this.extractTypeParams(child);
+            const details: SymbolDetails = this.extractDeclarationDocs(node, child);
+            if (typeParams.length > 0) details.typeParams = typeParams;
+            const isAlias = child.type === 'type_alias';
             
+            details.typeKind =
+              typeNode.type === 'struct_type' ? 'struct' : typeNode.type === 'interface_type' ? 'interface' : 'defined';
+
+            // An alias is just another name for its right-hand side, whatever that is
             let kind: SymbolKind = 'type';
-            if (typeNode.type === 'struct_type') {
+            if (isAlias) {
+              details.isAlias = true;
+              details.underlying = typeString(typeNode);
+            } else if (typeNode.type === 'struct_type') {
               kind = 'struct'; // Use 'struct' for Go structs
             } else if (typeNode.type === 'interface_type') {
               kind = 'interface';
+              const embedded = this.extractEmbeddedInterfaces(typeNode);
+              if (embedded.length > 0) details.embeddedInterfaces = embedded;
+            } else {
+              details.underlying = typeString(typeNode);
             }
             
-            symbols.push({
+            // Ungrouped declarations cover the whole `type X struct {...}`
+            const rangeNode = this.declarationRangeNode(node, child);
+            
+            const typeSymbol: Omit<SymbolRecord, 'fileId' | 'symbolId'> = {
               language,
               kind,
               name,
               qualifiedName,
-              startLine: child.startPosition.row + 1,
-              startCol: child.startPosition.column,
-              endLine: child.endPosition.row + 1,
-              endCol: child.endPosition.column,
-              signature: `type ${name}`,
+              startLine: rangeNode.startPosition.row + 1,
+              startCol: rangeNode.startPosition.column + 1,
+              endLine: rangeNode.endPosition.row + 1,
+              endCol: rangeNode.endPosition.column + 1,
+              signature: isAlias ? `type ${name} = ${details.underlying}` : `type ${name}`,
               exported,
-            });
+              details: this.nonEmptyDetails(details),
+            };
+            symbols.push(typeSymbol);
 
             // Extract struct fields
-            if (typeNode.type === 'struct_type') {
-              this.extractStructFields(typeNode, symbols, language, sourceLines, qualifiedName, 0);
+            if (kind === 'struct') {
+              const truncated = this.extractStructFields(typeNode, symbols, language, sourceLines, qualifiedName, 0);
+              if (truncated) {
+                typeSymbol.details = { ...typeSymbol.details, truncated: true };
+              }
             }
             
             // Extract interface methods
-            if (typeNode.type === 'interface_type') {
+            if (kind === 'interface') {
               this.extractInterfaceMethods(typeNode, symbols, language, sourceLines, qualifiedName);
             }
           }
@@ -173,37 +415,195 @@ export class GoExtractor {
     }
 
     // Variable/constant declarations
-    if (node.type === 'var_declaration' || node.type === 'const_declaration') {
+    if (
+      (node.type === 'var_declaration' && this.wants('variable')) ||
+      (node.type === 'const_declaration' && this.wants('constant'))
+    ) {
       const specs = node.children.filter(c => c.type === 'var_spec' || c.type === 'const_spec');
+      const isConst = node.type === 'const_declaration';
+      // A const spec without values repeats the previous spec's type and expressions
+      let inheritedValues: Parser.SyntaxNode[] = [];
+      let inheritedType: Parser.SyntaxNode | null = null;
       
-      for (const spec of specs) {
-        const nameNode = spec.childForFieldName('name');
-        if (nameNode) {
+      specs.forEach((spec, iota) => {
+        const rangeNode = this.declarationRangeNode(node, spec);
+        const valueList = spec.childForFieldName('value');
+        let typeNode = spec.childForFieldName('type');
+        let values = valueList ? valueList.namedChildren : [];
+        if (isConst) {
+          if (values.length > 0) {
+            inheritedValues = values;
+            inheritedType = typeNode;
+          } else {
+            values = inheritedValues;
+            typeNode = inheritedType;
+          }
+        }
+
+        const nameNodes = spec.childrenForFieldName('name');
+        nameNodes.forEach((nameNode, index) => {
           const name = nameNode.text;
+          if (name === '_') return;
           const qualifiedName = scope ? `${scope}.${name}` : name;
-          const exported = name.length > 0 && name[0] === name[0].toUpperCase();
+          const exported = isExportedName(name);
+          const details = this.extractDeclarationDocs(node, spec);
+          if (typeNode) details.type = typeString(typeNode);
+          const inlineStruct = typeNode && this.extractInlineStruct(typeNode, 1);
+          if (inlineStruct) details.inlineStruct = inlineStruct;
+          if (isConst && values[index]) {
+            Object.assign(details, this.evaluateConstValue(values[index], iota));
+            if (details.intValue !== undefined) {
+              this.constValues.set(name, BigInt(details.intValue));
+            }
+            if (!typeNode) {
+              Object.assign(details, this.untypedConstType(values[index], details));
+            }
+            this.constTypes.set(name, { type: details.type, untyped: details.untyped, defaultType: details.defaultType });
+          } else if (!isConst && values.length === nameNodes.length) {
+            // `var a, b = f()` assigns both from one call, so there is no per-name expression
+            details.value = values[index].text;
+            const inferred = typeNode ? undefined : inferExpressionType(values[index], this.resultTypes);
+            if (inferred) {
+              details.type = inferred;
+              details.typeInferred = true;
+            }
+          }
+          if (!isConst && values.length > 0) {
+            // Each name depends on its own expression, or with `var a, b = f()` on the one call
+            const initializers = values.length === nameNodes.length ? [values[index]] : values;
+            const refs = Array.from(new Set(initializers.flatMap(expressionRefs))).sort();
+            if (refs.length > 0) details.initRefs = refs;
+          }
+          // With several names in one spec, each symbol starts at its own identifier
+          const start = nameNodes.length > 1 ? nameNode.startPosition : rangeNode.startPosition;
           
           symbols.push({
             language,
-            kind: node.type === 'const_declaration' ? 'constant' : 'variable',
+            kind: isConst ? 'constant' : 'variable',
             name,
             qualifiedName,
-            startLine: spec.startPosition.row + 1,
-            startCol: spec.startPosition.column,
-            endLine: spec.endPosition.row + 1,
-            endCol: spec.endPosition.column,
+            startLine: start.row + 1,
+            startCol: start.column + 1,
+            endLine: rangeNode.endPosition.row + 1,
+            endCol: rangeNode.endPosition.column + 1,
             exported,
+            details: this.nonEmptyDetails(details),
           });
-        }
-      }
+        });
+      });
     }
 
-    // Recurse into children
+    // Recurse into children. Declarations inside function bodies are local,
+    // not package-level, so bodies are not searched.
     for (const child of node.namedChildren) {
+      if (child.type === 'block') continue;
       this.extractSymbols(child, symbols, language, sourceLines, scope);
     }
   }
 
+  /**
+   * Declarations inside a function or method body (includeLocals): local
+   * variables and constants (`var x T`, `x := f()`, `const n = 3`) and
+   * labels, each qualified by its enclosing symbol and pointing back to it
+   * through details.scope. A function literal that is the only value
+   * assigned to a name (`f := func(x int) error {...}`) becomes a function
+   * symbol with its parameters and results, and the locals of its body hang
+   * off it. Locals are never exported. Names declared in if, for and switch
+   * headers and by range clauses are not extracted, nor are names a `:=`
+   * only assigns to because its block already declares them.
+   */
+  private extractLocals(
+    fnNode: Parser.SyntaxNode,
+    scope: string,
+    symbols: Omit<SymbolRecord, 'fileId' | 'symbolId'>[],
+    language: Language
+  ): void {
+    const push = (kind: SymbolKind, name: string, start: Parser.SyntaxNode, end: Parser.SyntaxNode, details: SymbolDetails, parent: string) => {
+      symbols.push({
+        language,
+        kind,
+        name,
+        qualifiedName: `${parent}.${name}`,
+        startLine: start.startPosition.row + 1,
+        startCol: start.startPosition.column + 1,
+        endLine: end.endPosition.row + 1,
+        endCol: end.endPosition.column + 1,
+        exported: false,
+        details: { ...details, scope: parent },
+      });
+    };
+
+    // A name bound to a function literal, or a plain variable or constant
+    const declare = (kind: SymbolKind, nameNode: Parser.SyntaxNode, statement: Parser.SyntaxNode, value: Parser.SyntaxNode | undefined, details: SymbolDetails, parent: string) => {
+      const name = nameNode.text;
+      if (name === '_') return;
+      // With several names in one statement, each symbol starts at its own identifier
+      const start = this.hasSeveralNames(statement) ? nameNode : statement;
+      if (kind === 'variable' && value?.type === 'func_literal') {
+        if (!this.wants('function')) return;
+        push('function', name, start, statement, { ...details, ...this.extractParamsAndResults(value) }, parent);
+        visit(value, `${parent}.${name}`);
+        return;
+      }
+      if (this.wants(kind)) push(kind, name, start, statement, details, parent);
+      if (value) visit(value, parent);
+    };
+
+    const visit = (node: Parser.SyntaxNode, parent: string): void => {
+      // Names declared so far among these statements; `x, err := g()` after `err := f()` only assigns err
+      const declared = new Set<string>();
+      // The `err := f()` of `if err := f(); err != nil` and of for and switch headers
+      const initializer = node.childForFieldName('initializer');
+      for (const child of node.namedChildren) {
+        if (child.type === 'short_var_declaration' && child.startIndex === initializer?.startIndex) {
+          child.childForFieldName('right')?.namedChildren.forEach(value => visit(value, parent));
+        } else if (child.type === 'labeled_statement') {
+          const label = child.childForFieldName('label');
+          if (label && this.wants('label')) push('label', label.text, child, label, {}, parent);
+          visit(child, parent);
+        } else if (child.type === 'short_var_declaration') {
+          const names = child.childForFieldName('left')?.namedChildren.filter(n => n.type === 'identifier') ?? [];
+          const values = child.childForFieldName('right')?.namedChildren ?? [];
+          names.forEach((nameNode, i) => {
+            const value = values.length === names.length ? values[i] : undefined;
+            if (declared.has(nameNode.text)) {
+              if (value) visit(value, parent);
+              return;
+            }
+            declared.add(nameNode.text);
+            declare('variable', nameNode, child, value, {}, parent);
+          });
+          if (values.length !== names.length) values.forEach(value => visit(value, parent));
+        } else if (child.type === 'var_declaration' || child.type === 'const_declaration') {
+          const kind: SymbolKind = child.type === 'var_declaration' ? 'variable' : 'constant';
+          for (const spec of child.namedChildren.filter(c => c.type === 'var_spec' || c.type === 'const_spec')) {
+            const typeNode = spec.childForFieldName('type');
+            const names = spec.childrenForFieldName('name');
+            const values = spec.childForFieldName('value')?.namedChildren ?? [];
+            names.forEach((nameNode, i) => {
+              declared.add(nameNode.text);
+              const details: SymbolDetails = typeNode ? { type: typeString(typeNode) } : {};
+              declare(kind, nameNode, spec, values.length === names.length ? values[i] : undefined, details, parent);
+            });
+            if (values.length !== names.length) values.forEach(value => visit(value, parent));
+          }
+        } else {
+          visit(child, parent);
+        }
+      }
+    };
+
+    const body = fnNode.childForFieldName('body');
+    if (body) visit(body, scope);
+  }
+
+  private hasSeveralNames(statement: Parser.SyntaxNode): boolean {
+    const names = statement.type === 'short_var_declaration'
+      ? statement.childForFieldName('left')?.namedChildren ?? []
+      : statement.childrenForFieldName('name');
+    return names.length > 1;
+  }
+
   private extractStructFields(
     structNode: Parser.SyntaxNode,
     symbols: Omit<SymbolRecord, 'fileId' | 'symbolId'>[],
@@ -211,47 +611,82 @@ export class GoExtractor {
     sourceLines: string[],
     structName: string,
     currentDepth: number = 0
-  ): void {
+  ): boolean {
     // struct_type contains field_declaration_list as a child
     const fieldList = structNode.namedChildren.find(c => c.type === 'field_declaration_list');
-    if (!fieldList) return;
+    if (!fieldList) return false;
+
+    // Whether any nested struct below this one was cut off by the depth limit
+    let truncated = false;
+    // Position of the next field in declaration order; `X, Y float64` takes two
+    let fieldIndex = 0;
 
     for (const field of fieldList.namedChildren) {
       if (field.type === 'field_declaration') {
-        const nameNode = field.childForFieldName('name');
+        const nameNodes = field.childrenForFieldName('name');
         const typeNode = field.childForFieldName('type');
         
-        if (nameNode) {
+        for (const nameNode of nameNodes) {
           const name = nameNode.text;
           const qualifiedName = `${structName}.${name}`;
-          const exported = name.length > 0 && name[0] === name[0].toUpperCase();
+          const exported = isExportedName(name);
           
           // 提取类型信息
-          const fieldType = typeNode ? typeNode.text : '';
+          const fieldType = typeNode ? typeString(typeNode) : '';
+          const tags = this.extractFieldTags(field);
+          const details: SymbolDetails = { fieldIndex: fieldIndex++ };
+          if (fieldType) details.type = fieldType;
+          const inlineStruct = typeNode && this.extractInlineStruct(typeNode, currentDepth + 1);
+          if (inlineStruct) details.inlineStruct = inlineStruct;
+          if (tags) details.tags = tags;
+          const doc = this.extractDocComment(field);
+          if (doc) details.doc = doc;
+          const comment = this.extractLineComment(field);
+          if (comment) details.comment = comment;
           
-          symbols.push({
+          const fieldSymbol: Omit<SymbolRecord, 'fileId' | 'symbolId'> = {
             language,
             kind: 'field',
             name,
             qualifiedName,
-            startLine: field.startPosition.row + 1,
-            startCol: field.startPosition.column,
+            startLine: nameNode.startPosition.row + 1, // X and Y in `X, Y float64` each start at their own name
+            startCol: nameNode.startPosition.column + 1,
             endLine: field.endPosition.row + 1,
-            endCol: field.endPosition.column,
+            endCol: field.endPosition.column + 1,
             signature: fieldType ? `${name} ${fieldType}` : undefined,
             exported,
-          });
+          };
+          symbols.push(fieldSymbol);
 
           // 递归处理匿名嵌套结构体
-          if (typeNode && typeNode.type === 'struct_type' && currentDepth < this.maxNestedStructDepth) {
-            this.extractStructFields(typeNode, symbols, language, sourceLines, qualifiedName, currentDepth + 1);
+          if (typeNode && typeNode.type === 'struct_type') {
+            const withinLimit = this.maxNestedStructDepth === 0 || currentDepth < this.maxNestedStructDepth;
+            if (!withinLimit || this.extractStructFields(typeNode, symbols, language, sourceLines, qualifiedName, currentDepth + 1)) {
+              // 超出深度限制：标记该字段，让调用方知道索引被截断
+              details.truncated = true;
+              truncated = true;
+            }
           }
-        } else if (!nameNode && typeNode) {
+          fieldSymbol.details = this.nonEmptyDetails(details);
+        }
+
+        if (nameNodes.length === 0 && typeNode) {
           // 处理嵌入字段（embedded field）
           // 例如: type Employee struct { Person; Company string }
-          const embeddedName = typeNode.text;
+          // 字段名取类型的基础名（*Person、pkg.Person、Box[T] 均为 Person/Box），类型保留原文
+          const embeddedName = this.embeddedFieldName(typeNode);
+          const isPointer = field.children.some(c => c.type === '*');
+          const embeddedType = isPointer ? `*${typeString(typeNode)}` : typeString(typeNode);
           const qualifiedName = `${structName}.${embeddedName}`;
-          const exported = embeddedName.length > 0 && embeddedName[0] === embeddedName[0].toUpperCase();
+          const exported = isExportedName(embeddedName);
+          const tags = this.extractFieldTags(field);
+          const details: SymbolDetails = { type: embeddedType, isEmbedded: true, fieldIndex: fieldIndex++ };
+          if (isPointer) details.isPointer = true;
+          if (tags) details.tags = tags;
+          const doc = this.extractDocComment(field);
+          if (doc) details.doc = doc;
+          const comment = this.extractLineComment(field);
+          if (comment) details.comment = comment;
           
           symbols.push({
             language,
@@ -259,14 +694,273 @@ export class GoExtractor {
             name: embeddedName,
             qualifiedName,
             startLine: field.startPosition.row + 1,
-            startCol: field.startPosition.column,
+            startCol: field.startPosition.column + 1,
             endLine: field.endPosition.row + 1,
-            endCol: field.endPosition.column,
+            endCol: field.endPosition.column + 1,
+            signature: embeddedType,
             exported,
+            details: this.nonEmptyDetails(details),
           });
         }
       }
     }
+
+    return truncated;
+  }
+
+  /**
+   * The implicit name of an embedded field, per the Go spec: the unqualified
+   * type name without pointer or type arguments.
+   */
+  private embeddedFieldName(typeNode: Parser.SyntaxNode): string {
+    let node = typeNode;
+    if (node.type === 'pointer_type') {
+      node = node.namedChildren[0] ?? node;
+    }
+    if (node.type === 'generic_type') {
+      node = node.childForFieldName('type') ?? node;
+    }
+    if (node.type === 'qualified_type') {
+      node = node.childForFieldName('name') ?? node;
+    }
+    return node.text;
+  }
+
+  /**
+   * Record details.typeRef on struct fields: which named type the field's
+   * type refers to and, for a qualified one, the import path it comes from.
+   * Type parameters of the enclosing generic type are not named types.
+   */
+  private resolveFieldTypeRefs(symbols: ExtractionResult['symbols'], imports: ExtractionResult['imports']): void {
+    const typeParamsByType = new Map<string, string[]>();
+    for (const symbol of symbols) {
+      if (symbol.kind === 'struct' && symbol.details?.typeParams) {
+        typeParamsByType.set(symbol.qualifiedName, symbol.details.typeParams.map(p => p.name));
+      }
+    }
+
+    for (const symbol of symbols) {
+      if (symbol.kind !== 'field' || !symbol.details?.type) continue;
+      // pkg.Type.Field, or pkg.Type.Field.Nested... for anonymous nested structs
+      const owner = symbol.qualifiedName.split('.').slice(0, 2).join('.');
+      const typeRef = resolveTypeRef(symbol.details.type, imports, typeParamsByType.get(owner));
+      if (typeRef) symbol.details.typeRef = typeRef;
+    }
+  }
+
+  private nonEmptyDetails(details: SymbolDetails): SymbolDetails | undefined {
+    return Object.keys(details).length > 0 ? details : undefined;
+  }
+
+  /**
+   * Pick the node whose range a declared symbol should cover: the whole
+   * declaration for `type X ...` / `var x ...`, or only the spec inside a
+   * grouped `type (...)` / `var (...)` block.
+   */
+  private declarationRangeNode(declaration: Parser.SyntaxNode, spec: Parser.SyntaxNode): Parser.SyntaxNode {
+    const grouped = declaration.children.some(c => c.type === '(');
+    return grouped ? spec : declaration;
+  }
+
+  /**
+   * The type of a constant declared without one. Converting a value
+   * (`Color(1)`, `time.Duration(5)`), naming a typed constant or calling
+   * len, cap or unsafe.Sizeof/Alignof/Offsetof (int, uintptr) gives a
+   * typed constant; anything else is untyped, with the default type Go
+   * gives it where a type is needed (1000 → int, 3.14 → float64, iota →
+   * int) when that can be told from the expression.
+   */
+  private untypedConstType(valueNode: Parser.SyntaxNode, details: SymbolDetails): SymbolDetails {
+    let node = valueNode;
+    while (node.type === 'parenthesized_expression' && node.namedChildren[0]) {
+      node = node.namedChildren[0];
+    }
+
+    if (node.type === 'call_expression') {
+      const fn = node.childForFieldName('function');
+      if (fn && Object.hasOwn(CONST_BUILTIN_TYPES, fn.text)) {
+        return { type: CONST_BUILTIN_TYPES[fn.text] };
+      }
+      // Constant expressions only call builtins; any other call is a conversion
+      if (fn && !Object.hasOwn(CONST_BUILTIN_DEFAULTS, fn.text)) {
+        return { type: fn.type.endsWith('_type') ? typeString(fn) : fn.text };
+      }
+      const defaultType = fn ? CONST_BUILTIN_DEFAULTS[fn.text] : '';
+      return { untyped: true, ...(defaultType ? { defaultType } : {}) };
+    }
+    if (node.type === 'binary_expression' && !COMPARISON_OPERATORS.has(node.childForFieldName('operator')?.text ?? '')) {
+      // A typed operand makes the result typed; a shift has the type of its left operand
+      const isShift = ['<<', '>>'].includes(node.childForFieldName('operator')?.text ?? '');
+      const operands = [node.childForFieldName('left'), isShift ? null : node.childForFieldName('right')];
+      for (const operand of operands) {
+        const operandType = operand && this.untypedConstType(operand, {});
+        if (operandType?.type) return { type: operandType.type };
+      }
+    }
+    if (node.type === 'identifier') {
+      const named = this.constTypes.get(node.text);
+      if (named?.type) return { type: named.type };
+      if (named) return { untyped: true, ...(named.defaultType ? { defaultType: named.defaultType } : {}) };
+      if (node.text === 'true' || node.text === 'false') return { untyped: true, defaultType: 'bool' };
+    }
+
+    const defaultType = inferExpressionType(node, this.resultTypes) ?? (details.intValue !== undefined ? 'int' : undefined);
+    return { untyped: true, ...(defaultType ? { defaultType } : {}) };
+  }
+
+  /**
+   * Record a constant's value expression and, for integer expressions built
+   * from literals, iota, earlier constants and arithmetic, its folded value.
+   */
+  private evaluateConstValue(valueNode: Parser.SyntaxNode, iota: number): SymbolDetails {
+    const value = valueNode.text;
+    const folded = this.foldIntExpression(valueNode, BigInt(iota));
+    if (folded !== null && folded >= BigInt(Number.MIN_SAFE_INTEGER) && folded <= BigInt(Number.MAX_SAFE_INTEGER)) {
+      return { value, valueKnown: true, intValue: Number(folded) };
+    }
+    // A lone string, float, rune or boolean literal is known even though it isn't an integer
+    const literalTypes = ['interpreted_string_literal', 'raw_string_literal', 'float_literal', 'rune_literal', 'true', 'false'];
+    return { value, valueKnown: literalTypes.includes(valueNode.type) };
+  }
+
+  private foldIntExpression(node: Parser.SyntaxNode, iota: bigint): bigint | null {
+    switch (node.type) {
+      case 'int_literal': {
+        const digits = node.text.replace(/_/g, '');
+        try {
+          // BigInt understands 0x/0o/0b; a bare leading 0 is octal in Go
+          return /^0[0-7]+$/.test(digits) ? BigInt(`0o${digits.slice(1)}`) : BigInt(digits);
+        } catch {
+          return null;
+        }
+      }
+      case 'iota':
+        return iota;
+      case 'identifier':
+        if (node.text === 'iota') return iota;
+        return this.constValues.get(node.text) ?? null;
+      case 'parenthesized_expression':
+        return node.namedChildren.length === 1 ? this.foldIntExpression(node.namedChildren[0], iota) : null;
+      case 'unary_expression': {
+        const operand = node.childForFieldName('operand');
+        const operator = node.childForFieldName('operator')?.text;
+        const value = operand ? this.foldIntExpression(operand, iota) : null;
+        if (value === null) return null;
+        if (operator === '-') return -value;
+        if (operator === '+') return value;
+        if (operator === '^') return ~value;
+        return null;
+      }
+      case 'binary_expression': {
+        const left = node.childForFieldName('left');
+        const right = node.childForFieldName('right');
+        const operator = node.childForFieldName('operator')?.text;
+        const a = left ? this.foldIntExpression(left, iota) : null;
+        const b = right ? this.foldIntExpression(right, iota) : null;
+        if (a === null || b === null) return null;
+        switch (operator) {
+          case '+': return a + b;
+          case '-': return a - b;
+          case '*': return a * b;
+          case '/': return b === 0n ? null : a / b;
+          case '%': return b === 0n ? null : a % b;
+          case '<<': return b < 0n || b > 1024n ? null : a << b;
+          case '>>': return b < 0n ? null : a >> b;
+          case '&': return a & b;
+          case '|': return a | b;
+          case '^': return a ^ b;
+          case '&^': return a & ~b;
+          default: return null;
+        }
+      }
+      default:
+        return null;
+    }
+  }
+
+  /**
+   * Doc comments for a declared name. In a grouped `const (...)` block the
+   * comment above the block becomes groupDoc and the entry's own comment
+   * becomes doc; an ungrouped declaration only has a doc.
+   */
+  private extractDeclarationDocs(declaration: Parser.SyntaxNode, spec: Parser.SyntaxNode): SymbolDetails {
+    const details: SymbolDetails = {};
+
+    if (declaration.children.some(c => c.type === '(')) {
+      const doc = this.extractDocComment(spec, true);
+      const groupDoc = this.extractDocComment(declaration);
+      if (doc) details.doc = doc;
+      if (groupDoc) details.groupDoc = groupDoc;
+    } else {
+      const doc = this.extractDocComment(declaration);
+      if (doc) details.doc = doc;
+    }
+
+    return details;
+  }
+
+  /**
+   * Collect the comment group directly above a node: consecutive comments
+   * with no blank line between them or the node. A comment trailing other
+   * code on its line ends the group. With allowLineComment, a comment on the
+   * node's own last line (`A = 1 // note`) is used when there is no group.
+   */
+  private extractDocComment(node: Parser.SyntaxNode, allowLineComment = false): string | undefined {
+    const lines: string[] = [];
+    let expectedEndRow = node.startPosition.row - 1;
+    let sibling = node.previousSibling;
+
+    while (sibling && sibling.type === 'comment' && sibling.endPosition.row === expectedEndRow) {
+      const before = sibling.previousSibling;
+      if (before && before.endPosition.row === sibling.startPosition.row) {
+        break;
+      }
+      lines.unshift(...this.commentText(sibling));
+      expectedEndRow = sibling.startPosition.row - 1;
+      sibling = before;
+    }
+
+    const doc = lines.join('\n').trim();
+    return doc || (allowLineComment ? this.extractLineComment(node) : undefined);
+  }
+
+  /**
+   * The comment trailing a node on its last line, e.g. `Street string // 街道`
+   */
+  private extractLineComment(node: Parser.SyntaxNode): string | undefined {
+    const next = node.nextSibling;
+    if (!next || next.type !== 'comment' || next.startPosition.row !== node.endPosition.row) {
+      return undefined;
+    }
+    const comment = this.commentText(next).join('\n').trim();
+    return comment || undefined;
+  }
+
+  private commentText(comment: Parser.SyntaxNode): string[] {
+    const text = comment.text;
+    // Directives such as //go:generate or //nolint:errcheck are not documentation
+    if (parseDirective(text)) {
+      return [];
+    }
+    if (text.startsWith('//')) {
+      return [text.slice(2).trimStart()];
+    }
+    // /* ... */ block comment
+    return text
+      .slice(2, -2)
+      .split('\n')
+      .map(line => line.trim().replace(/^\* ?/, ''));
+  }
+
+  private extractFieldTags(field: Parser.SyntaxNode): Record<string, string> | undefined {
+    const tagNode = field.childForFieldName('tag');
+    if (!tagNode) return undefined;
+
+    const raw = tagLiteralValue(tagNode.text);
+    if (raw === null) return undefined;
+
+    const tags = parseStructTag(raw);
+    return Object.keys(tags).length > 0 ? tags : undefined;
   }
 
   private extractInterfaceMethods(
@@ -283,7 +977,10 @@ export class GoExtractor {
         if (nameNode) {
           const name = nameNode.text;
           const qualifiedName = `${interfaceName}.${name}`;
-          const exported = name.length > 0 && name[0] === name[0].toUpperCase();
+          const exported = isExportedName(name);
+          const details = this.extractParamsAndResults(child);
+          const doc = this.extractDocComment(child, true);
+          if (doc) details.doc = doc;
           
           symbols.push({
             language,
@@ -291,17 +988,43 @@ export class GoExtractor {
             name,
             qualifiedName,
             startLine: child.startPosition.row + 1,
-            startCol: child.startPosition.column,
+            startCol: child.startPosition.column + 1,
             endLine: child.endPosition.row + 1,
-            endCol: child.endPosition.column,
-            signature: this.extractSignature(child, sourceLines),
+            endCol: child.endPosition.column + 1,
+            // Full method spec, e.g. "Validate() error"
+            signature: child.text.replace(/\s+/g, ' '),
             exported,
+            details: this.nonEmptyDetails(details),
           });
         }
       }
     }
   }
 
+  /**
+   * Collect interfaces embedded directly in an interface body, stored
+   * verbatim (`io.Reader`, `Closer`). Type-set elements such as
+   * `~int | ~string` are constraints, not embeddings, and are skipped.
+   */
+  private extractEmbeddedInterfaces(interfaceNode: Parser.SyntaxNode): string[] {
+    const embedded: string[] = [];
+    const namedTypes = ['type_identifier', 'qualified_type', 'generic_type'];
+
+    for (const child of interfaceNode.namedChildren) {
+      if (namedTypes.includes(child.type)) {
+        embedded.push(child.text);
+      } else if (
+        (child.type === 'type_elem' || child.type === 'constraint_elem') &&
+        child.namedChildren.length === 1 &&
+        namedTypes.includes(child.namedChildren[0].type)
+      ) {
+        embedded.push(child.namedChildren[0].text);
+      }
+    }
+
+    return embedded;
+  }
+
   private extractCallsAndReferences(
     node: Parser.SyntaxNode,
     calls: Array<{
@@ -332,18 +1055,18 @@ export class GoExtractor {
           callerName: '', // Will be resolved later
           calleeName,
           siteStartLine: node.startPosition.row + 1,
-          siteStartCol: node.startPosition.column,
+          siteStartCol: node.startPosition.column + 1,
           siteEndLine: node.endPosition.row + 1,
-          siteEndCol: node.endPosition.column,
+          siteEndCol: node.endPosition.column + 1,
         });
 
         references.push({
           name: calleeName,
           refKind: 'call',
           startLine: functionNode.startPosition.row + 1,
-          startCol: functionNode.startPosition.column,
+          startCol: functionNode.startPosition.column + 1,
           endLine: functionNode.endPosition.row + 1,
-          endCol: functionNode.endPosition.column,
+          endCol: functionNode.endPosition.column + 1,
         });
       }
     }
@@ -384,9 +1107,9 @@ export class GoExtractor {
         name,
         refKind,
         startLine: node.startPosition.row + 1,
-        startCol: node.startPosition.column,
+        startCol: node.startPosition.column + 1,
         endLine: node.endPosition.row + 1,
-        endCol: node.endPosition.column,
+        endCol: node.endPosition.column + 1,
       });
     }
 
@@ -396,21 +1119,390 @@ export class GoExtractor {
     }
   }
 
-  private extractReceiverType(receiverNode: Parser.SyntaxNode): string {
+  /**
+   * Exported-looking names the file uses: as values, calls, types, selected
+   * fields and methods (`u.Email`, `u.Validate()`) or composite literal keys.
+   * Declaring occurrences are left out, so a symbol used nowhere but its own
+   * declaration does not appear. Unexported names are not collected because
+   * only exported symbols are checked for use (see QueryEngine.unusedExported).
+   */
+  private extractUsedNames(rootNode: Parser.SyntaxNode): string[] {
+    const names = new Set<string>();
+    const visit = (node: Parser.SyntaxNode): void => {
+      if (node.type === 'identifier' || node.type === 'type_identifier' || node.type === 'field_identifier') {
+        const parent = node.parent;
+        const declares = parent && DECLARING_PARENTS.has(parent.type) &&
+          parent.childrenForFieldName('name').some(nameNode => nameNode.startIndex === node.startIndex);
+        if (!declares && /^\p{Lu}/u.test(node.text)) {
+          names.add(node.text);
+        }
+      }
+      for (const child of node.namedChildren) {
+        visit(child);
+      }
+    };
+    visit(rootNode);
+    return Array.from(names).sort();
+  }
+
+  /**
+   * Directive comments of the file in source order. A directive in the
+   * comment group directly above a declaration, or trailing the line the
+   * declaration starts on, is attached to the symbol declared there.
+   */
+  private extractDirectives(
+    rootNode: Parser.SyntaxNode,
+    symbols: ExtractionResult['symbols']
+  ): Directive[] {
+    const directives: Directive[] = [];
+    const visit = (node: Parser.SyntaxNode): void => {
+      if (node.type === 'comment') {
+        const parsed = parseDirective(node.text);
+        if (parsed) {
+          const declaration = this.directiveTarget(node);
+          const symbol = declaration && symbols.find(s => s.startLine === declaration.startPosition.row + 1);
+          directives.push({ ...parsed, line: node.startPosition.row + 1, ...(symbol ? { symbol: symbol.qualifiedName } : {}) });
+        }
+        return;
+      }
+      for (const child of node.children) {
+        visit(child);
+      }
+    };
+    visit(rootNode);
+    return directives;
+  }
+
+  /**
+   * The node a directive comment belongs to: the first node after its
+   * comment group when no blank line separates them, otherwise a node that
+   * starts on the comment's own line before it
+   */
+  private directiveTarget(comment: Parser.SyntaxNode): Parser.SyntaxNode | null {
+    const before = comment.previousSibling;
+    if (before && before.type !== 'comment' && before.startPosition.row === comment.startPosition.row) {
+      return before;
+    }
+    if (before && before.endPosition.row === comment.startPosition.row) {
+      return null;
+    }
+
+    let last = comment;
+    let next = comment.nextSibling;
+    while (next && next.type === 'comment' && next.startPosition.row === last.endPosition.row + 1) {
+      last = next;
+      next = next.nextSibling;
+    }
+    return next && next.type !== 'comment' && next.startPosition.row === last.endPosition.row + 1 ? next : null;
+  }
+
+  /**
+   * Read a method receiver such as `(s *Stack[T])`: name is 's' (empty when
+   * omitted), typeText is 'Stack[T]' without the pointer, baseType is 'Stack'.
+   */
+  private extractReceiverType(receiverNode: Parser.SyntaxNode): {
+    name: string;
+    typeText: string;
+    baseType: string;
+    typeParams: string[];
+    isPointer: boolean;
+  } {
     // receiver is typically (parameterList) with type inside
     const paramList = receiverNode.namedChildren[0];
     if (paramList && paramList.type === 'parameter_declaration') {
-      const typeNode = paramList.childForFieldName('type');
+      const name = paramList.childForFieldName('name')?.text ?? '';
+      let typeNode = paramList.childForFieldName('type');
+      // Handle pointer types like *MyStruct
+      const isPointer = typeNode?.type === 'pointer_type';
+      if (typeNode && typeNode.type === 'pointer_type') {
+        typeNode = typeNode.namedChildren[0] ?? typeNode;
+      }
       if (typeNode) {
-        // Handle pointer types like *MyStruct
-        if (typeNode.type === 'pointer_type') {
-          const innerType = typeNode.namedChildren[0];
-          return innerType ? innerType.text : typeNode.text;
+        // Handle generic receivers like Stack[T] or *Stack[K, V]
+        if (typeNode.type === 'generic_type') {
+          const baseNode = typeNode.childForFieldName('type');
+          const argsNode = typeNode.childForFieldName('type_arguments');
+          return {
+            name,
+            typeText: typeString(typeNode),
+            baseType: baseNode ? baseNode.text : typeNode.text.split('[')[0],
+            typeParams: argsNode ? argsNode.namedChildren.map(arg => arg.text) : [],
+            isPointer,
+          };
+        }
+        return { name, typeText: typeString(typeNode), baseType: typeNode.text, typeParams: [], isPointer };
+      }
+    }
+    return { name: '', typeText: '', baseType: '', typeParams: [], isPointer: false };
+  }
+
+  /**
+   * Read parameters and results from a function, method or interface method.
+   * Grouped declarations (`a, b int`) expand to one Param per name.
+   */
+  private extractParamsAndResults(node: Parser.SyntaxNode): Pick<SymbolDetails, 'params' | 'results'> {
+    const details: Pick<SymbolDetails, 'params' | 'results'> = {};
+
+    const paramsNode = node.childForFieldName('parameters');
+    if (paramsNode) {
+      const params = this.extractParamList(paramsNode);
+      if (params.length > 0) details.params = params;
+    }
+
+    const resultNode = node.childForFieldName('result');
+    if (resultNode) {
+      // A single unnamed result is a bare type rather than a parameter_list
+      details.results = resultNode.type === 'parameter_list'
+        ? this.extractParamList(resultNode)
+        : [this.param('', resultNode)];
+    }
+
+    return details;
+  }
+
+  private extractParamList(listNode: Parser.SyntaxNode): Param[] {
+    const params: Param[] = [];
+
+    for (const decl of listNode.namedChildren) {
+      if (decl.type !== 'parameter_declaration' && decl.type !== 'variadic_parameter_declaration') {
+        continue;
+      }
+
+      const typeNode = decl.childForFieldName('type');
+      const isVariadic = decl.type === 'variadic_parameter_declaration';
+      const names = decl.childrenForFieldName('name');
+
+      for (const name of names.length > 0 ? names.map(n => n.text) : ['']) {
+        const param = this.param(name, typeNode);
+        params.push(isVariadic ? { ...param, isVariadic } : param);
+      }
+    }
+
+    return params;
+  }
+
+  private param(name: string, typeNode: Parser.SyntaxNode | null): Param {
+    const param: Param = { name, type: typeNode ? typeString(typeNode) : '' };
+    const inlineStruct = typeNode && this.extractInlineStruct(typeNode, 1);
+    if (inlineStruct) param.inlineStruct = inlineStruct;
+    return param;
+  }
+
+  /**
+   * The fields of an anonymous struct type, looking through pointers, slices
+   * and arrays (`[]struct{ A int }`); undefined for any other type. depth is
+   * the nesting level of this struct, 1 for one written directly in a
+   * declaration: past maxNestedStructDepth the struct is marked truncated
+   * instead of expanded, as nested struct fields are.
+   */
+  private extractInlineStruct(typeNode: Parser.SyntaxNode, depth: number): InlineStruct | undefined {
+    let node: Parser.SyntaxNode | null = typeNode;
+    while (node && node.type !== 'struct_type') {
+      if (node.type === 'pointer_type') {
+        node = node.namedChildren[0] ?? null;
+      } else if (node.type === 'slice_type' || node.type === 'array_type' || node.type === 'implicit_length_array_type') {
+        node = node.childForFieldName('element');
+      } else {
+        return undefined;
+      }
+    }
+    if (!node) return undefined;
+    if (this.maxNestedStructDepth !== 0 && depth > this.maxNestedStructDepth) {
+      return { fields: [], truncated: true };
+    }
+
+    const fields: InlineField[] = [];
+    const fieldList = node.namedChildren.find(c => c.type === 'field_declaration_list');
+    for (const field of fieldList?.namedChildren ?? []) {
+      if (field.type !== 'field_declaration') continue;
+      const fieldTypeNode = field.childForFieldName('type');
+      if (!fieldTypeNode) continue;
+
+      const nameNodes = field.childrenForFieldName('name');
+      const isPointer = field.children.some(c => c.type === '*');
+      const base: InlineField = nameNodes.length === 0
+        ? {
+            name: this.embeddedFieldName(fieldTypeNode),
+            type: `${isPointer ? '*' : ''}${typeString(fieldTypeNode)}`,
+            isEmbedded: true,
+            ...(isPointer ? { isPointer } : {}),
+          }
+        : { name: '', type: typeString(fieldTypeNode) };
+      const tags = this.extractFieldTags(field);
+      if (tags) base.tags = tags;
+      const inlineStruct = this.extractInlineStruct(fieldTypeNode, depth + 1);
+      if (inlineStruct) base.inlineStruct = inlineStruct;
+
+      for (const name of nameNodes.length > 0 ? nameNodes.map(n => n.text) : [base.name]) {
+        fields.push({ ...base, name });
+      }
+    }
+    return { fields };
+  }
+
+  private extractTypeParams(node: Parser.SyntaxNode): TypeParam[] {
+    // Both function_declaration and type_spec expose a type_parameters field
+    const paramList = node.childForFieldName('type_parameters');
+    if (!paramList) return [];
+
+    const typeParams: TypeParam[] = [];
+    for (const decl of paramList.namedChildren) {
+      if (decl.type !== 'type_parameter_declaration' && decl.type !== 'parameter_declaration') {
+        continue;
+      }
+      // [K comparable, V any] or [K, V any]: every name shares the declaration's constraint
+      const constraintNode = decl.childForFieldName('type');
+      const constraint = constraintNode ? typeString(constraintNode) : '';
+      for (const nameNode of decl.childrenForFieldName('name')) {
+        typeParams.push({ name: nameNode.text, constraint });
+      }
+    }
+    return typeParams;
+  }
+
+  /**
+   * Resolve calls inside function and method bodies to the qualified name of
+   * the callee. Handles same-package calls (`ValidateEmail(email)`) and method
+   * calls on variables whose type is known statically: the receiver, typed
+   * parameters, `var x T`, `x := T{}` / `&T{}` / `new(T)`, and `x := NewT()`
+   * when NewT is declared in this file. Calls through interface values,
+   * function variables or into other packages are not resolved.
+   */
+  private extractCallEdges(rootNode: Parser.SyntaxNode, packageName: string): ExtractionResult['callEdges'] {
+    const edges: ExtractionResult['callEdges'] = [];
+
+    // Constructor-style functions: name -> named type of their first result
+    const resultTypes = new Map<string, string>();
+    for (const decl of rootNode.namedChildren) {
+      if (decl.type !== 'function_declaration') continue;
+      const name = decl.childForFieldName('name')?.text;
+      const result = this.extractParamsAndResults(decl).results?.[0];
+      const resultType = result ? this.namedTypeOf(result.type) : null;
+      if (name && resultType) {
+        resultTypes.set(name, resultType);
+      }
+    }
+
+    for (const decl of rootNode.namedChildren) {
+      if (decl.type !== 'function_declaration' && decl.type !== 'method_declaration') continue;
+      const nameNode = decl.childForFieldName('name');
+      const body = decl.childForFieldName('body');
+      if (!nameNode || !body) continue;
+
+      // Variables in scope -> their named type, or null when it isn't known
+      const variables = new Map<string, string | null>();
+      let callerQualifiedName = `${packageName}.${nameNode.text}`;
+
+      const receiverNode = decl.childForFieldName('receiver');
+      if (receiverNode) {
+        const receiver = this.extractReceiverType(receiverNode);
+        if (receiver.baseType) {
+          callerQualifiedName = `${packageName}.${receiver.baseType}.${nameNode.text}`;
+          if (receiver.name) variables.set(receiver.name, receiver.baseType);
         }
-        return typeNode.text;
       }
+      for (const param of this.extractParamsAndResults(decl).params ?? []) {
+        if (param.name) {
+          variables.set(param.name, param.isVariadic ? null : this.namedTypeOf(param.type));
+        }
+      }
+
+      const inferType = (expr: Parser.SyntaxNode): string | null => {
+        if (expr.type === 'composite_literal') {
+          const typeNode = expr.childForFieldName('type');
+          return typeNode ? this.namedTypeOf(typeNode.text) : null;
+        }
+        if (expr.type === 'unary_expression' && expr.childForFieldName('operator')?.text === '&') {
+          const operand = expr.childForFieldName('operand');
+          return operand ? inferType(operand) : null;
+        }
+        if (expr.type === 'call_expression') {
+          const fn = expr.childForFieldName('function');
+          if (fn?.type !== 'identifier') return null;
+          if (fn.text === 'new') {
+            const typeArg = expr.childForFieldName('arguments')?.namedChildren[0];
+            return typeArg ? this.namedTypeOf(typeArg.text) : null;
+          }
+          return variables.has(fn.text) ? null : resultTypes.get(fn.text) ?? null;
+        }
+        if (expr.type === 'identifier') {
+          return variables.get(expr.text) ?? null;
+        }
+        return null;
+      };
+
+      const visit = (node: Parser.SyntaxNode): void => {
+        if (node.type === 'short_var_declaration') {
+          const left = node.childForFieldName('left')?.namedChildren ?? [];
+          const right = node.childForFieldName('right')?.namedChildren ?? [];
+          left.forEach((name, i) => {
+            variables.set(name.text, left.length === right.length ? inferType(right[i]) : null);
+          });
+        } else if (node.type === 'var_spec') {
+          const typeNode = node.childForFieldName('type');
+          const values = node.childForFieldName('value')?.namedChildren ?? [];
+          node.childrenForFieldName('name').forEach((name, i) => {
+            const type = typeNode ? this.namedTypeOf(typeNode.text) : values[i] ? inferType(values[i]) : null;
+            variables.set(name.text, type);
+          });
+        } else if (node.type === 'call_expression') {
+          const callee = this.resolveCallee(node, packageName, variables);
+          if (callee) {
+            edges.push({
+              callerQualifiedName,
+              calleeQualifiedName: callee,
+              siteStartLine: node.startPosition.row + 1,
+              siteStartCol: node.startPosition.column + 1,
+              siteEndLine: node.endPosition.row + 1,
+              siteEndCol: node.endPosition.column + 1,
+            });
+          }
+        }
+
+        for (const child of node.namedChildren) {
+          visit(child);
+        }
+      };
+      visit(body);
     }
-    return '';
+
+    return edges;
+  }
+
+  private resolveCallee(
+    call: Parser.SyntaxNode,
+    packageName: string,
+    variables: Map<string, string | null>
+  ): string | null {
+    const fn = call.childForFieldName('function');
+    if (!fn) return null;
+
+    if (fn.type === 'identifier') {
+      // A local function variable, or a builtin such as len/append
+      if (variables.has(fn.text) || GO_BUILTIN_FUNCS.has(fn.text)) return null;
+      return `${packageName}.${fn.text}`;
+    }
+
+    if (fn.type === 'selector_expression') {
+      const operand = fn.childForFieldName('operand');
+      const field = fn.childForFieldName('field');
+      if (operand?.type === 'identifier' && field) {
+        const type = variables.get(operand.text);
+        return type ? `${packageName}.${type}.${field.text}` : null;
+      }
+    }
+
+    return null;
+  }
+
+  /**
+   * The local named type behind a type expression: `*UserService` and
+   * `Stack[T]` give their base name; qualified, slice, map and other
+   * composite types give null.
+   */
+  private namedTypeOf(typeText: string): string | null {
+    const base = typeText.replace(/^\*/, '').replace(/\[.*\]$/s, '');
+    return /^[A-Za-z_][A-Za-z0-9_]*$/.test(base) ? base : null;
   }
 
   private extractCalleeName(node: Parser.SyntaxNode): string {
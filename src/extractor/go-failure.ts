@@ -0,0 +1,32 @@
+/**
+ * How Go functions can fail: by panicking or by returning an error
+ */
+
+import type Parser from 'tree-sitter';
+import type { Param } from '../core/types.js';
+
+/**
+ * Whether a function or method body calls the builtin panic. Function
+ * literals inside the body count, deferred or not, and so do panics a
+ * deferred recover would catch. A local function named panic would be
+ * taken for the builtin.
+ */
+export function callsPanic(fnNode: Parser.SyntaxNode): boolean {
+  const visit = (node: Parser.SyntaxNode): boolean => {
+    if (node.type === 'call_expression') {
+      const callee = node.childForFieldName('function');
+      if (callee?.type === 'identifier' && callee.text === 'panic') return true;
+    }
+    return node.namedChildren.some(visit);
+  };
+
+  const body = fnNode.childForFieldName('body');
+  return body ? visit(body) : false;
+}
+
+/**
+ * Whether any result is of the predeclared type error (named or not)
+ */
+export function returnsError(results: Param[] = []): boolean {
+  return results.some(result => result.type === 'error');
+}
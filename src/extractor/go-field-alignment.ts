@@ -0,0 +1,97 @@
+/**
+ * Struct size and field ordering analysis, in the spirit of the fieldalignment
+ * vet analyzer. Sizes follow the gc compiler on 64-bit platforms.
+ */
+
+import type { FieldAlignmentWarning, SymbolRecord } from '../core/types.js';
+
+interface Layout {
+  size: number;
+  align: number;
+}
+
+const WORD = 8;
+
+const PREDECLARED_LAYOUTS: Record<string, Layout> = {
+  bool: { size: 1, align: 1 },
+  int8: { size: 1, align: 1 },
+  uint8: { size: 1, align: 1 },
+  byte: { size: 1, align: 1 },
+  int16: { size: 2, align: 2 },
+  uint16: { size: 2, align: 2 },
+  int32: { size: 4, align: 4 },
+  uint32: { size: 4, align: 4 },
+  rune: { size: 4, align: 4 },
+  float32: { size: 4, align: 4 },
+  int: { size: WORD, align: WORD },
+  uint: { size: WORD, align: WORD },
+  uintptr: { size: WORD, align: WORD },
+  int64: { size: 8, align: 8 },
+  uint64: { size: 8, align: 8 },
+  float64: { size: 8, align: 8 },
+  complex64: { size: 8, align: 4 },
+  complex128: { size: 16, align: 8 },
+  string: { size: 2 * WORD, align: WORD },
+  error: { size: 2 * WORD, align: WORD },
+  any: { size: 2 * WORD, align: WORD },
+  'unsafe.Pointer': { size: WORD, align: WORD },
+};
+
+function alignUp(offset: number, align: number): number {
+  return Math.ceil(offset / align) * align;
+}
+
+/**
+ * Size and alignment of a type as rendered by typeString, or null when it
+ * depends on a named type declared elsewhere (or a type parameter)
+ */
+export function typeLayout(type: string): Layout | null {
+  if (PREDECLARED_LAYOUTS[type]) return PREDECLARED_LAYOUTS[type];
+  if (type.startsWith('*') || type.startsWith('map[') || type.startsWith('func(')) return { size: WORD, align: WORD };
+  if (type.startsWith('chan ') || type.startsWith('chan<- ') || type.startsWith('<-chan ')) return { size: WORD, align: WORD };
+  if (type.startsWith('[]')) return { size: 3 * WORD, align: WORD };
+  if (type.startsWith('interface{')) return { size: 2 * WORD, align: WORD };
+  if (type === 'struct{}') return { size: 0, align: 1 };
+
+  const array = /^\[(\d+)\](.+)$/.exec(type);
+  if (array) {
+    const element = typeLayout(array[2]);
+    return element ? { size: Number(array[1]) * element.size, align: element.align } : null;
+  }
+  return null;
+}
+
+function structSize(layouts: Layout[]): number {
+  let offset = 0;
+  let maxAlign = 1;
+  for (const { size, align } of layouts) {
+    offset = alignUp(offset, align) + size;
+    maxAlign = Math.max(maxAlign, align);
+  }
+  return alignUp(offset, maxAlign);
+}
+
+/**
+ * Compare a struct's size with the size it would have with its fields sorted
+ * by alignment, then size, largest first. fields must be the struct's direct
+ * fields in declaration order. Returns null when the struct is already
+ * optimal or a field's size cannot be determined from its type alone.
+ */
+export function analyzeFieldAlignment(
+  fields: Array<Pick<SymbolRecord, 'name' | 'details'>>
+): FieldAlignmentWarning | null {
+  const laidOut: Array<{ name: string; layout: Layout }> = [];
+  for (const field of fields) {
+    const layout = field.details?.type ? typeLayout(field.details.type) : null;
+    if (!layout) return null;
+    laidOut.push({ name: field.name, layout });
+  }
+
+  const optimal = [...laidOut].sort(
+    (a, b) => b.layout.align - a.layout.align || b.layout.size - a.layout.size
+  );
+  const size = structSize(laidOut.map(field => field.layout));
+  const optimalSize = structSize(optimal.map(field => field.layout));
+
+  return optimalSize < size ? { size, optimalSize, optimalOrder: optimal.map(field => field.name) } : null;
+}
@@ -0,0 +1,162 @@
+/**
+ * Go struct tag parsing, compatible with reflect.StructTag conventions
+ */
+
+const SIMPLE_ESCAPES: Record<string, string> = {
+  a: '\x07',
+  b: '\b',
+  f: '\f',
+  n: '\n',
+  r: '\r',
+  t: '\t',
+  v: '\v',
+  '\\': '\\',
+  "'": "'",
+  '"': '"',
+};
+
+/**
+ * Unquote a Go interpreted string literal (including the surrounding quotes).
+ * Returns null when the literal is malformed, mirroring strconv.Unquote errors.
+ */
+export function unquoteGoString(quoted: string): string | null {
+  if (quoted.length < 2 || quoted[0] !== '"' || quoted[quoted.length - 1] !== '"') {
+    return null;
+  }
+
+  const body = quoted.slice(1, -1);
+  let result = '';
+  let i = 0;
+
+  while (i < body.length) {
+    const ch = body[i];
+    if (ch === '"' || ch === '\n') {
+      return null;
+    }
+    if (ch !== '\\') {
+      result += ch;
+      i++;
+      continue;
+    }
+
+    const next = body[i + 1];
+    if (next === undefined) {
+      return null;
+    }
+
+    if (next in SIMPLE_ESCAPES) {
+      result += SIMPLE_ESCAPES[next];
+      i += 2;
+      continue;
+    }
+
+    // \xHH, \uHHHH, \UHHHHHHHH
+    const hexLength = next === 'x' ? 2 : next === 'u' ? 4 : next === 'U' ? 8 : 0;
+    if (hexLength > 0) {
+      const hex = body.slice(i + 2, i + 2 + hexLength);
+      if (hex.length !== hexLength || !/^[0-9a-fA-F]+$/.test(hex)) {
+        return null;
+      }
+      const code = parseInt(hex, 16);
+      result += next === 'x' ? String.fromCharCode(code) : String.fromCodePoint(code);
+      i += 2 + hexLength;
+      continue;
+    }
+
+    // \NNN octal
+    const octal = body.slice(i + 1, i + 4);
+    if (/^[0-7]{3}$/.test(octal)) {
+      result += String.fromCharCode(parseInt(octal, 8));
+      i += 4;
+      continue;
+    }
+
+    return null;
+  }
+
+  return result;
+}
+
+/**
+ * Strip the literal delimiters from a struct tag as written in source.
+ * Raw (`...`) tags are returned verbatim, interpreted ("...") tags are unquoted.
+ */
+export function tagLiteralValue(literal: string): string | null {
+  if (literal.startsWith('`') && literal.endsWith('`') && literal.length >= 2) {
+    return literal.slice(1, -1);
+  }
+  return unquoteGoString(literal);
+}
+
+/**
+ * Parse a struct tag into key/value pairs following reflect.StructTag.Lookup rules.
+ * Values are kept whole: `json:"name,omitempty"` yields { json: 'name,omitempty' }.
+ * Parsing stops at the first malformed pair, as reflect does; the first occurrence
+ * of a duplicated key wins.
+ */
+export function parseStructTag(tag: string): Record<string, string> {
+  const tags: Record<string, string> = {};
+  let rest = tag;
+
+  while (rest !== '') {
+    // Skip leading space
+    let i = 0;
+    while (i < rest.length && rest[i] === ' ') {
+      i++;
+    }
+    rest = rest.slice(i);
+    if (rest === '') {
+      break;
+    }
+
+    // Scan to colon. A space, a quote or a control character is a syntax error.
+    i = 0;
+    while (
+      i < rest.length &&
+      rest.charCodeAt(i) > 0x20 &&
+      rest[i] !== ':' &&
+      rest[i] !== '"' &&
+      rest.charCodeAt(i) !== 0x7f
+    ) {
+      i++;
+    }
+    if (i === 0 || i + 1 >= rest.length || rest[i] !== ':' || rest[i + 1] !== '"') {
+      break;
+    }
+    const name = rest.slice(0, i);
+    rest = rest.slice(i + 1);
+
+    // Scan quoted string to find value
+    i = 1;
+    while (i < rest.length && rest[i] !== '"') {
+      if (rest[i] === '\\') {
+        i++;
+      }
+      i++;
+    }
+    if (i >= rest.length) {
+      break;
+    }
+    const quotedValue = rest.slice(0, i + 1);
+    rest = rest.slice(i + 1);
+
+    const value = unquoteGoString(quotedValue);
+    if (value === null) {
+      break;
+    }
+    if (!(name in tags)) {
+      tags[name] = value;
+    }
+  }
+
+  return tags;
+}
+
+/**
+ * Split a tag value into its name and options, e.g. "name,omitempty"
+ * -> { name: 'name', options: ['omitempty'] }.
+ */
+export function splitTagOptions(value: string): { name: string; options: string[] } {
+  const [name, ...options] = value.split(',');
+  return { name, options };
+}
@@ -0,0 +1,101 @@
+/**
+ * Inference of a Go variable's type from its initializer
+ */
+
+import type Parser from 'tree-sitter';
+import { signatureString, typeString } from './go-type-string.js';
+import { PREDECLARED_TYPES } from './go-type-ref.js';
+
+// Default types of untyped constants: `var n = 1` declares an int
+const LITERAL_TYPES: Record<string, string> = {
+  int_literal: 'int',
+  float_literal: 'float64',
+  imaginary_literal: 'complex128',
+  rune_literal: 'rune',
+  interpreted_string_literal: 'string',
+  raw_string_literal: 'string',
+  true: 'bool',
+  false: 'bool',
+};
+
+const BOOLEAN_OPERATORS = new Set(['==', '!=', '<', '<=', '>', '>=', '&&', '||']);
+
+/**
+ * Result types of the file's top-level functions that return exactly one
+ * value, by function name. Generic functions are left out, since their
+ * result type depends on the type arguments of each call.
+ */
+export function functionResultTypes(rootNode: Parser.SyntaxNode): Map<string, string> {
+  const results = new Map<string, string>();
+  for (const node of rootNode.namedChildren) {
+    if (node.type !== 'function_declaration' || node.childForFieldName('type_parameters')) continue;
+    const name = node.childForFieldName('name');
+    const result = node.childForFieldName('result');
+    if (!name || !result) continue;
+
+    if (result.type !== 'parameter_list') {
+      results.set(name.text, typeString(result));
+      continue;
+    }
+    const decls = result.namedChildren.filter(c => c.type === 'parameter_declaration');
+    const type = decls.length === 1 && decls[0].childrenForFieldName('name').length <= 1 && decls[0].childForFieldName('type');
+    if (type) results.set(name.text, typeString(type));
+  }
+  return results;
+}
+
+/**
+ * The type a variable initialized with expr gets, when the expression alone
+ * tells: literals have their default type (1.5 → float64), composite
+ * literals, conversions, make and new the type they name, comparisons bool,
+ * and calls to a function of resultTypes its result. Undefined when more
+ * analysis is needed, e.g. for a call into another package or arithmetic.
+ */
+export function inferExpressionType(expr: Parser.SyntaxNode, resultTypes: Map<string, string>): string | undefined {
+  switch (expr.type) {
+    case 'parenthesized_expression':
+      return expr.namedChildren[0] && inferExpressionType(expr.namedChildren[0], resultTypes);
+
+    case 'composite_literal': {
+      const type = expr.childForFieldName('type');
+      return type ? typeString(type) : undefined;
+    }
+
+    case 'func_literal':
+      return `func${signatureString(expr)}`;
+
+    case 'type_conversion_expression': {
+      const type = expr.childForFieldName('type');
+      return type ? typeString(type) : undefined;
+    }
+
+    case 'unary_expression': {
+      const operator = expr.childForFieldName('operator')?.text;
+      const operand = expr.childForFieldName('operand');
+      if (operator === '!') return 'bool';
+      if (operator === '&' && operand?.type === 'composite_literal') {
+        const type = inferExpressionType(operand, resultTypes);
+        return type && `*${type}`;
+      }
+      return undefined;
+    }
+
+    case 'binary_expression':
+      return BOOLEAN_OPERATORS.has(expr.childForFieldName('operator')?.text ?? '') ? 'bool' : undefined;
+
+    case 'call_expression': {
+      const fn = expr.childForFieldName('function');
+      const [first] = expr.childForFieldName('arguments')?.namedChildren ?? [];
+      if (!fn) return undefined;
+      if (fn.type.endsWith('_type')) return typeString(fn); // []byte(s)
+      if (fn.type !== 'identifier') return undefined;
+      if (fn.text === 'new') return first && `*${typeString(first)}`;
+      if (fn.text === 'make') return first && typeString(first);
+      if (PREDECLARED_TYPES.has(fn.text)) return fn.text; // int64(n)
+      return resultTypes.get(fn.text);
+    }
+
+    default:
+      return LITERAL_TYPES[expr.type];
+  }
+}
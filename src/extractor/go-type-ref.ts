@@ -0,0 +1,59 @@
+/**
+ * Resolution of the named type a Go type expression refers to
+ */
+
+import type { TypeRef } from '../core/types.js';
+
+export const PREDECLARED_TYPES = new Set([
+  'any', 'bool', 'byte', 'comparable', 'complex64', 'complex128', 'error',
+  'float32', 'float64', 'int', 'int8', 'int16', 'int32', 'int64', 'rune',
+  'string', 'uint', 'uint8', 'uint16', 'uint32', 'uint64', 'uintptr',
+]);
+
+/**
+ * The package name an import path declares by convention: its last element,
+ * skipping a major version element ("github.com/x/y/v2" → y) and dropping a
+ * "go-" prefix or ".vN" suffix ("gopkg.in/yaml.v3" → yaml)
+ */
+export function defaultPackageName(importPath: string): string {
+  const parts = importPath.split('/');
+  let last = parts[parts.length - 1];
+  if (/^v\d+$/.test(last) && parts.length > 1) {
+    last = parts[parts.length - 2];
+  }
+  return last.replace(/^go-/, '').replace(/\.v\d+$/, '');
+}
+
+/**
+ * The named type at the core of a type as rendered by typeString, looking
+ * through pointers, slices, arrays and channels and dropping type arguments:
+ * '[]*http.Client' → http.Client. Null for predeclared types, maps, funcs
+ * and type literals, which name no single type.
+ */
+export function resolveTypeRef(
+  type: string,
+  imports: Array<{ path: string; alias?: string }>,
+  typeParams: string[] = []
+): TypeRef | null {
+  let core = type;
+  for (;;) {
+    const stripped = core.replace(/^(?:\*|\[\d*\]|\[\.\.\.\]|chan<- |<-chan |chan )/, '');
+    if (stripped === core) break;
+    core = stripped;
+  }
+  core = core.replace(/\[.*\]$/, '');
+
+  const match = /^(?:(\w+)\.)?(\w+)$/.exec(core);
+  if (!match) return null;
+  const [, pkgQualifier, name] = match;
+
+  if (!pkgQualifier) {
+    if (PREDECLARED_TYPES.has(name) || typeParams.includes(name)) return null;
+    return { name, isLocal: true };
+  }
+
+  const imp =
+    imports.find(i => i.alias === pkgQualifier) ??
+    imports.find(i => !i.alias && defaultPackageName(i.path) === pkgQualifier);
+  return { name, pkgQualifier, isLocal: false, ...(imp ? { importPath: imp.path } : {}) };
+}
@@ -0,0 +1,138 @@
+/**
+ * Render Go type expressions from the syntax tree in gofmt's canonical form
+ */
+
+import type Parser from 'tree-sitter';
+
+/**
+ * Render a Go type node, e.g. `chan<- int`, `map[string][]*User`,
+ * `func(int) (string, error)` or `[...]byte`. Layout in the source (line
+ * breaks, extra spaces, comments) does not affect the result. Unknown node
+ * types fall back to their source text with whitespace collapsed.
+ */
+export function typeString(node: Parser.SyntaxNode): string {
+  const element = (field: string) => {
+    const child = node.childForFieldName(field);
+    return child ? typeString(child) : '';
+  };
+
+  switch (node.type) {
+    case 'type_identifier':
+    case 'identifier':
+    case 'package_identifier':
+    case 'field_identifier':
+      return node.text;
+
+    case 'qualified_type':
+      return `${element('package')}.${element('name')}`;
+
+    case 'pointer_type':
+      return `*${typeString(node.namedChildren[0])}`;
+
+    case 'slice_type':
+      return `[]${element('element')}`;
+
+    case 'array_type': {
+      const length = node.childForFieldName('length');
+      return `[${length ? collapse(length.text) : ''}]${element('element')}`;
+    }
+
+    case 'implicit_length_array_type':
+      return `[...]${element('element')}`;
+
+    case 'map_type':
+      return `map[${element('key')}]${element('value')}`;
+
+    case 'channel_type': {
+      // `<-chan T` receives, `chan<- T` sends; the arrow position is all that differs
+      const tokens = node.children.filter(c => !c.isNamed).map(c => c.type);
+      const value = element('value');
+      if (tokens[0] === '<-') return `<-chan ${value}`;
+      if (tokens[1] === '<-') return `chan<- ${value}`;
+      return `chan ${value}`;
+    }
+
+    case 'function_type':
+      return `func${signatureString(node)}`;
+
+    case 'generic_type': {
+      const args = node.childForFieldName('type_arguments');
+      const argList = args ? args.namedChildren.map(typeString).join(', ') : '';
+      return `${element('type')}[${argList}]`;
+    }
+
+    case 'parenthesized_type':
+      return `(${typeString(node.namedChildren[0])})`;
+
+    case 'negated_type':
+      return `~${typeString(node.namedChildren[0])}`;
+
+    case 'struct_type': {
+      const fieldList = node.namedChildren.find(c => c.type === 'field_declaration_list');
+      const fields = (fieldList?.namedChildren ?? [])
+        .filter(c => c.type === 'field_declaration')
+        .map(fieldString);
+      return fields.length > 0 ? `struct{ ${fields.join('; ')} }` : 'struct{}';
+    }
+
+    case 'interface_type': {
+      const elems = node.namedChildren
+        .filter(c => c.type !== 'comment')
+        .map(c => (c.type === 'method_elem' ? methodElemString(c) : collapse(c.text)));
+      return elems.length > 0 ? `interface{ ${elems.join('; ')} }` : 'interface{}';
+    }
+
+    default:
+      return collapse(node.text);
+  }
+}
+
+/**
+ * Render the parameters and results of a function, method or function type:
+ * `(a, b int, opts ...Option) (string, error)`.
+ */
+export function signatureString(node: Parser.SyntaxNode): string {
+  const params = node.childForFieldName('parameters');
+  const result = node.childForFieldName('result');
+
+  let signature = params ? parameterListString(params) : '()';
+  if (result) {
+    signature += ` ${result.type === 'parameter_list' ? parameterListString(result) : typeString(result)}`;
+  }
+  return signature;
+}
+
+function parameterListString(list: Parser.SyntaxNode): string {
+  const parts = list.namedChildren
+    .filter(c => c.type === 'parameter_declaration' || c.type === 'variadic_parameter_declaration')
+    .map(decl => {
+      const typeNode = decl.childForFieldName('type');
+      const type = (decl.type === 'variadic_parameter_declaration' ? '...' : '') + (typeNode ? typeString(typeNode) : '');
+      const names = decl.childrenForFieldName('name').map(n => n.text);
+      return names.length > 0 ? `${names.join(', ')} ${type}` : type;
+    });
+  return `(${parts.join(', ')})`;
+}
+
+function fieldString(field: Parser.SyntaxNode): string {
+  const typeNode = field.childForFieldName('type');
+  const tag = field.childForFieldName('tag');
+  const names = field.childrenForFieldName('name').map(n => n.text);
+  let type = typeNode ? typeString(typeNode) : '';
+  if (names.length === 0 && field.children.some(c => c.type === '*')) {
+    type = `*${type}`; // embedded *T
+  }
+
+  let text = names.length > 0 ? `${names.join(', ')} ${type}` : type;
+  if (tag) text += ` ${tag.text}`;
+  return text;
+}
+
+function methodElemString(elem: Parser.SyntaxNode): string {
+  const name = elem.childForFieldName('name');
+  return `${name ? name.text : ''}${signatureString(elem)}`;
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
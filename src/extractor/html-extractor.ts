@@ -72,9 +72,9 @@ export class HtmlExtractor {
               name: idValue,
               qualifiedName: `#${idValue}`,
               startLine: idAttr.startPosition.row + 1,
-              startCol: idAttr.startPosition.column,
+              startCol: idAttr.startPosition.column + 1,
               endLine: idAttr.endPosition.row + 1,
-              endCol: idAttr.endPosition.column,
+              endCol: idAttr.endPosition.column + 1,
               exported: true, // IDs are globally accessible
             });
           }
@@ -91,9 +91,9 @@ export class HtmlExtractor {
               name: className,
               qualifiedName: `.${className}`,
               startLine: classAttr.startPosition.row + 1,
-              startCol: classAttr.startPosition.column,
+              startCol: classAttr.startPosition.column + 1,
               endLine: classAttr.endPosition.row + 1,
-              endCol: classAttr.endPosition.column,
+              endCol: classAttr.endPosition.column + 1,
               exported: true,
             });
           }
@@ -107,9 +107,9 @@ export class HtmlExtractor {
             name: tagNameText,
             qualifiedName: tagNameText,
             startLine: startTag.startPosition.row + 1,
-            startCol: startTag.startPosition.column,
+            startCol: startTag.startPosition.column + 1,
             endLine: node.endPosition.row + 1,
-            endCol: node.endPosition.column,
+            endCol: node.endPosition.column + 1,
             exported: true,
           });
         }
@@ -128,9 +128,9 @@ export class HtmlExtractor {
         name: scriptName,
         qualifiedName: `<${scriptName}>`,
         startLine: node.startPosition.row + 1,
-        startCol: node.startPosition.column,
+        startCol: node.startPosition.column + 1,
         endLine: node.endPosition.row + 1,
-        endCol: node.endPosition.column,
+        endCol: node.endPosition.column + 1,
         exported: true,
       });
     }
@@ -143,9 +143,9 @@ export class HtmlExtractor {
         name: 'style',
         qualifiedName: '<style>',
         startLine: node.startPosition.row + 1,
-        startCol: node.startPosition.column,
+        startCol: node.startPosition.column + 1,
         endLine: node.endPosition.row + 1,
-        endCol: node.endPosition.column,
+        endCol: node.endPosition.column + 1,
         exported: true,
       });
     }
@@ -193,9 +193,9 @@ export class HtmlExtractor {
               name: idName,
               refKind: 'read',
               startLine: attrValue.startPosition.row + 1,
-              startCol: attrValue.startPosition.column,
+              startCol: attrValue.startPosition.column + 1,
               endLine: attrValue.endPosition.row + 1,
-              endCol: attrValue.endPosition.column,
+              endCol: attrValue.endPosition.column + 1,
             });
           }
         }
@@ -210,9 +210,9 @@ export class HtmlExtractor {
           name: className,
           refKind: 'read',
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
         });
       }
     }
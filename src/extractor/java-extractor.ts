@@ -107,9 +107,9 @@ export class JavaExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `class ${name}`,
           exported,
         });
@@ -136,9 +136,9 @@ export class JavaExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `interface ${name}`,
           exported,
         });
@@ -165,9 +165,9 @@ export class JavaExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `enum ${name}`,
           exported,
         });
@@ -188,9 +188,9 @@ export class JavaExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: this.extractSignature(node, sourceLines),
           exported,
         });
@@ -233,9 +233,9 @@ export class JavaExtractor {
               name,
               qualifiedName,
               startLine: declarator.startPosition.row + 1,
-              startCol: declarator.startPosition.column,
+              startCol: declarator.startPosition.column + 1,
               endLine: declarator.endPosition.row + 1,
-              endCol: declarator.endPosition.column,
+              endCol: declarator.endPosition.column + 1,
               exported,
             });
           }
@@ -256,9 +256,9 @@ export class JavaExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             signature: this.extractSignature(member, sourceLines),
             exported,
           });
@@ -285,9 +285,9 @@ export class JavaExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             signature: this.extractSignature(member, sourceLines),
             exported,
           });
@@ -321,9 +321,9 @@ export class JavaExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             signature: `${kind} ${name}`,
             exported,
           });
@@ -360,9 +360,9 @@ export class JavaExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             signature: this.extractSignature(member, sourceLines),
             exported,
           });
@@ -384,9 +384,9 @@ export class JavaExtractor {
               name,
               qualifiedName,
               startLine: declarator.startPosition.row + 1,
-              startCol: declarator.startPosition.column,
+              startCol: declarator.startPosition.column + 1,
               endLine: declarator.endPosition.row + 1,
-              endCol: declarator.endPosition.column,
+              endCol: declarator.endPosition.column + 1,
               exported: true, // Interface fields are always public static final
             });
           }
@@ -425,18 +425,18 @@ export class JavaExtractor {
           callerName: '', // Will be resolved later
           calleeName,
           siteStartLine: node.startPosition.row + 1,
-          siteStartCol: node.startPosition.column,
+          siteStartCol: node.startPosition.column + 1,
           siteEndLine: node.endPosition.row + 1,
-          siteEndCol: node.endPosition.column,
+          siteEndCol: node.endPosition.column + 1,
         });
 
         references.push({
           name: calleeName,
           refKind: 'call',
           startLine: nameNode.startPosition.row + 1,
-          startCol: nameNode.startPosition.column,
+          startCol: nameNode.startPosition.column + 1,
           endLine: nameNode.endPosition.row + 1,
-          endCol: nameNode.endPosition.column,
+          endCol: nameNode.endPosition.column + 1,
         });
       }
     }
@@ -451,18 +451,18 @@ export class JavaExtractor {
           callerName: '',
           calleeName, // Constructor call
           siteStartLine: node.startPosition.row + 1,
-          siteStartCol: node.startPosition.column,
+          siteStartCol: node.startPosition.column + 1,
           siteEndLine: node.endPosition.row + 1,
-          siteEndCol: node.endPosition.column,
+          siteEndCol: node.endPosition.column + 1,
         });
 
         references.push({
           name: calleeName,
           refKind: 'call',
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
         });
       }
     }
@@ -506,9 +506,9 @@ export class JavaExtractor {
         name,
         refKind,
         startLine: node.startPosition.row + 1,
-        startCol: node.startPosition.column,
+        startCol: node.startPosition.column + 1,
         endLine: node.endPosition.row + 1,
-        endCol: node.endPosition.column,
+        endCol: node.endPosition.column + 1,
       });
     }
 
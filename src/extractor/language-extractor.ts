@@ -0,0 +1,25 @@
+/**
+ * Common contract for per-language symbol extractors
+ */
+
+import type Parser from 'tree-sitter';
+import type { Language } from '../core/types.js';
+import type { ExtractionResult as GoExtractionResult } from './go-extractor.js';
+
+/**
+ * What an extractor produces for one file. Symbols, calls and references are
+ * language-agnostic; the package name, imports, resolved call edges and used
+ * names and directive comments are only reported by languages that have them
+ * (currently Go). An extractor that stops at a symbol limit sets truncated.
+ */
+export type ExtractionResult = Pick<GoExtractionResult, 'symbols' | 'calls' | 'references'> &
+  Partial<Pick<GoExtractionResult, 'packageName' | 'imports' | 'callEdges' | 'usedNames' | 'directives' | 'truncated'>>;
+
+/**
+ * An extractor turns a parsed tree-sitter tree into symbols. Register one
+ * with Indexer.registerExtractor to replace the built-in extractor of a
+ * language, e.g. to add symbol kinds or details a project needs.
+ */
+export interface LanguageExtractor {
+  extract(tree: Parser.Tree, source: string, language: Language): ExtractionResult;
+}
@@ -71,11 +71,12 @@ export class PythonExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: this.extractSignature(node, sourceLines),
           exported,
+          ...this.docstringDetails(node),
         });
 
         // Recurse into function body with new scope
@@ -100,11 +101,12 @@ export class PythonExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `class ${name}`,
           exported,
+          ...this.docstringDetails(node),
         });
 
         // Extract class members
@@ -132,9 +134,9 @@ export class PythonExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           exported,
         });
       }
@@ -182,11 +184,12 @@ export class PythonExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             signature: this.extractSignature(member, sourceLines),
             exported: exported || isSpecial, // Special methods are considered exported
+            ...this.docstringDetails(member),
           });
         }
       }
@@ -211,11 +214,12 @@ export class PythonExtractor {
               name,
               qualifiedName,
               startLine: defNode.startPosition.row + 1,
-              startCol: defNode.startPosition.column,
+              startCol: defNode.startPosition.column + 1,
               endLine: defNode.endPosition.row + 1,
-              endCol: defNode.endPosition.column,
+              endCol: defNode.endPosition.column + 1,
               signature: this.extractSignature(defNode, sourceLines),
               exported,
+              ...this.docstringDetails(defNode),
             });
           }
         }
@@ -237,9 +241,9 @@ export class PythonExtractor {
               name,
               qualifiedName,
               startLine: assign.startPosition.row + 1,
-              startCol: assign.startPosition.column,
+              startCol: assign.startPosition.column + 1,
               endLine: assign.endPosition.row + 1,
-              endCol: assign.endPosition.column,
+              endCol: assign.endPosition.column + 1,
               exported,
             });
           }
@@ -278,18 +282,18 @@ export class PythonExtractor {
           callerName: '', // Will be resolved later
           calleeName,
           siteStartLine: node.startPosition.row + 1,
-          siteStartCol: node.startPosition.column,
+          siteStartCol: node.startPosition.column + 1,
           siteEndLine: node.endPosition.row + 1,
-          siteEndCol: node.endPosition.column,
+          siteEndCol: node.endPosition.column + 1,
         });
 
         references.push({
           name: calleeName,
           refKind: 'call',
           startLine: functionNode.startPosition.row + 1,
-          startCol: functionNode.startPosition.column,
+          startCol: functionNode.startPosition.column + 1,
           endLine: functionNode.endPosition.row + 1,
-          endCol: functionNode.endPosition.column,
+          endCol: functionNode.endPosition.column + 1,
         });
       }
     }
@@ -338,9 +342,9 @@ export class PythonExtractor {
         name,
         refKind,
         startLine: node.startPosition.row + 1,
-        startCol: node.startPosition.column,
+        startCol: node.startPosition.column + 1,
         endLine: node.endPosition.row + 1,
-        endCol: node.endPosition.column,
+        endCol: node.endPosition.column + 1,
       });
     }
 
@@ -389,6 +393,27 @@ export class PythonExtractor {
     return false;
   }
 
+  /**
+   * The docstring of a def or class (a string literal as the first statement
+   * of its body), as `{ details: { doc } }` ready to spread into a symbol.
+   * Indentation is removed the way inspect.cleandoc does.
+   */
+  private docstringDetails(node: Parser.SyntaxNode): Pick<SymbolRecord, 'details'> {
+    const first = node.childForFieldName('body')?.namedChildren[0];
+    const literal = first?.type === 'expression_statement' ? first.namedChildren[0] : undefined;
+    if (!literal || literal.type !== 'string' || first!.namedChildren.length !== 1) {
+      return {};
+    }
+
+    const body = literal.text.replace(/^[rRuU]*("""|'''|"|')([\s\S]*)\1$/, '$2');
+    const [head, ...rest] = body.split('\n');
+    const indents = rest.filter(line => line.trim()).map(line => line.length - line.trimStart().length);
+    const indent = indents.length > 0 ? Math.min(...indents) : 0;
+    const doc = [head.trim(), ...rest.map(line => line.slice(indent).trimEnd())].join('\n').trim();
+
+    return doc ? { details: { doc } } : {};
+  }
+
   private extractSignature(node: Parser.SyntaxNode, sourceLines: string[]): string {
     // Get first line of the node for signature
     const startLine = node.startPosition.row;
@@ -87,9 +87,9 @@ export class RustExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: this.extractSignature(node, sourceLines),
           exported,
         });
@@ -116,9 +116,9 @@ export class RustExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `struct ${name}`,
           exported,
         });
@@ -145,9 +145,9 @@ export class RustExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `enum ${name}`,
           exported,
         });
@@ -168,9 +168,9 @@ export class RustExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `trait ${name}`,
           exported,
         });
@@ -214,9 +214,9 @@ export class RustExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           exported,
         });
       }
@@ -236,9 +236,9 @@ export class RustExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           exported,
         });
       }
@@ -258,9 +258,9 @@ export class RustExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           exported,
         });
 
@@ -301,9 +301,9 @@ export class RustExtractor {
             name,
             qualifiedName,
             startLine: field.startPosition.row + 1,
-            startCol: field.startPosition.column,
+            startCol: field.startPosition.column + 1,
             endLine: field.endPosition.row + 1,
-            endCol: field.endPosition.column,
+            endCol: field.endPosition.column + 1,
             signature: typeNode ? typeNode.text : undefined,
             exported,
           });
@@ -333,9 +333,9 @@ export class RustExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             signature: this.extractSignature(member, sourceLines),
             exported,
           });
@@ -366,9 +366,9 @@ export class RustExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             signature: this.extractSignature(member, sourceLines),
             exported,
           });
@@ -413,18 +413,18 @@ export class RustExtractor {
           callerName: '', // Will be resolved later
           calleeName,
           siteStartLine: node.startPosition.row + 1,
-          siteStartCol: node.startPosition.column,
+          siteStartCol: node.startPosition.column + 1,
           siteEndLine: node.endPosition.row + 1,
-          siteEndCol: node.endPosition.column,
+          siteEndCol: node.endPosition.column + 1,
         });
 
         references.push({
           name: calleeName,
           refKind: 'call',
           startLine: functionNode.startPosition.row + 1,
-          startCol: functionNode.startPosition.column,
+          startCol: functionNode.startPosition.column + 1,
           endLine: functionNode.endPosition.row + 1,
-          endCol: functionNode.endPosition.column,
+          endCol: functionNode.endPosition.column + 1,
         });
       }
     }
@@ -468,9 +468,9 @@ export class RustExtractor {
         name,
         refKind,
         startLine: node.startPosition.row + 1,
-        startCol: node.startPosition.column,
+        startCol: node.startPosition.column + 1,
         endLine: node.endPosition.row + 1,
-        endCol: node.endPosition.column,
+        endCol: node.endPosition.column + 1,
       });
     }
 
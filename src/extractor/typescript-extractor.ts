@@ -70,9 +70,9 @@ export class TypeScriptExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: this.extractSignature(node, sourceLines),
           exported: this.isExported(node),
         });
@@ -102,9 +102,9 @@ export class TypeScriptExtractor {
               name,
               qualifiedName,
               startLine: child.startPosition.row + 1,
-              startCol: child.startPosition.column,
+              startCol: child.startPosition.column + 1,
               endLine: child.endPosition.row + 1,
-              endCol: child.endPosition.column,
+              endCol: child.endPosition.column + 1,
               signature: this.extractSignature(valueNode, sourceLines),
               exported: this.isExported(node.parent),
             });
@@ -126,9 +126,9 @@ export class TypeScriptExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `class ${name}`,
           exported: this.isExported(node),
         });
@@ -154,9 +154,9 @@ export class TypeScriptExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           signature: `interface ${name}`,
           exported: this.isExported(node),
         });
@@ -176,9 +176,9 @@ export class TypeScriptExtractor {
           name,
           qualifiedName,
           startLine: node.startPosition.row + 1,
-          startCol: node.startPosition.column,
+          startCol: node.startPosition.column + 1,
           endLine: node.endPosition.row + 1,
-          endCol: node.endPosition.column,
+          endCol: node.endPosition.column + 1,
           exported: this.isExported(node),
         });
       }
@@ -211,9 +211,9 @@ export class TypeScriptExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             signature: this.extractSignature(member, sourceLines),
             exported: false,
           });
@@ -233,9 +233,9 @@ export class TypeScriptExtractor {
             name,
             qualifiedName,
             startLine: member.startPosition.row + 1,
-            startCol: member.startPosition.column,
+            startCol: member.startPosition.column + 1,
             endLine: member.endPosition.row + 1,
-            endCol: member.endPosition.column,
+            endCol: member.endPosition.column + 1,
             exported: false,
           });
         }
@@ -273,18 +273,18 @@ export class TypeScriptExtractor {
           callerName: '', // Will be resolved later based on scope
           calleeName,
           siteStartLine: node.startPosition.row + 1,
-          siteStartCol: node.startPosition.column,
+          siteStartCol: node.startPosition.column + 1,
           siteEndLine: node.endPosition.row + 1,
-          siteEndCol: node.endPosition.column,
+          siteEndCol: node.endPosition.column + 1,
         });
 
         references.push({
           name: calleeName,
           refKind: 'call',
           startLine: functionNode.startPosition.row + 1,
-          startCol: functionNode.startPosition.column,
+          startCol: functionNode.startPosition.column + 1,
           endLine: functionNode.endPosition.row + 1,
-          endCol: functionNode.endPosition.column,
+          endCol: functionNode.endPosition.column + 1,
         });
       }
     }
@@ -323,9 +323,9 @@ export class TypeScriptExtractor {
         name,
         refKind,
         startLine: node.startPosition.row + 1,
-        startCol: node.startPosition.column,
+        startCol: node.startPosition.column + 1,
         endLine: node.endPosition.row + 1,
-        endCol: node.endPosition.column,
+        endCol: node.endPosition.column + 1,
       });
     }
 
@@ -2,14 +2,39 @@
  * Main API entry point for CodeIndex
  */
 
+import { mkdirSync, rmSync, writeFileSync } from 'fs';
+import { dirname, resolve } from 'path';
 import { Indexer } from './indexer/indexer.js';
 import { QueryEngine } from './query/query-engine.js';
+import { IndexSnapshot } from './query/index-snapshot.js';
 import { EmbeddingsGenerator } from './embeddings/embeddings-generator.js';
 import { FileWatcher } from './watcher/file-watcher.js';
+import { watchIndexEvents } from './watcher/index-event-stream.js';
+import type { IndexEvent } from './watcher/index-event-stream.js';
+import { buildIndexDocument, writeJSON } from './export/json-exporter.js';
+import { exportSQLite } from './export/sqlite-exporter.js';
+import { readCache, writeCache } from './storage/index-cache.js';
+import { commonRoot, mergeInto } from './storage/index-merge.js';
+import { writeDOT } from './export/dot-exporter.js';
+import { writeCSV, writeFieldsCSV } from './export/csv-exporter.js';
+import { writeYAML } from './export/yaml-exporter.js';
+import { writeNDJSON } from './export/ndjson-exporter.js';
 import type { EmbeddingOptions } from './embeddings/embeddings-generator.js';
+import type { LanguageExtractor } from './extractor/language-extractor.js';
+import type { IndexDocument } from './export/json-exporter.js';
+import type { FileSet, TokenRange } from './indexer/file-set.js';
 import type {
   IndexOptions,
+  IndexProgress,
+  IndexRunOptions,
+  IndexWarning,
   QuerySymbolOptions,
+  FindOptions,
+  ScoredSymbol,
+  ScoreConfig,
+  AnonymousShape,
+  ShapeOccurrence,
+  SymbolPage,
   CallChainOptions,
   CallNode,
   Location,
@@ -17,8 +42,38 @@ import type {
   PropertyNode,
   Language,
   SymbolKind,
+  PackageIndex,
+  IndexDelta,
+  RefreshReport,
+  ImportRecord,
+  FileDirective,
+  FileParseError,
+  Comment,
+  CommentGroup,
+  Conflict,
+  Implementer,
+  PromotedMember,
+  MergeConflict,
+  SymbolVisitor,
+  SymbolPostProcessor,
+  SkippedFile,
+  SkippedSymlink,
+  IndexStats,
+  UnusedExportedOptions,
 } from './core/types.js';
 
+export interface MergeResult {
+  index: CodeIndex;
+  conflicts: MergeConflict[]; // 重复索引的包目录，合并结果中只保留第一个索引的内容
+}
+
+/**
+ * Calls run on a single thread, so each one sees the database in a
+ * consistent state. An async reindexAll/refreshAll, however, yields between
+ * files: queries issued while it runs may see some files already updated and
+ * others not. Take a snapshot() first when a series of queries must agree
+ * with each other during such an update.
+ */
 export class CodeIndex {
   private indexer: Indexer;
   private queryEngine: QueryEngine;
@@ -30,7 +85,7 @@ export class CodeIndex {
   private constructor(private options: IndexOptions) {
     this.indexer = new Indexer(options);
     this.db = this.indexer.getDatabase();
-    this.queryEngine = new QueryEngine(this.db);
+    this.queryEngine = new QueryEngine(this.db, options.normalizeForm, options.nameForms);
   }
 
   /**
@@ -42,36 +97,108 @@ export class CodeIndex {
     return instance;
   }
 
+  /**
+   * Create a CodeIndex from a cache written by saveCache. The cached database
+   * replaces the one at options.dbPath; call refreshAll afterwards to pick up
+   * files changed since the cache was saved. Throws StaleCacheError if the
+   * cache was written by an incompatible version.
+   */
+  static async loadCache(cachePath: string, options: IndexOptions): Promise<CodeIndex> {
+    const data = readCache(cachePath);
+    for (const suffix of ['', '-wal', '-shm']) {
+      rmSync(options.dbPath + suffix, { force: true });
+    }
+    mkdirSync(dirname(options.dbPath), { recursive: true });
+    writeFileSync(options.dbPath, data);
+    return CodeIndex.create(options);
+  }
+
+  /**
+   * Combine indexes built from separate roots, such as the modules of a
+   * workspace, into a new index at options.dbPath (replacing what is there).
+   * Its rootDir is the closest directory containing every root, and file
+   * paths are rebased onto it, so locations stay meaningful. A package
+   * directory found in more than one index (overlapping roots) is taken from
+   * the first and reported in conflicts. The merged index is a regular
+   * CodeIndex: every query works on it.
+   */
+  static async merge(indexes: CodeIndex[], options: Omit<IndexOptions, 'rootDir' | 'languages'>): Promise<MergeResult> {
+    if (indexes.length === 0) {
+      throw new Error('No indexes to merge');
+    }
+    for (const index of indexes) {
+      if (!index.initialized) throw new Error('CodeIndex not initialized');
+      if (resolve(index.options.dbPath) === resolve(options.dbPath)) {
+        throw new Error(`Cannot merge into ${options.dbPath}: it is the database of an index being merged`);
+      }
+    }
+
+    for (const suffix of ['', '-wal', '-shm']) {
+      rmSync(options.dbPath + suffix, { force: true });
+    }
+    const rootDir = commonRoot(indexes.map(index => index.options.rootDir));
+    const languages = Array.from(new Set(indexes.flatMap(index => index.options.languages)));
+    const merged = await CodeIndex.create({ ...options, rootDir, languages });
+    const conflicts = mergeInto(
+      merged.db,
+      rootDir,
+      indexes.map(index => ({ rootDir: index.options.rootDir, db: index.db }))
+    );
+    return { index: merged, conflicts };
+  }
+
   private async init(): Promise<void> {
     await this.indexer.init();
     this.initialized = true;
   }
 
   /**
-   * Reindex all files in the workspace
+   * Replace the built-in extractor of a language with a custom one
+   */
+  registerExtractor(language: Language, extractor: LanguageExtractor): void {
+    this.indexer.registerExtractor(language, extractor);
+  }
+
+  /**
+   * Run a post-processor on every symbol indexed from now on, after the
+   * ones registered before it
+   */
+  use(processor: SymbolPostProcessor): void {
+    this.indexer.use(processor);
+  }
+
+  /**
+   * Reindex all files in the workspace. Pass options.signal to make the run
+   * abortable, e.g. when an editor switches projects mid-index: the promise
+   * then rejects with the signal's reason before the next file is indexed.
    */
-  async reindexAll(onProgress?: (current: number, total: number) => void): Promise<void> {
+  async reindexAll(onProgress?: IndexProgress, options: IndexRunOptions = {}): Promise<void> {
     if (!this.initialized) {
       throw new Error('CodeIndex not initialized');
     }
-    await this.indexer.indexAll(onProgress);
+    await this.indexer.indexAll(onProgress, options);
   }
 
   /**
-   * Clear all existing data and rebuild the index from scratch
+   * Clear all existing data and rebuild the index from scratch. Aborting
+   * options.signal keeps the old index unless options.keepPartial is set.
+   * Files that failed are thrown as an AggregateError after the vacuum.
    */
-  async rebuild(onProgress?: (current: number, total: number) => void): Promise<void> {
+  async rebuild(onProgress?: IndexProgress, options: IndexRunOptions = {}): Promise<void> {
     if (!this.initialized) {
       throw new Error('CodeIndex not initialized');
     }
     if (!onProgress) {
-      console.log('Clearing existing index...');
+      console.log('Clearing and rebuilding index...');
     }
-    this.indexer.getDatabase().clearAll();
-    if (!onProgress) {
-      console.log('Rebuilding index...');
+    let failedFiles: AggregateError | undefined;
+    try {
+      await this.indexer.rebuildAll(onProgress, options);
+    } catch (error) {
+      // Files that failed do not stop the rest of the rebuild, nor its vacuum
+      if (!(error instanceof AggregateError)) throw error;
+      failedFiles = error;
     }
-    await this.indexer.indexAll(onProgress);
     if (!onProgress) {
       console.log('Vacuuming database...');
     }
@@ -79,6 +206,7 @@ export class CodeIndex {
     if (!onProgress) {
       console.log('Rebuild complete!');
     }
+    if (failedFiles) throw failedFiles;
   }
 
   /**
@@ -93,6 +221,120 @@ export class CodeIndex {
     }
   }
 
+  /**
+   * Index in-memory source (e.g. an unsaved editor buffer) as the content of
+   * path, and return the symbols found in it
+   */
+  indexSource(path: string, content: string | Buffer): SymbolRecord[] {
+    if (!this.initialized) {
+      throw new Error('CodeIndex not initialized');
+    }
+    return this.indexer.indexSource(path, content);
+  }
+
+  /**
+   * Files that were left out of the index because they exceed maxFileSize
+   */
+  skippedFiles(): SkippedFile[] {
+    return this.indexer.getSkippedFiles();
+  }
+
+  /**
+   * Files indexed with a problem, e.g. more symbols than maxSymbolsPerFile,
+   * each warning naming the file
+   */
+  warnings(): IndexWarning[] {
+    return this.indexer.getWarnings();
+  }
+
+  /**
+   * Symbolic links the last scan left alone: all of them unless
+   * followSymlinks is set, otherwise broken links and links to directories
+   * already walked (which includes every link that would form a cycle)
+   */
+  skippedSymlinks(): SkippedSymlink[] {
+    return this.indexer.getSkippedSymlinks();
+  }
+
+  /**
+   * The FileSet positions are given in: IndexOptions.fileSet, or one the
+   * index made for itself. A file is added to it the first time tokenRange
+   * needs it, and again after its content changes.
+   */
+  get fileSet(): FileSet {
+    return this.indexer.fileSet;
+  }
+
+  /**
+   * A symbol's range as Pos values of fileSet, consistent with those of
+   * other tools sharing it, e.g. for a go/packages-style loader that already
+   * added the file; fileSet.position() turns them back into file, line and
+   * byte column. Null when the file changed since it was indexed.
+   */
+  async tokenRange(symbol: SymbolRecord): Promise<TokenRange | null> {
+    if (!this.initialized) {
+      throw new Error('CodeIndex not initialized');
+    }
+    return this.indexer.tokenRange(symbol);
+  }
+
+  /**
+   * Re-index one file and return the symbols added, removed and changed. A
+   * path include, exclude or ignore leaves out is not indexed.
+   */
+  async updateFile(path: string): Promise<IndexDelta> {
+    if (!this.initialized) {
+      throw new Error('CodeIndex not initialized');
+    }
+    return this.indexer.updateFile(path);
+  }
+
+  /**
+   * Index only the files changed since a git revision (`git diff --name-only
+   * <ref>`), e.g. the merge base of a pull request, and drop deleted ones.
+   * Much faster than reindexAll when few files changed; files git does not
+   * report are left as they are.
+   */
+  async indexChangedSince(ref: string): Promise<IndexDelta> {
+    if (!this.initialized) {
+      throw new Error('CodeIndex not initialized');
+    }
+    return this.indexer.indexChangedSince(ref);
+  }
+
+  /**
+   * Re-index only files modified since they were last indexed, and drop
+   * deleted files. Returns the symbol changes together with the files
+   * reparsed, left unchanged, deleted and failed.
+   */
+  async refreshAll(onProgress?: IndexProgress): Promise<RefreshReport> {
+    if (!this.initialized) {
+      throw new Error('CodeIndex not initialized');
+    }
+    return this.indexer.refreshAll(onProgress);
+  }
+
+  /**
+   * Visit every symbol in the workspace file by file, without storing anything
+   * in the index. An error thrown by visit stops the walk and is rethrown.
+   */
+  async indexStream(visit: SymbolVisitor): Promise<void> {
+    if (!this.initialized) {
+      throw new Error('CodeIndex not initialized');
+    }
+    await this.indexer.indexStream(visit);
+  }
+
+  /**
+   * Index all Go files in a directory as a single package
+   */
+  async indexPackage(dir: string, options: { includeTests?: boolean } = {}): Promise<PackageIndex> {
+    if (!this.initialized) {
+      throw new Error('CodeIndex not initialized');
+    }
+    return this.indexer.indexPackage(dir, options);
+  }
+
   /**
    * Find a single symbol by name
    */
@@ -107,6 +349,293 @@ export class CodeIndex {
     return this.queryEngine.findSymbols(query);
   }
 
+  /**
+   * Report Go declarations that clash on qualified name within a package
+   */
+  async conflicts(): Promise<Conflict[]> {
+    return this.queryEngine.conflicts();
+  }
+
+  /**
+   * List a file's imports as written, with blank (`_`) and dot (`.`) imports flagged
+   */
+  async importsOf(path: string): Promise<ImportRecord[]> {
+    return this.queryEngine.importsOf(path);
+  }
+
+  /**
+   * List directive comments such as `//go:generate stringer -type=Kind`, all
+   * of them or only those named name ('go:generate'). Directives placed on a
+   * declaration carry the qualified name of its symbol.
+   */
+  async directives(name?: string): Promise<FileDirective[]> {
+    return this.queryEngine.directives(name);
+  }
+
+  /**
+   * List the syntax errors of files that did not parse cleanly, for the
+   * whole index or the file at path (relative to rootDir). Such files are
+   * still indexed: their declarations outside the broken spots are kept.
+   */
+  async parseErrors(path?: string): Promise<FileParseError[]> {
+    return this.queryEngine.parseErrors(path);
+  }
+
+  /**
+   * Every comment of the file at path (relative to rootDir) with its
+   * position, in groups of adjacent comments, for tools that put comments
+   * back after rewriting code. Recorded only with includeComments; doc
+   * comments on symbols are unaffected either way.
+   */
+  async comments(path: string): Promise<CommentGroup[]> {
+    return this.queryEngine.comments(path);
+  }
+
+  /**
+   * Render a symbol as its declaration, e.g. `func (s *UserService) GetUser(id int) (*User, error)`
+   */
+  async symbolToString(symbol: SymbolRecord): Promise<string> {
+    return this.queryEngine.symbolToString(symbol);
+  }
+
+  /**
+   * A symbol's declaration with receiver, parameter and result names left
+   * out, e.g. `func (*UserService) GetUser(int) (*User, error)`: renaming
+   * them does not change it, while changing a type does
+   */
+  async canonicalSignature(symbol: SymbolRecord): Promise<string> {
+    return this.queryEngine.canonicalSignature(symbol);
+  }
+
+  /**
+   * Find symbols by name pattern with `*` and `?` wildcards (e.g. "Get*")
+   */
+  async find(pattern: string, options: FindOptions = {}): Promise<SymbolRecord[]> {
+    return this.queryEngine.find(pattern, options);
+  }
+
+  /**
+   * Autocomplete-style lookup: up to limit symbols whose name starts with
+   * prefix, case-insensitively unless options.caseSensitive is set, sorted
+   * by name ignoring case. The first call builds an in-memory name index
+   * that later calls reuse until the index changes.
+   */
+  async prefixSearch(prefix: string, limit: number, options: FindOptions = {}): Promise<SymbolRecord[]> {
+    return this.queryEngine.prefixSearch(prefix, limit, options);
+  }
+
+  /**
+   * Find symbols by name written in any naming convention: 'get_user_by_email'
+   * or 'get-user-by-email' finds GetUserByEmail, 'http_server' finds
+   * HTTPServer. Needs the nameForms option, which stores the snake_case form
+   * of every name when indexing.
+   */
+  async findNormalized(query: string, options: FindOptions = {}): Promise<SymbolRecord[]> {
+    return this.queryEngine.findNormalized(query, options);
+  }
+
+  /**
+   * One page of find's results with the total number of matches, for
+   * paginated listings. The order is stable, so pages neither overlap nor
+   * skip symbols as long as the index does not change in between.
+   */
+  async findPage(pattern: string, offset: number, limit: number, options: FindOptions = {}): Promise<SymbolPage> {
+    return this.queryEngine.findPage(pattern, offset, limit, options);
+  }
+
+  /**
+   * What a Go function or method depends on: the indexed package-level
+   * functions, types, variables and constants its body uses, e.g.
+   * ValidateEmail, FormatUserName and User for CreateUser. A lighter-weight
+   * view than the call graph.
+   */
+  async bodyReferences(qualifiedName: string): Promise<SymbolRecord[]> {
+    return this.queryEngine.bodyReferences(qualifiedName);
+  }
+
+  /**
+   * Concrete Go types that implement an interface of the same package, e.g.
+   * implementers('Validator') → *User when User has `func (u *User)
+   * Validate() error`. pointer is set when only *T implements it.
+   */
+  async implementers(interfaceName: string): Promise<Implementer[]> {
+    return this.queryEngine.implementers(interfaceName);
+  }
+
+  /**
+   * The main function of each Go command (`package main`) and every init
+   * function, sorted by file and line. Other symbols of commands are marked
+   * with details.inMain.
+   */
+  async entrypoints(): Promise<SymbolRecord[]> {
+    return this.queryEngine.entrypoints();
+  }
+
+  /**
+   * Every distinct anonymous struct shape (field names and types in order)
+   * with the fields, variables and parameters where it is written, most
+   * repeated first, to spot structs worth extracting into a named type
+   */
+  async anonymousShapes(): Promise<AnonymousShape[]> {
+    return this.queryEngine.anonymousShapes();
+  }
+
+  /**
+   * The symbol the cursor is in: the innermost symbol of the file at path
+   * (relative to rootDir) whose range contains line:col, both 1-based, or
+   * null outside every symbol
+   */
+  async symbolAt(path: string, line: number, col: number): Promise<SymbolRecord | null> {
+    return this.queryEngine.symbolAt(path, line, col);
+  }
+
+  /**
+   * Go methods declared on a receiver type their package does not declare,
+   * flagged with details.orphanReceiver. They are indexed like any other
+   * method; this finds the ones whose type is missing, e.g. excluded by
+   * build constraints or not written yet.
+   */
+  async orphanMethods(): Promise<SymbolRecord[]> {
+    return this.queryEngine.orphanMethods();
+  }
+
+  /**
+   * Explain why a Go type does not implement an interface, one reason per
+   * offending method: "missing method Validate() error", "method Foo has
+   * signature Foo(int) string, interface wants Foo(string) string", or a
+   * pointer receiver that leaves only *T implementing it. Empty if it does.
+   */
+  async whyNotImplements(typeName: string, interfaceName: string): Promise<string[]> {
+    return this.queryEngine.whyNotImplements(typeName, interfaceName);
+  }
+
+  /**
+   * Go functions and methods with a cyclomatic complexity of at least
+   * threshold, most complex first. Needs the computeComplexity option.
+   */
+  async complexFunctions(threshold: number): Promise<SymbolRecord[]> {
+    return this.queryEngine.complexFunctions(threshold);
+  }
+
+  /**
+   * Resolve a field path below a Go struct, e.g.
+   * resolveFieldPath('Company', 'Address', 'City') or
+   * resolveFieldPath('Person', 'ContactInfo.EmergencyContact.Address.Street'),
+   * walking anonymous nested structs, named struct types and promoted
+   * fields. Returns the leaf field; rejects with an error naming the segment
+   * that could not be resolved.
+   */
+  async resolveFieldPath(typeName: string, ...path: string[]): Promise<SymbolRecord> {
+    return this.queryEngine.resolveFieldPath(typeName, path);
+  }
+
+  /**
+   * Fields and methods a Go type can use through its embedded fields, e.g.
+   * promotedMembers('Employee') → Person's Name and Greet when Employee
+   * embeds Person. Members the type declares itself shadow promoted ones;
+   * names promoted twice at the same depth are ambiguous and left out.
+   */
+  async promotedMembers(typeName: string): Promise<PromotedMember[]> {
+    return this.queryEngine.promotedMembers(typeName);
+  }
+
+  /**
+   * Exported Go symbols not used anywhere in the index (see
+   * QueryEngine.unusedExported for how use is detected). Importers outside
+   * the index are not considered. Symbols declared in _test.go files are
+   * skipped unless includeTestFiles is set; main and init are unexported
+   * and never reported.
+   */
+  async unusedExported(options: UnusedExportedOptions = {}): Promise<SymbolRecord[]> {
+    return this.queryEngine.unusedExported(options);
+  }
+
+  /**
+   * Symbol counts by kind and visibility, files, lines covered by symbols and
+   * the deepest Go struct nesting
+   */
+  async stats(): Promise<IndexStats> {
+    return this.queryEngine.getStats();
+  }
+
+  /**
+   * Fuzzy "go to symbol" search: the limit best matches of query against
+   * symbol names, best first. Exported symbols and types, functions and
+   * methods outrank similar matches among the rest; config adjusts those
+   * weights, and each result carries its score and raw matchScore.
+   */
+  async fuzzyFind(query: string, limit: number, config: ScoreConfig = {}): Promise<ScoredSymbol[]> {
+    return this.queryEngine.fuzzyFind(query, limit, config);
+  }
+
+  /**
+   * Look up a symbol by its exact qualified name (e.g. "example.UserService.GetUser"),
+   * or with the import path in place of the package name
+   * ("github.com/acme/shop/example.UserService.GetUser")
+   */
+  async lookup(qualifiedName: string): Promise<SymbolRecord | null> {
+    return this.queryEngine.lookup(qualifiedName);
+  }
+
+  /**
+   * Look up a symbol by details.stableId, an ID derived from its package,
+   * kind and qualified name that stays the same across re-indexing runs
+   */
+  async lookupStableId(stableId: string): Promise<SymbolRecord | null> {
+    return this.queryEngine.lookupStableId(stableId);
+  }
+
+  /**
+   * List the methods declared on a type (e.g. "UserService" or "example.UserService")
+   */
+  async methodsOf(typeName: string): Promise<SymbolRecord[]> {
+    return this.queryEngine.methodsOf(typeName);
+  }
+
+  /**
+   * Everything that uses a type in its signature, type or body: the
+   * answer to "what breaks if I change this type". For User that includes
+   * a `users map[int]*User` field, functions taking or returning *User and
+   * User's own methods. Accepts "User" or "example.User".
+   */
+  async usagesOfType(typeName: string): Promise<SymbolRecord[]> {
+    return this.queryEngine.usagesOfType(typeName);
+  }
+
+  /**
+   * The local variables, constants, function literals and labels of a
+   * function or method, when indexed with includeLocals. Locals of a
+   * function literal assigned to a local name hang off that literal:
+   * localsOf("example.Run.handler").
+   */
+  async localsOf(name: string): Promise<SymbolRecord[]> {
+    return this.queryEngine.localsOf(name);
+  }
+
+  /**
+   * Functions building values of a functional-options type, like
+   * `func WithTimeout(d time.Duration) Option`: the settings a constructor
+   * taking `...Option` accepts. A heuristic (see QueryEngine.optionConstructors).
+   */
+  async optionConstructors(optionType: string): Promise<SymbolRecord[]> {
+    return this.queryEngine.optionConstructors(optionType);
+  }
+
+  /**
+   * List the fluent (builder-style) methods of a type: those whose only
+   * result is their receiver type, like `func (b *Builder) Name(string) *Builder`
+   */
+  async fluentMethods(typeName: string): Promise<SymbolRecord[]> {
+    return this.queryEngine.fluentMethods(typeName);
+  }
+
+  /**
+   * Find the functions and methods that call a symbol (e.g. "example.ValidateEmail")
+   */
+  async callers(qualifiedName: string): Promise<SymbolRecord[]> {
+    return this.queryEngine.callers(qualifiedName);
+  }
+
   /**
    * Build a call chain starting from a symbol
    */
@@ -189,6 +718,7 @@ export class CodeIndex {
       rootDir: this.options.rootDir,
       include: this.options.include,
       exclude: this.options.exclude,
+      followSymlinks: this.options.followSymlinks,
       debounceMs: 500,
       batchIntervalMs,
       minChangeLines,
@@ -213,6 +743,24 @@ export class CodeIndex {
     process.on('SIGTERM', cleanup);
   }
 
+  /**
+   * Watch the workspace and yield an event with the symbol delta for each
+   * changed file. Unlike watch(), files are re-indexed as soon as their
+   * changes settle. Iteration ends when the signal aborts.
+   */
+  watchEvents(options: { debounceMs?: number; signal?: AbortSignal } = {}): AsyncGenerator<IndexEvent, void, undefined> {
+    if (!this.initialized) {
+      throw new Error('CodeIndex not initialized');
+    }
+    return watchIndexEvents(this.indexer, {
+      rootDir: this.options.rootDir,
+      exclude: this.options.exclude,
+      followSymlinks: this.options.followSymlinks,
+      debounceMs: options.debounceMs,
+      signal: options.signal,
+    });
+  }
+
   /**
    * Stop watching files
    */
@@ -294,6 +842,85 @@ export class CodeIndex {
     });
   }
 
+  /**
+   * Export the full index as a JSON-serializable document
+   */
+  exportJSON(): IndexDocument {
+    return buildIndexDocument(this.db);
+  }
+
+  /**
+   * Write the full index as JSON to a stream
+   */
+  writeJSON(out: NodeJS.WritableStream): void {
+    writeJSON(this.db, out);
+  }
+
+  /**
+   * Write every symbol as one line of JSON, with its file and package, for
+   * jq and other streaming consumers. Resolves once the last line is
+   * written; a slow stream is waited for rather than buffered.
+   */
+  async writeNDJSON(out: NodeJS.WritableStream): Promise<void> {
+    await writeNDJSON(this.db, out);
+  }
+
+  /**
+   * Write the full index as YAML to a stream, with the same schema as
+   * writeJSON; doc comments come out as block scalars
+   */
+  writeYAML(out: NodeJS.WritableStream): void {
+    writeYAML(this.db, out);
+  }
+
+  /**
+   * Write the Go type dependency graph in Graphviz DOT format
+   */
+  writeDOT(out: NodeJS.WritableStream): void {
+    writeDOT(this.db, out);
+  }
+
+  /**
+   * Write one CSV row per symbol (file, package, kind, name, exported,
+   * start_line, end_line, doc), for spreadsheets
+   */
+  writeCSV(out: NodeJS.WritableStream): void {
+    writeCSV(this.db, out);
+  }
+
+  /**
+   * Write one CSV row per leaf struct field, nested anonymous structs
+   * flattened into dotted field paths
+   */
+  writeFieldsCSV(out: NodeJS.WritableStream): void {
+    writeFieldsCSV(this.db, out);
+  }
+
+  /**
+   * Export the index into a standalone SQLite database for ad-hoc SQL queries.
+   * Re-exporting to the same path updates it in place.
+   */
+  exportSQLite(dbPath: string): void {
+    exportSQLite(this.db, dbPath);
+  }
+
+  /**
+   * Take a read-only copy of the index as it is now. Updates made afterwards
+   * are not visible through it. Close the snapshot when done with it.
+   */
+  snapshot(): IndexSnapshot {
+    if (!this.initialized) throw new Error('CodeIndex not initialized');
+    return new IndexSnapshot(this.db.snapshot(), this.options.normalizeForm, this.options.nameForms);
+  }
+
+  /**
+   * Save the whole index to a binary cache file for CodeIndex.loadCache
+   */
+  saveCache(cachePath: string): void {
+    if (!this.initialized) throw new Error('CodeIndex not initialized');
+    writeCache(this.db, cachePath);
+  }
+
   /**
    * Close the index and release resources
    */
@@ -302,10 +929,35 @@ export class CodeIndex {
   }
 }
 
+export { signatureHash } from './indexer/symbol-hash.js';
+export { symbolToString, canonicalSignature } from './query/symbol-string.js';
+export { StaleCacheError, CACHE_VERSION } from './storage/index-cache.js';
+export { SourceSyntaxError } from './parser/parse-errors.js';
+export { NodeFileSystem, MemoryFileSystem } from './indexer/source-fs.js';
+export { FileSet, TokenFile, NO_POS } from './indexer/file-set.js';
+export type { TokenPosition, TokenRange } from './indexer/file-set.js';
+export { serve } from './server/http-server.js';
+export type { ServeOptions } from './server/http-server.js';
+export type { SourceFileSystem, SourceFileStats, MemoryFile } from './indexer/source-fs.js';
+export type { IndexSnapshot } from './query/index-snapshot.js';
+export { diffIndexes, formatIndexDiff } from './export/index-diff.js';
+export type { IndexDiff, FileDiff, ModifiedSymbol } from './export/index-diff.js';
+export { breakingChanges } from './export/api-compat.js';
+export type { ApiChange, ApiChangeKind } from './export/api-compat.js';
+
 // Re-export types
 export type {
   IndexOptions,
+  IndexProgress,
+  IndexRunOptions,
+  IndexWarning,
   QuerySymbolOptions,
+  FindOptions,
+  ScoredSymbol,
+  ScoreConfig,
+  AnonymousShape,
+  ShapeOccurrence,
+  SymbolPage,
   CallChainOptions,
   CallNode,
   Location,
@@ -313,5 +965,35 @@ export type {
   PropertyNode,
   Language,
   SymbolKind,
+  PackageIndex,
+  IndexDelta,
+  RefreshReport,
+  ImportRecord,
+  Directive,
+  FileDirective,
+  ParseError,
+  FileParseError,
+  Comment,
+  CommentGroup,
+  Conflict,
+  Implementer,
+  PromotedMember,
+  MergeConflict,
+  SymbolVisitor,
+  SymbolPostProcessor,
+  TestKind,
+  SkippedFile,
+  SkippedSymlink,
+  IndexStats,
+  UnusedExportedOptions,
+  NormalizeForm,
 } from './core/types.js';
+export type { IndexEvent, IndexChangeKind } from './watcher/index-event-stream.js';
+export type { LanguageExtractor, ExtractionResult } from './extractor/language-extractor.js';
+export type {
+  IndexDocument,
+  FileDocument,
+  SymbolDocument,
+  ImportDocument,
+} from './export/json-exporter.js';
 
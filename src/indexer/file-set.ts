@@ -0,0 +1,161 @@
+/**
+ * Compact source positions shared between tools, after Go's token.FileSet
+ *
+ * A FileSet gives every file it holds a range of integers starting at the
+ * file's base, one per byte of its UTF-8 content plus one for the end of
+ * file, so one number (a Pos) names a file and an offset in it. Ranges are
+ * handed out in the order files are added and never overlap; 0 is NoPos.
+ * Like go/token, positions only mean something within the FileSet that made
+ * them: the same file added to two sets, or twice to one set, gets
+ * different bases, so Pos values from different sets cannot be compared.
+ */
+
+import { createHash } from 'crypto';
+
+export const NO_POS = 0;
+
+export interface TokenPosition {
+  filename: string;
+  offset: number; // bytes from the start of the file
+  line: number; // 1-based
+  column: number; // 1-based, in bytes
+}
+
+export interface TokenRange {
+  pos: number;
+  end: number; // Pos just past the last byte
+}
+
+/**
+ * The byte offset in content of a 1-based line and column, the column
+ * counted in UTF-16 code units as the index records columns
+ */
+export function byteOffsetOf(content: string, line: number, column: number): number {
+  let lineStart = 0;
+  for (let i = 1; i < line; i++) {
+    const newline = content.indexOf('\n', lineStart);
+    if (newline < 0) throw new RangeError(`Line ${line} out of range`);
+    lineStart = newline + 1;
+  }
+  return Buffer.byteLength(content.slice(0, lineStart + column - 1), 'utf-8');
+}
+
+export class TokenFile {
+  readonly size: number; // bytes of UTF-8 content
+  readonly contentHash: string; // SHA-256 of the content, hex, as the index hashes files
+  // Byte offset of the start of each line
+  private lines: number[];
+
+  constructor(
+    readonly name: string,
+    readonly base: number,
+    content: string
+  ) {
+    const bytes = Buffer.from(content, 'utf-8');
+    this.lines = [0];
+    for (let i = 0; i < bytes.length; i++) {
+      if (bytes[i] === 0x0a) this.lines.push(i + 1);
+    }
+    this.size = bytes.length;
+    this.contentHash = createHash('sha256').update(content).digest('hex');
+  }
+
+  get lineCount(): number {
+    return this.lines.length;
+  }
+
+  /**
+   * The Pos of a byte offset in the file, from 0 to size
+   */
+  pos(offset: number): number {
+    if (!Number.isInteger(offset) || offset < 0 || offset > this.size) {
+      throw new RangeError(`Offset ${offset} out of range for ${this.name} (size ${this.size})`);
+    }
+    return this.base + offset;
+  }
+
+  /**
+   * The byte offset of a Pos of this file
+   */
+  offset(pos: number): number {
+    if (!Number.isInteger(pos) || pos < this.base || pos > this.base + this.size) {
+      throw new RangeError(`Pos ${pos} out of range for ${this.name}`);
+    }
+    return pos - this.base;
+  }
+
+  /**
+   * The Pos of a 1-based line and byte column
+   */
+  lineColumnPos(line: number, column: number): number {
+    if (!Number.isInteger(line) || line < 1 || line > this.lines.length) {
+      throw new RangeError(`Line ${line} out of range for ${this.name} (${this.lines.length} lines)`);
+    }
+    return this.pos(this.lines[line - 1] + column - 1);
+  }
+
+  position(pos: number): TokenPosition {
+    const offset = this.offset(pos);
+    let low = 0;
+    let high = this.lines.length - 1;
+    while (low < high) {
+      const mid = (low + high + 1) >>> 1;
+      if (this.lines[mid] <= offset) low = mid;
+      else high = mid - 1;
+    }
+    return { filename: this.name, offset, line: low + 1, column: offset - this.lines[low] + 1 };
+  }
+}
+
+export class FileSet {
+  private files: TokenFile[] = [];
+  private byName = new Map<string, TokenFile>();
+  private nextBase = 1;
+
+  /**
+   * Add a file with its content and return it. Adding a name again adds
+   * a new file after the others, which file() returns from then on.
+   */
+  addFile(filename: string, content: string): TokenFile {
+    const file = new TokenFile(filename, this.nextBase, content);
+    this.nextBase += file.size + 1;
+    this.files.push(file);
+    this.byName.set(filename, file);
+    return file;
+  }
+
+  /**
+   * The file last added under filename
+   */
+  file(filename: string): TokenFile | undefined {
+    return this.byName.get(filename);
+  }
+
+  /**
+   * The file a Pos belongs to, or undefined for NoPos and positions past
+   * the last file
+   */
+  fileOf(pos: number): TokenFile | undefined {
+    let low = 0;
+    let high = this.files.length - 1;
+    while (low <= high) {
+      const mid = (low + high) >>> 1;
+      const file = this.files[mid];
+      if (pos < file.base) high = mid - 1;
+      else if (pos > file.base + file.size) low = mid + 1;
+      else return file;
+    }
+    return undefined;
+  }
+
+  position(pos: number): TokenPosition | undefined {
+    return this.fileOf(pos)?.position(pos);
+  }
+
+  /**
+   * Every file in the order added
+   */
+  getFiles(): TokenFile[] {
+    return [...this.files];
+  }
+}
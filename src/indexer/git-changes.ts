@@ -0,0 +1,27 @@
+/**
+ * Files changed in a git work tree since a given revision
+ */
+
+import { execFile } from 'child_process';
+
+/**
+ * Paths (relative to rootDir, '/'-separated) of files under rootDir that
+ * differ between ref and the work tree, as `git diff --name-only` lists
+ * them. Renames are listed as their old and new path, so both ends can be
+ * brought up to date; deleted files are included too. Untracked files are
+ * not, as git diff does not see them.
+ */
+export function changedFilesSince(rootDir: string, ref: string): Promise<string[]> {
+  const args = ['diff', '--name-only', '-z', '--no-renames', '--relative', ref, '--'];
+
+  return new Promise((resolve, reject) => {
+    execFile('git', args, { cwd: rootDir, maxBuffer: 64 * 1024 * 1024 }, (error, stdout, stderr) => {
+      if (error) {
+        const detail = String(stderr).trim() || error.message;
+        reject(new Error(`git diff against ${ref} failed in ${rootDir}: ${detail}`, { cause: error }));
+        return;
+      }
+      resolve(String(stdout).split('\0').filter(Boolean).sort());
+    });
+  });
+}
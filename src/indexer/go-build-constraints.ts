@@ -0,0 +1,215 @@
+/**
+ * Go build constraint evaluation (//go:build lines and _GOOS/_GOARCH file names)
+ */
+
+import type { BuildContext } from '../core/types.js';
+
+export interface FileConstraint {
+  matches: boolean;
+  constraint?: string; // 文件的约束表达式（文件名后缀与 //go:build 合并后），无约束时为空
+}
+
+const KNOWN_OS = new Set([
+  'aix', 'android', 'darwin', 'dragonfly', 'freebsd', 'hurd', 'illumos', 'ios', 'js',
+  'linux', 'nacl', 'netbsd', 'openbsd', 'plan9', 'solaris', 'wasip1', 'windows', 'zos',
+]);
+
+const KNOWN_ARCH = new Set([
+  '386', 'amd64', 'amd64p32', 'arm', 'armbe', 'arm64', 'arm64be', 'loong64', 'mips',
+  'mipsle', 'mips64', 'mips64le', 'mips64p32', 'mips64p32le', 'ppc', 'ppc64', 'ppc64le',
+  'riscv', 'riscv64', 's390', 's390x', 'sparc', 'sparc64', 'wasm',
+]);
+
+const UNIX_OS = new Set([
+  'aix', 'android', 'darwin', 'dragonfly', 'freebsd', 'hurd', 'illumos', 'ios',
+  'linux', 'netbsd', 'openbsd', 'solaris',
+]);
+
+const NODE_PLATFORM_TO_GOOS: Record<string, string> = {
+  win32: 'windows',
+  sunos: 'solaris',
+  cygwin: 'windows',
+};
+
+const NODE_ARCH_TO_GOARCH: Record<string, string> = {
+  x64: 'amd64',
+  ia32: '386',
+  ppc64: 'ppc64le',
+};
+
+/**
+ * The build context of the host running the indexer
+ */
+export function defaultBuildContext(): BuildContext {
+  return {
+    goos: NODE_PLATFORM_TO_GOOS[process.platform] ?? process.platform,
+    goarch: NODE_ARCH_TO_GOARCH[process.arch] ?? process.arch,
+    // As for go build on the host: on unless CGO_ENABLED=0
+    enableCgo: process.env.CGO_ENABLED !== '0',
+  };
+}
+
+type ConstraintExpr =
+  | { op: 'tag'; tag: string }
+  | { op: 'not'; expr: ConstraintExpr }
+  | { op: 'and' | 'or'; left: ConstraintExpr; right: ConstraintExpr };
+
+/**
+ * Parse a //go:build expression such as `linux && (amd64 || arm64) && !cgo`
+ */
+export function parseBuildExpression(text: string): ConstraintExpr {
+  const tokens = text.match(/\(|\)|!|&&|\|\||[A-Za-z0-9_.]+/g) ?? [];
+  let pos = 0;
+
+  const parseOr = (): ConstraintExpr => {
+    let left = parseAnd();
+    while (tokens[pos] === '||') {
+      pos++;
+      left = { op: 'or', left, right: parseAnd() };
+    }
+    return left;
+  };
+
+  const parseAnd = (): ConstraintExpr => {
+    let left = parseNot();
+    while (tokens[pos] === '&&') {
+      pos++;
+      left = { op: 'and', left, right: parseNot() };
+    }
+    return left;
+  };
+
+  const parseNot = (): ConstraintExpr => {
+    const token = tokens[pos++];
+    if (token === '!') {
+      return { op: 'not', expr: parseNot() };
+    }
+    if (token === '(') {
+      const expr = parseOr();
+      if (tokens[pos++] !== ')') {
+        throw new Error(`Invalid build constraint: ${text}`);
+      }
+      return expr;
+    }
+    if (token === undefined || !/^[A-Za-z0-9_.]+$/.test(token)) {
+      throw new Error(`Invalid build constraint: ${text}`);
+    }
+    return { op: 'tag', tag: token };
+  };
+
+  const expr = parseOr();
+  if (pos !== tokens.length) {
+    throw new Error(`Invalid build constraint: ${text}`);
+  }
+  return expr;
+}
+
+/**
+ * Convert legacy `// +build` lines to a //go:build expression.
+ * Lines are ANDed, space-separated options ORed, comma-separated terms ANDed.
+ */
+function plusBuildToExpression(lines: string[]): string {
+  return lines
+    .map(line => {
+      const options = line.trim().split(/\s+/).filter(Boolean).map(option => {
+        const terms = option.split(',');
+        return terms.length > 1 ? `(${terms.join(' && ')})` : terms[0];
+      });
+      return options.length > 1 ? `(${options.join(' || ')})` : options[0];
+    })
+    .join(' && ');
+}
+
+function tagSatisfied(tag: string, ctx: BuildContext): boolean {
+  if (tag === ctx.goos || tag === ctx.goarch) return true;
+  if (tag === 'unix') return UNIX_OS.has(ctx.goos);
+  // android implies linux, ios implies darwin, illumos implies solaris
+  if (tag === 'linux' && ctx.goos === 'android') return true;
+  if (tag === 'darwin' && ctx.goos === 'ios') return true;
+  if (tag === 'solaris' && ctx.goos === 'illumos') return true;
+  if (tag === 'gc') return true;
+  if (tag === 'cgo' && ctx.enableCgo) return true;
+  // Release tags: assume a toolchain new enough for any go1.N constraint
+  if (/^go1\.\d+$/.test(tag)) return true;
+  return (ctx.tags ?? []).includes(tag);
+}
+
+export function evaluateBuildExpression(expr: ConstraintExpr, ctx: BuildContext): boolean {
+  switch (expr.op) {
+    case 'tag':
+      return tagSatisfied(expr.tag, ctx);
+    case 'not':
+      return !evaluateBuildExpression(expr.expr, ctx);
+    case 'and':
+      return evaluateBuildExpression(expr.left, ctx) && evaluateBuildExpression(expr.right, ctx);
+    case 'or':
+      return evaluateBuildExpression(expr.left, ctx) || evaluateBuildExpression(expr.right, ctx);
+  }
+}
+
+/**
+ * Find the build constraint in a file header. Only comments before the
+ * package clause count; //go:build takes precedence over // +build.
+ */
+export function extractBuildConstraint(source: string): string | undefined {
+  const plusBuild: string[] = [];
+
+  for (const rawLine of source.split('\n')) {
+    const line = rawLine.trim();
+    if (line === '') continue;
+    if (!line.startsWith('//')) break;
+
+    const goBuild = line.match(/^\/\/go:build\s+(.+)$/);
+    if (goBuild) {
+      return goBuild[1].trim();
+    }
+    const legacy = line.match(/^\/\/\s*\+build\s+(.+)$/);
+    if (legacy) {
+      plusBuild.push(legacy[1]);
+    }
+  }
+
+  return plusBuild.length > 0 ? plusBuildToExpression(plusBuild) : undefined;
+}
+
+/**
+ * Derive the implicit constraint from a file name: name_GOOS.go,
+ * name_GOARCH.go and name_GOOS_GOARCH.go (a _test suffix is ignored).
+ */
+export function fileNameConstraint(fileName: string): string | undefined {
+  const base = fileName.split('/').pop()!.replace(/\.go$/, '').replace(/_test$/, '');
+  const parts = base.split('_');
+  // The first element is the file's own name and never a constraint
+  if (parts.length < 2) return undefined;
+
+  const last = parts[parts.length - 1];
+  const secondLast = parts.length >= 3 ? parts[parts.length - 2] : undefined;
+
+  if (secondLast && KNOWN_OS.has(secondLast) && KNOWN_ARCH.has(last)) {
+    return `${secondLast} && ${last}`;
+  }
+  if (KNOWN_OS.has(last) || KNOWN_ARCH.has(last)) {
+    return last;
+  }
+  return undefined;
+}
+
+/**
+ * Decide whether a Go file builds in the given context
+ */
+export function evaluateFileConstraints(fileName: string, source: string, ctx: BuildContext): FileConstraint {
+  const parts = [fileNameConstraint(fileName), extractBuildConstraint(source)].filter(
+    (part): part is string => Boolean(part)
+  );
+  if (parts.length === 0) {
+    return { matches: true };
+  }
+
+  const constraint = parts.length > 1 ? parts.map(part => `(${part})`).join(' && ') : parts[0];
+  try {
+    return { matches: evaluateBuildExpression(parseBuildExpression(constraint), ctx), constraint };
+  } catch {
+    // Malformed constraints are reported by `go vet`; index the file rather than lose it
+    return { matches: true, constraint };
+  }
+}
@@ -0,0 +1,47 @@
+/**
+ * Detection of generated Go files (https://go.dev/s/generatedcode)
+ */
+
+// Must match a whole line comment, trailing period included
+const GENERATED_COMMENT = /^\/\/ Code generated .* DO NOT EDIT\.$/;
+
+/**
+ * Whether a Go file is generated: a line comment matching
+ * `^// Code generated .* DO NOT EDIT\.$` appears before the first
+ * non-comment, non-blank text (normally the package clause). The marker may
+ * follow a license header; it does not count once code has started.
+ */
+export function isGeneratedGoFile(source: string): boolean {
+  let inBlockComment = false;
+
+  for (const rawLine of source.split('\n')) {
+    const fullLine = rawLine.replace(/\r$/, '');
+    let line = fullLine.trim();
+
+    if (inBlockComment) {
+      const end = line.indexOf('*/');
+      if (end === -1) continue;
+      inBlockComment = false;
+      line = line.slice(end + 2).trim();
+    }
+
+    if (line === '') continue;
+    if (line.startsWith('//')) {
+      // Matched untrimmed: an indented marker or one with trailing spaces does not count
+      if (GENERATED_COMMENT.test(fullLine)) return true;
+      continue;
+    }
+    if (line.startsWith('/*')) {
+      const end = line.indexOf('*/', 2);
+      if (end === -1) {
+        inBlockComment = true;
+      } else if (line.slice(end + 2).trim() !== '') {
+        return false; // code after the comment on the same line
+      }
+      continue;
+    }
+    return false;
+  }
+
+  return false;
+}
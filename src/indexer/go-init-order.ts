@@ -0,0 +1,71 @@
+/**
+ * Go package initialization: init functions and the order package-level
+ * variables are initialized in
+ */
+
+import type { SymbolRecord } from '../core/types.js';
+
+/**
+ * Analyze the symbols of one package, given file by file in the order the
+ * go tool presents files (sorted by name) and in source order within each.
+ *
+ * Sets details.initDeps on each package-level variable to the package
+ * variables its initializer depends on: those it names directly (initRefs)
+ * and those read by the package functions it calls, followed through their
+ * bodies (bodyRefs). This is best effort: methods and references through
+ * other packages are not followed.
+ *
+ * Returns the package's init functions in file and position order, and the
+ * variables in initialization order: per the Go spec, repeatedly the
+ * earliest declared variable whose dependencies are all initialized.
+ * Variables caught in a dependency cycle, which the compiler rejects, come
+ * last in declaration order.
+ */
+export function analyzeInitOrder(symbols: SymbolRecord[]): { inits: SymbolRecord[]; initOrder: SymbolRecord[] } {
+  const isPackageLevel = (symbol: SymbolRecord) => symbol.qualifiedName.split('.').length === 2;
+  const vars = symbols.filter(symbol => symbol.kind === 'variable' && isPackageLevel(symbol));
+  const funcs = new Map(
+    symbols.filter(symbol => symbol.kind === 'function' && isPackageLevel(symbol)).map(symbol => [symbol.name, symbol])
+  );
+  const varNames = new Set(vars.map(symbol => symbol.name));
+
+  // Package variables reached from a set of names, through the functions they call
+  const depsOf = (refs: string[]): string[] => {
+    const deps = new Set<string>();
+    const visited = new Set<string>();
+    const pending = [...refs];
+    while (pending.length > 0) {
+      const name = pending.pop()!;
+      if (visited.has(name)) continue;
+      visited.add(name);
+      if (varNames.has(name)) {
+        deps.add(name);
+      } else if (funcs.has(name)) {
+        pending.push(...(funcs.get(name)!.details?.bodyRefs ?? []));
+      }
+    }
+    return Array.from(deps).sort();
+  };
+
+  for (const symbol of vars) {
+    const deps = depsOf(symbol.details?.initRefs ?? []).filter(name => name !== symbol.name);
+    if (deps.length > 0) {
+      symbol.details = { ...symbol.details, initDeps: deps };
+    }
+  }
+
+  const initOrder: SymbolRecord[] = [];
+  const initialized = new Set<string>();
+  let remaining = vars;
+  for (;;) {
+    const ready = remaining.find(symbol => (symbol.details?.initDeps ?? []).every(name => initialized.has(name)));
+    if (!ready) break;
+    initOrder.push(ready);
+    initialized.add(ready.name);
+    remaining = remaining.filter(symbol => symbol !== ready);
+  }
+  initOrder.push(...remaining);
+
+  const inits = symbols.filter(symbol => symbol.kind === 'function' && symbol.name === 'init' && isPackageLevel(symbol));
+  return { inits, initOrder };
+}
@@ -0,0 +1,66 @@
+/**
+ * The module path a go.mod declares, for turning package directories into
+ * full import paths
+ */
+
+import type { SymbolRecord } from '../core/types.js';
+
+/**
+ * The path of the `module` directive in the text of a go.mod file, or null
+ * when there is none. Accepts the quoted (`module "example.com/m"`) and
+ * parenthesized forms as well as the usual bare one; comments are ignored.
+ */
+export function parseModulePath(goMod: string): string | null {
+  const lines = goMod.split('\n').map(line => line.replace(/\/\/.*$/, '').trim());
+
+  for (let i = 0; i < lines.length; i++) {
+    const match = /^module\b\s*(.*)$/.exec(lines[i]);
+    if (!match) continue;
+
+    let path = match[1];
+    if (path === '(') {
+      path = lines.slice(i + 1).find(line => line !== '') ?? '';
+    }
+    path = path.replace(/^(["`])(.*)\1$/, '$2');
+    return path && path !== ')' ? path : null;
+  }
+  return null;
+}
+
+/**
+ * Import path of the package in dir ('/'-separated, relative to the module
+ * root; '.' for the root itself)
+ */
+export function packageImportPath(modulePath: string, dir: string): string {
+  return dir === '.' || dir === '' ? modulePath : `${modulePath}/${dir}`;
+}
+
+/**
+ * The key identifying the Go package of a file in dir ('/'-separated,
+ * relative to rootDir): its import path when the module path is known,
+ * otherwise dir itself ('.' for rootDir), which is unique within one index
+ * only. Files of an external test package (package foo_test) belong to a
+ * package of their own, named with a _test suffix as go list names it.
+ */
+export function goPackagePath(modulePath: string | undefined, dir: string, externalTest: boolean): string {
+  const path = modulePath ? packageImportPath(modulePath, dir) : dir || '.';
+  return externalTest ? `${path}_test` : path;
+}
+
+/**
+ * Give the typeRef of every local type reference among symbols, all from
+ * the package in dir, the full import path of that package
+ */
+export function qualifyLocalTypeRefs(
+  symbols: Array<Pick<SymbolRecord, 'details'>>,
+  modulePath: string,
+  dir: string
+): void {
+  const importPath = packageImportPath(modulePath, dir);
+  for (const symbol of symbols) {
+    const typeRef = symbol.details?.typeRef;
+    if (typeRef?.isLocal) {
+      symbol.details = { ...symbol.details, typeRef: { ...typeRef, importPath } };
+    }
+  }
+}
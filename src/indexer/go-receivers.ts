@@ -0,0 +1,28 @@
+/**
+ * Go methods whose receiver type is not declared in their package
+ */
+
+import type { SymbolRecord } from '../core/types.js';
+
+const RECEIVER_KINDS = new Set(['struct', 'interface', 'type']);
+
+/**
+ * Set details.orphanReceiver on the methods among symbols, all of one
+ * package in any file order, whose receiver type none of them declares.
+ * Returns those methods. Such a method is kept, not
+ * dropped: its type may be in a file that was excluded (build constraints,
+ * includeKinds) or has not been written yet.
+ */
+export function markOrphanReceivers(symbols: SymbolRecord[]): SymbolRecord[] {
+  const typeNames = new Set(symbols.filter(symbol => RECEIVER_KINDS.has(symbol.kind)).map(symbol => symbol.name));
+  const orphans: SymbolRecord[] = [];
+
+  for (const symbol of symbols) {
+    const receiverType = symbol.kind === 'method' ? symbol.details?.receiverType : undefined;
+    if (receiverType && !typeNames.has(receiverType)) {
+      symbol.details = { ...symbol.details, orphanReceiver: true };
+      orphans.push(symbol);
+    }
+  }
+  return orphans;
+}
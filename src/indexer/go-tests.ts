@@ -0,0 +1,76 @@
+/**
+ * Recognition of the functions `go test` runs in _test.go files
+ */
+
+import type { ExtractionResult } from '../extractor/language-extractor.js';
+import type { SymbolRecord, TestKind } from '../core/types.js';
+
+type ExtractedSymbol = Omit<SymbolRecord, 'fileId' | 'symbolId'>;
+
+// Name prefix and the *testing.X parameter each kind requires (examples take none)
+const TEST_FUNCTIONS: Array<{ kind: TestKind; prefix: string; param?: string }> = [
+  { kind: 'test', prefix: 'Test', param: 'T' },
+  { kind: 'benchmark', prefix: 'Benchmark', param: 'B' },
+  { kind: 'fuzz', prefix: 'Fuzz', param: 'F' },
+  { kind: 'example', prefix: 'Example' },
+];
+
+/**
+ * Same rule as `go test`: the prefix alone, or the prefix followed by
+ * anything but a lower-case letter ("TestUser", "Test_user", not "Testify")
+ */
+function hasTestName(name: string, prefix: string): boolean {
+  if (!name.startsWith(prefix)) return false;
+  const next = name.charAt(prefix.length);
+  return next.toUpperCase() === next;
+}
+
+/**
+ * How the file refers to the testing package's types: 'testing.' normally,
+ * the alias when renamed, '' for a dot import, or null when not imported
+ * (only examples can be found then)
+ */
+function testingQualifier(imports: ExtractionResult['imports']): string | null {
+  const imp = imports?.find(i => i.path === 'testing');
+  if (!imp) return null;
+  if (!imp.alias) return 'testing.';
+  if (imp.alias === '_') return null;
+  return imp.alias === '.' ? '' : `${imp.alias}.`;
+}
+
+/**
+ * The kind of test a top-level function is, or undefined when `go test`
+ * would not run it: TestXxx(t *testing.T), BenchmarkXxx(b *testing.B),
+ * FuzzXxx(f *testing.F) and ExampleXxx(), none generic or with results
+ */
+function testKindOf(symbol: ExtractedSymbol, qualifier: string | null): TestKind | undefined {
+  if (symbol.kind !== 'function') return undefined;
+  const details = symbol.details ?? {};
+  if (details.typeParams?.length || details.results?.length) return undefined;
+
+  const params = details.params ?? [];
+  for (const { kind, prefix, param } of TEST_FUNCTIONS) {
+    if (!hasTestName(symbol.name, prefix)) continue;
+    if (!param) return params.length === 0 ? kind : undefined;
+    const [first] = params;
+    const matches = qualifier !== null && params.length === 1 && !first.isVariadic && first.type === `*${qualifier}${param}`;
+    return matches ? kind : undefined;
+  }
+  return undefined;
+}
+
+/**
+ * Tag test, benchmark, fuzz and example functions of a _test.go file with
+ * details.testKind. Other files are left alone.
+ */
+export function annotateGoTests(relativePath: string, extraction: ExtractionResult): void {
+  if (!relativePath.endsWith('_test.go')) return;
+  const qualifier = testingQualifier(extraction.imports);
+
+  for (const symbol of extraction.symbols) {
+    const testKind = testKindOf(symbol, qualifier);
+    if (testKind) {
+      symbol.details = { ...symbol.details, testKind };
+    }
+  }
+}
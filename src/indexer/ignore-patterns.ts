@@ -0,0 +1,117 @@
+/**
+ * gitignore-style path matching for the `ignore` index option
+ *
+ * Supported syntax, as in .gitignore:
+ *   - blank lines and lines starting with `#` are skipped (`\#` for a literal #)
+ *   - `!pattern` re-includes a path an earlier pattern ignored
+ *   - a trailing `/` matches directories only
+ *   - a pattern with a `/` at the start or in the middle is anchored to the
+ *     root; otherwise it matches a name at any depth
+ *   - `*` and `?` do not cross `/`; `**` matches any number of directories;
+ *     `[abc]` and `[!a-z]` are character classes
+ *
+ * The last matching pattern wins. Once a directory is ignored, nothing below
+ * it can be re-included, just like git.
+ */
+
+interface IgnoreRule {
+  regex: RegExp;
+  negated: boolean;
+  dirOnly: boolean;
+}
+
+/**
+ * Directories skipped unless a later `!` pattern re-includes them
+ */
+export const DEFAULT_IGNORE_PATTERNS = ['vendor/', '.*/'];
+
+function globToRegExpSource(glob: string): string {
+  let source = '';
+  for (let i = 0; i < glob.length; i++) {
+    const ch = glob[i];
+    if (ch === '*') {
+      if (glob[i + 1] === '*') {
+        const atStart = i === 0 || glob[i - 1] === '/';
+        const atEnd = i + 2 === glob.length || glob[i + 2] === '/';
+        if (atStart && atEnd) {
+          // `**/` matches zero or more directories; a trailing `**` matches everything below
+          source += i + 2 === glob.length ? '.*' : '(?:.*/)?';
+          i += 2;
+          continue;
+        }
+      }
+      source += '[^/]*';
+    } else if (ch === '?') {
+      source += '[^/]';
+    } else if (ch === '[') {
+      const close = glob.indexOf(']', i + 2);
+      if (close === -1) {
+        source += '\\[';
+        continue;
+      }
+      let body = glob.slice(i + 1, close);
+      if (body.startsWith('!')) body = `^${body.slice(1)}`;
+      source += `[${body.replace(/\\/g, '\\\\')}]`;
+      i = close;
+    } else if (ch === '\\' && i + 1 < glob.length) {
+      source += glob[++i].replace(/[.*+?^${}()|[\]\\/]/g, '\\$&');
+    } else {
+      source += ch.replace(/[.*+?^${}()|[\]\\/]/g, '\\$&');
+    }
+  }
+  return source;
+}
+
+/**
+ * Compile a single glob (`*`, `?`, `**` and character classes, as above)
+ * matching whole slash-separated paths
+ */
+export function globToRegExp(glob: string): RegExp {
+  return new RegExp(`^${globToRegExpSource(glob)}$`);
+}
+
+function compileRule(line: string): IgnoreRule | null {
+  let pattern = line.replace(/(?<!\\)\s+$/, '');
+  if (!pattern || pattern.startsWith('#')) return null;
+
+  const negated = pattern.startsWith('!');
+  if (negated) pattern = pattern.slice(1);
+  if (pattern.startsWith('\\#') || pattern.startsWith('\\!')) pattern = pattern.slice(1);
+
+  const dirOnly = pattern.endsWith('/');
+  if (dirOnly) pattern = pattern.slice(0, -1);
+  if (!pattern) return null;
+
+  const anchored = pattern.includes('/');
+  if (pattern.startsWith('/')) pattern = pattern.slice(1);
+
+  const prefix = anchored ? '^' : '^(?:.*/)?';
+  return { regex: new RegExp(`${prefix}${globToRegExpSource(pattern)}$`), negated, dirOnly };
+}
+
+/**
+ * Compile ignore patterns into a predicate over paths relative to the index
+ * root, using `/` as the separator
+ */
+export function compileIgnorePatterns(patterns: string[]): (relativePath: string) => boolean {
+  const rules = patterns.map(compileRule).filter((rule): rule is IgnoreRule => rule !== null);
+  if (rules.length === 0) return () => false;
+
+  const matches = (path: string, isDir: boolean): boolean => {
+    let ignored = false;
+    for (const rule of rules) {
+      if ((!rule.dirOnly || isDir) && rule.regex.test(path)) {
+        ignored = !rule.negated;
+      }
+    }
+    return ignored;
+  };
+
+  return (relativePath: string) => {
+    const parts = relativePath.split(/[\\/]/).filter(Boolean);
+    for (let i = 1; i < parts.length; i++) {
+      if (matches(parts.slice(0, i).join('/'), true)) return true;
+    }
+    return matches(parts.join('/'), false);
+  };
+}
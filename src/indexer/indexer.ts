@@ -2,94 +2,639 @@
  * Code indexer - scans files, parses, and stores symbols/calls
  */
 
-import { readFileSync, statSync } from 'fs';
+import { cpus } from 'os';
+import { join, posix, relative, resolve, sep } from 'path';
 import { createHash } from 'crypto';
-import fg from 'fast-glob';
 import { CodeDatabase } from '../storage/database.js';
 import { TreeSitterParser } from '../parser/tree-sitter-wrapper.js';
+import { SourceSyntaxError, collectParseErrors } from '../parser/parse-errors.js';
+import { collectComments } from '../parser/comments.js';
 import { TypeScriptExtractor } from '../extractor/typescript-extractor.js';
 import { GoExtractor } from '../extractor/go-extractor.js';
 import { PythonExtractor } from '../extractor/python-extractor.js';
 import { RustExtractor } from '../extractor/rust-extractor.js';
 import { JavaExtractor } from '../extractor/java-extractor.js';
 import { HtmlExtractor } from '../extractor/html-extractor.js';
-import type { IndexOptions, Language } from '../core/types.js';
+import type { ExtractionResult, LanguageExtractor } from '../extractor/language-extractor.js';
+import { defaultBuildContext, evaluateFileConstraints } from './go-build-constraints.js';
+import { diffSymbols, emptyDelta, mergeDelta } from './symbol-delta.js';
+import { changedFilesSince } from './git-changes.js';
+import { assignSignatureHashes } from './symbol-hash.js';
+import { assignStableIds } from './symbol-id.js';
+import { analyzeFieldAlignment } from '../extractor/go-field-alignment.js';
+import { DEFAULT_IGNORE_PATTERNS, compileIgnorePatterns, globToRegExp } from './ignore-patterns.js';
+import { isGeneratedGoFile } from './go-generated.js';
+import { annotateGoTests } from './go-tests.js';
+import { symbolSource } from './symbol-source.js';
+import { normalizeExtraction } from './normalize-names.js';
+import { snakeCase } from './name-case.js';
+import { markOrphanReceivers } from './go-receivers.js';
+import { goPackagePath, parseModulePath, qualifyLocalTypeRefs } from './go-mod.js';
+import { analyzeInitOrder } from './go-init-order.js';
+import { NodeFileSystem } from './source-fs.js';
+import { FileSet, byteOffsetOf } from './file-set.js';
+import type { TokenFile, TokenRange } from './file-set.js';
+import type { SourceFileStats, SourceFileSystem } from './source-fs.js';
+import type {
+  BuildContext,
+  CommentGroup,
+  FileParseError,
+  IndexDelta,
+  IndexOptions,
+  IndexProgress,
+  IndexRunOptions,
+  IndexWarning,
+  Language,
+  PackageIndex,
+  ParseError,
+  RefreshReport,
+  SkippedFile,
+  SkippedSymlink,
+  SymbolPostProcessor,
+  SymbolRecord,
+  SymbolVisitor,
+} from '../core/types.js';
+
+const DEFAULT_MAX_FILE_SIZE = 10 * 1024 * 1024;
+
+interface SourceFile {
+  relativePath: string;
+  language: Language;
+  content: string;
+  stats: SourceFileStats;
+}
 
 export class Indexer {
   private db: CodeDatabase;
   private parser: TreeSitterParser;
-  private tsExtractor: TypeScriptExtractor;
-  private goExtractor: GoExtractor;
-  private pythonExtractor: PythonExtractor;
-  private rustExtractor: RustExtractor;
-  private javaExtractor: JavaExtractor;
-  private htmlExtractor: HtmlExtractor;
+  private extractors: Map<Language, LanguageExtractor>;
   private options: IndexOptions;
+  private buildContext: BuildContext;
+  private ignoreMatcher: (relativePath: string) => boolean;
+  private skippedFiles = new Map<string, SkippedFile>();
+  private warnings = new Map<string, IndexWarning>(); // relative path → warning
+  private fs: SourceFileSystem;
+  private postProcessors: SymbolPostProcessor[] = [];
+  private modulePath: string | undefined; // options.modulePath, or the module path of rootDir/go.mod
+  readonly fileSet: FileSet; // options.fileSet, or one of its own
 
   constructor(options: IndexOptions) {
     this.options = options;
+    this.buildContext = options.buildContext ?? defaultBuildContext();
+    this.ignoreMatcher = compileIgnorePatterns([
+      ...(options.defaultIgnores === false ? [] : DEFAULT_IGNORE_PATTERNS),
+      ...(options.ignore ?? []),
+    ]);
+    this.fs = options.fs ?? new NodeFileSystem(options.rootDir, options.followSymlinks);
+    this.fileSet = options.fileSet ?? new FileSet();
     this.db = new CodeDatabase(options.dbPath);
     this.parser = new TreeSitterParser();
-    this.tsExtractor = new TypeScriptExtractor();
-    this.goExtractor = new GoExtractor(options.maxNestedStructDepth);
-    this.pythonExtractor = new PythonExtractor();
-    this.rustExtractor = new RustExtractor();
-    this.javaExtractor = new JavaExtractor();
-    this.htmlExtractor = new HtmlExtractor();
+    const tsExtractor = new TypeScriptExtractor();
+    this.extractors = new Map<Language, LanguageExtractor>([
+      ['ts', tsExtractor],
+      ['tsx', tsExtractor],
+      ['js', tsExtractor],
+      ['jsx', tsExtractor],
+      ['go', new GoExtractor(
+        options.maxNestedStructDepth,
+        options.computeComplexity,
+        options.includeKinds,
+        options.includeLocals,
+        options.maxSymbolsPerFile
+      )],
+      ['python', new PythonExtractor()],
+      ['rust', new RustExtractor()],
+      ['java', new JavaExtractor()],
+      ['html', new HtmlExtractor()],
+    ]);
+  }
+
+  /**
+   * Use a custom extractor for a language instead of the built-in one.
+   * Call before indexing; files already indexed keep their old symbols
+   * until they change or the index is rebuilt.
+   */
+  registerExtractor(language: Language, extractor: LanguageExtractor): void {
+    this.extractors.set(language, extractor);
+  }
+
+  /**
+   * Add a post-processor. Processors run on each symbol in registration
+   * order, after all built-in extraction and annotation.
+   */
+  use(processor: SymbolPostProcessor): void {
+    this.postProcessors.push(processor);
   }
 
   async init(): Promise<void> {
     await this.parser.init(this.options.languages);
+    this.modulePath = this.options.modulePath ?? (await this.readModulePath());
   }
 
-  async indexAll(onProgress?: (current: number, total: number) => void): Promise<void> {
-    const files = await this.scanFiles();
+  /**
+   * The module path declared by go.mod at rootDir, if there is one
+   */
+  private async readModulePath(): Promise<string | undefined> {
+    if (!this.options.languages.includes('go') || !(await this.fs.stat('go.mod'))) {
+      return undefined;
+    }
+    return parseModulePath(await this.fs.readFile('go.mod')) ?? undefined;
+  }
+
+  /**
+   * Index every file matched by include/exclude, taking files from the
+   * stream indexStream also consumes. Files are read `concurrency` at a
+   * time; parsing and storing are not concurrent and happen in path order,
+   * so symbol ids do not depend on which read finishes first. A file that
+   * fails does not stop the run; all failures are thrown together as an
+   * AggregateError once every file has been tried.
+   * The exception is a syntax error under strictParse, which is thrown as a
+   * SourceSyntaxError right away.
+   * Aborting options.signal stops the run before the next file (see
+   * IndexRunOptions).
+   */
+  async indexAll(onProgress?: IndexProgress, options: IndexRunOptions = {}): Promise<void> {
+    await this.indexFiles(onProgress, options, false);
+  }
+
+  /**
+   * Clear the database, then index every file as indexAll does. An aborted
+   * rebuild that discards its partial results leaves the old index in place.
+   */
+  async rebuildAll(onProgress?: IndexProgress, options: IndexRunOptions = {}): Promise<void> {
+    await this.indexFiles(onProgress, options, true);
+  }
+
+  /**
+   * Index every file, after clearing the database when clear is set. Unless
+   * keepPartial is set, a run that options.signal may abort reads every file
+   * first and then writes them all in one synchronous transaction: an
+   * abandoned run has written nothing, and no other write on the connection
+   * can land inside the transaction, as it could while awaiting a read.
+   * Failed files do not roll back the rest.
+   */
+  private async indexFiles(onProgress: IndexProgress | undefined, options: IndexRunOptions, clear: boolean): Promise<void> {
+    const { signal } = options;
+    signal?.throwIfAborted();
+    const atomic = Boolean(signal) && !options.keepPartial;
+    const files = (await this.scanFiles()).sort();
+    const concurrency = Math.max(1, this.options.concurrency ?? cpus().length);
+    const errors: Error[] = [];
+    this.skippedFiles.clear();
+    this.warnings.clear();
     
     if (!onProgress) {
       console.log(`Found ${files.length} files to index`);
     }
 
+    let processed = 0;
     let indexed = 0;
-    for (const filePath of files) {
+    // Store one file; a syntax error under strictParse is returned, as it stops the run
+    const store = (filePath: string, source: SourceFile | null | { error: unknown }): SourceSyntaxError | undefined => {
       try {
-        await this.indexFile(filePath);
-        indexed++;
-        if (onProgress) {
-          onProgress(indexed, files.length);
-        } else if (indexed % 10 === 0) {
-          console.log(`Indexed ${indexed}/${files.length} files`);
+        if (source && 'error' in source) {
+          throw source.error;
         }
+        if (source) {
+          this.storeFile(source);
+        } else {
+          this.dropSkipped(this.relativePathOf(filePath));
+        }
+        indexed++;
       } catch (error) {
+        if (error instanceof SourceSyntaxError) {
+          return error;
+        }
         console.error(`Error indexing ${filePath}:`, error);
+        const message = error instanceof Error ? error.message : String(error);
+        errors.push(new Error(`${filePath}: ${message}`, { cause: error }));
+      }
+
+      processed++;
+      if (onProgress) {
+        onProgress(processed, files.length, this.relativePathOf(filePath));
+      } else if (processed % 10 === 0) {
+        console.log(`Indexed ${processed}/${files.length} files`);
+      }
+      return undefined;
+    };
+
+    const read = async (source: Promise<SourceFile | null>) => source.catch((error: unknown) => ({ error }));
+    if (atomic) {
+      const sources: Array<{ filePath: string; source: SourceFile | null | { error: unknown } }> = [];
+      for await (const { filePath, source } of this.sourceStream(files, concurrency, signal)) {
+        sources.push({ filePath, source: await read(source) });
+      }
+      let syntaxError: SourceSyntaxError | undefined;
+      this.db.transaction(() => {
+        if (clear) this.db.clearAll();
+        for (const { filePath, source } of sources) {
+          syntaxError = store(filePath, source);
+          if (syntaxError) break;
+        }
+      });
+      if (syntaxError) throw syntaxError; // strictParse: stop at the first broken file
+    } else {
+      if (clear) this.db.clearAll();
+      for await (const { filePath, source } of this.sourceStream(files, concurrency, signal)) {
+        const syntaxError = store(filePath, await read(source));
+        if (syntaxError) throw syntaxError; // strictParse: stop at the first broken file
       }
     }
 
     if (!onProgress) {
       console.log(`Indexing complete: ${indexed} files indexed`);
     }
+
+    if (errors.length > 0) {
+      throw new AggregateError(errors, `Failed to index ${errors.length} of ${files.length} files`);
+    }
+  }
+
+  /**
+   * Parse every file matched by include/exclude and hand each symbol to visit,
+   * without writing to the database. Files come from the same stream
+   * indexAll consumes, one at a time in path order, and nothing is kept once
+   * a file's symbols have been visited, so memory stays bounded by the
+   * largest file. The first error, from reading, parsing or the visitor,
+   * stops the stream and is rethrown.
+   */
+  async indexStream(visit: SymbolVisitor): Promise<void> {
+    const files = (await this.scanFiles()).sort();
+
+    for await (const { source: read } of this.sourceStream(files, 1)) {
+      const source = await read;
+      const extraction = source && this.extractSource(source);
+      if (!source || !extraction) continue;
+
+      for (const symbol of extraction.symbols) {
+        await visit(symbol, source.relativePath);
+      }
+    }
+  }
+
+  /**
+   * The files to index in path order, each with the promise of its content
+   * (null for files left out, see readSource). Up to concurrency files are
+   * read ahead; a read that fails rejects only its own file's promise.
+   * Aborting signal stops the stream before the next file.
+   */
+  private async *sourceStream(
+    files: string[],
+    concurrency: number,
+    signal?: AbortSignal
+  ): AsyncGenerator<{ filePath: string; source: Promise<SourceFile | null> }> {
+    for (const batch of this.createBatches(files, concurrency)) {
+      signal?.throwIfAborted();
+      const sources = batch.map(filePath => this.readSource(filePath));
+      await Promise.allSettled(sources);
+
+      for (const [i, source] of sources.entries()) {
+        signal?.throwIfAborted();
+        yield { filePath: batch[i], source };
+      }
+    }
+  }
+
+  /**
+   * Index every .go file in a directory (non-recursive) as one Go package.
+   * Methods are tied to their receiver types by qualified name, so a type and
+   * its methods may live in different files, in either order; a method whose
+   * type no file declares is kept with details.orphanReceiver. The result
+   * also lists the package's init functions and its variables in
+   * initialization order (see analyzeInitOrder). Test files are
+   * skipped unless includeTests is set; an external test file (package
+   * foo_test) belongs to package foo. Throws, leaving the index as it was,
+   * if the files disagree on the package name.
+   */
+  async indexPackage(dir: string, options: { includeTests?: boolean } = {}): Promise<PackageIndex> {
+    const absoluteDir = resolve(this.options.rootDir, dir);
+    const goFiles = (await this.fs.readDir(this.fsPathOf(absoluteDir)))
+      .filter(name => name.endsWith('.go'))
+      .filter(name => options.includeTests || !name.endsWith('_test.go'))
+      .map(name => join(absoluteDir, name))
+      .sort();
+
+    // Read everything first, so the files are stored and their package clauses
+    // checked in one transaction that a mismatch rolls back
+    const sources = await Promise.all(goFiles.map(filePath => this.readSource(filePath)));
+
+    let packageName = '';
+    let packageFile = '';
+    const files: string[] = [];
+    const imports = new Set<string>();
+    const symbols: SymbolRecord[] = [];
+    const parseErrors: FileParseError[] = [];
+
+    this.db.transaction(() => {
+      for (const [i, source] of sources.entries()) {
+        if (source) {
+          this.storeFile(source);
+        } else {
+          this.dropSkipped(this.relativePathOf(goFiles[i]));
+        }
+      }
+
+      for (const filePath of goFiles) {
+        const relativePath = this.relativePathOf(filePath);
+        const file = this.db.getFileByPath(relativePath);
+        if (!file) continue;
+
+        const filePackage = file.isExternalTest
+          ? (file.packageName || '').replace(/_test$/, '')
+          : file.packageName || '';
+        if (!packageName) {
+          packageName = filePackage;
+          packageFile = relativePath;
+        } else if (filePackage !== packageName) {
+          throw new Error(
+            `Package mismatch in ${dir}: ${packageFile} declares "${packageName}" but ${relativePath} declares "${file.packageName || ''}"`
+          );
+        }
+
+        files.push(relativePath);
+        for (const imp of this.db.getImportsByFile(file.fileId!)) {
+          imports.add(imp.path);
+        }
+        symbols.push(...this.db.getSymbolsInFile(file.fileId!).sort((a, b) => a.startLine - b.startLine || a.startCol - b.startCol));
+        parseErrors.push(...this.db.findParseErrors(relativePath));
+      }
+    });
+    markOrphanReceivers(symbols);
+    const { inits, initOrder } = analyzeInitOrder(symbols);
+
+    return {
+      dir,
+      packageName,
+      packagePath: goPackagePath(this.modulePath, this.fsPathOf(absoluteDir), false),
+      files,
+      imports: Array.from(imports).sort(),
+      symbols,
+      parseErrors,
+      inits,
+      initOrder,
+    };
+  }
+
+  /**
+   * Re-index a single file in place and report how its symbols changed.
+   * A file that no longer exists, or that include, exclude and ignore leave
+   * out, is removed from the index. Symbols of other files are untouched;
+   * methods find their receiver type by qualified name, so they stay
+   * attached whichever file is re-indexed.
+   */
+  async updateFile(filePath: string): Promise<IndexDelta> {
+    return this.reindexFile(filePath, this.isInScope(resolve(this.options.rootDir, filePath)));
+  }
+
+  /**
+   * updateFile for a path whose scope is known, e.g. because scanFiles
+   * listed it
+   */
+  private async reindexFile(filePath: string, inScope: boolean): Promise<IndexDelta> {
+    const absolutePath = resolve(this.options.rootDir, filePath);
+    const relativePath = this.relativePathOf(absolutePath);
+    const before = this.symbolsOfFile(relativePath);
+
+    if (inScope && await this.fs.stat(this.fsPathOf(absolutePath))) {
+      await this.indexFile(absolutePath);
+    } else {
+      const file = this.db.getFileByPath(relativePath);
+      if (file) {
+        this.db.deleteFile(file.fileId!);
+      }
+    }
+
+    return diffSymbols(before, this.symbolsOfFile(relativePath));
+  }
+
+  /**
+   * Re-index files whose mtime differs from the indexed one and drop files
+   * that have disappeared, returning the combined symbol changes and what
+   * happened to each file (see RefreshReport). A file whose mtime changed
+   * but whose content hashes the same is reported unchanged.
+   */
+  async refreshAll(onProgress?: IndexProgress): Promise<RefreshReport> {
+    const report: RefreshReport = { ...emptyDelta(), reparsed: [], unchanged: [], deleted: [], errors: {} };
+    const files = await this.scanFiles();
+    const onDisk = new Set<string>();
+
+    let checked = 0;
+    for (const filePath of files) {
+      const relativePath = this.relativePathOf(filePath);
+      onDisk.add(relativePath);
+
+      try {
+        const existingFile = this.db.getFileByPath(relativePath);
+        const stats = await this.fs.stat(this.fsPathOf(filePath));
+        if (!existingFile || existingFile.mtime !== stats?.mtimeMs) {
+          mergeDelta(report, await this.reindexFile(filePath, true));
+          const file = this.db.getFileByPath(relativePath);
+          if (!stats) {
+            if (existingFile) report.deleted.push(relativePath); // gone since the scan
+          } else if (existingFile && file && existingFile.contentHash === file.contentHash) {
+            report.unchanged.push(relativePath);
+          } else {
+            report.reparsed.push(relativePath);
+          }
+        } else {
+          report.unchanged.push(relativePath);
+        }
+      } catch (error) {
+        console.error(`Error indexing ${filePath}:`, error);
+        report.errors[relativePath] = error instanceof Error ? error : new Error(String(error));
+      }
+
+      checked++;
+      onProgress?.(checked, files.length, relativePath);
+    }
+
+    for (const file of this.db.getAllFiles()) {
+      if (!onDisk.has(file.path)) {
+        report.removed.push(...this.db.getSymbolsInFile(file.fileId!));
+        this.db.deleteFile(file.fileId!);
+        report.deleted.push(file.path);
+      }
+    }
+    for (const path of this.skippedFiles.keys()) {
+      if (!onDisk.has(path)) {
+        this.skippedFiles.delete(path);
+      }
+    }
+    for (const path of this.warnings.keys()) {
+      if (!onDisk.has(path)) {
+        this.warnings.delete(path);
+      }
+    }
+
+    for (const paths of [report.reparsed, report.unchanged, report.deleted]) {
+      paths.sort((a, b) => (a < b ? -1 : a > b ? 1 : 0));
+    }
+    return report;
+  }
+
+  /**
+   * Bring only the files git reports as changed since ref up to date, as
+   * updateFile does for each: changed files that include/exclude select are
+   * re-indexed and deleted ones dropped. A rename is handled as the deletion
+   * of the old path plus the addition of the new one. rootDir must be inside
+   * a git work tree on the local disk.
+   */
+  async indexChangedSince(ref: string): Promise<IndexDelta> {
+    const delta = emptyDelta();
+    const changed = await changedFilesSince(this.options.rootDir, ref);
+    if (changed.length === 0) {
+      return delta;
+    }
+
+    const indexable = new Set(await this.scanFiles());
+    for (const path of changed) {
+      const filePath = resolve(this.options.rootDir, path);
+      const deleted = !(await this.fs.stat(this.fsPathOf(filePath)));
+      if (indexable.has(filePath) || deleted) {
+        mergeDelta(delta, await this.reindexFile(filePath, true));
+      }
+    }
+    return delta;
   }
 
   async indexFile(filePath: string): Promise<void> {
+    const source = await this.readSource(filePath);
+    if (source) {
+      this.storeFile(source);
+    } else {
+      this.dropSkipped(this.relativePathOf(filePath));
+    }
+  }
+
+  /**
+   * Index source text that is not (or not yet) on disk, such as an unsaved
+   * editor buffer, as if it were the content of filePath. Gives the same
+   * symbols as indexFile on a file with that content, and returns them.
+   * The record gets mtime 0, so the next refreshAll re-reads the file from
+   * disk. Unsupported languages and content over maxFileSize are ignored
+   * and return [].
+   */
+  indexSource(filePath: string, content: string | Buffer): SymbolRecord[] {
+    const relativePath = this.relativePathOf(resolve(this.options.rootDir, filePath));
+    const language = this.languageOf(relativePath);
+    if (!language) {
+      return [];
+    }
+
+    const text = typeof content === 'string' ? content : content.toString('utf-8');
+    if (this.skipIfTooLarge(relativePath, Buffer.byteLength(text))) {
+      this.dropSkipped(relativePath);
+      return [];
+    }
+    this.storeFile({ relativePath, language, content: text, stats: { mtimeMs: 0, size: Buffer.byteLength(text) } });
+    return this.symbolsOfFile(relativePath);
+  }
+
+  /**
+   * Read a file that should be indexed; null for unsupported languages and
+   * files over maxFileSize, which are not even read
+   */
+  private async readSource(filePath: string): Promise<SourceFile | null> {
     // Normalize path relative to root
-    const relativePath = filePath.startsWith(this.options.rootDir)
-      ? filePath.slice(this.options.rootDir.length + 1)
-      : filePath;
+    const relativePath = this.relativePathOf(filePath);
 
     // Get language
-    const language = this.parser.getLanguageForFile(relativePath);
-    if (!language || !this.options.languages.includes(language)) {
-      return;
+    const language = this.languageOf(relativePath);
+    if (!language || this.isIgnored(filePath)) {
+      return null;
+    }
+
+    const fsPath = this.fsPathOf(filePath);
+    const stats = await this.fs.stat(fsPath);
+    if (!stats) {
+      throw new Error(`File not found: ${filePath}`);
+    }
+    if (this.skipIfTooLarge(relativePath, stats.size)) {
+      return null;
+    }
+    const content = await this.fs.readFile(fsPath);
+    return { relativePath, language, content, stats };
+  }
+
+  /**
+   * Record a file larger than maxFileSize as skipped; dropSkipped then
+   * removes whatever the index had for it. Returns false, and forgets an
+   * earlier skip, for files within the limit.
+   */
+  private skipIfTooLarge(relativePath: string, size: number): boolean {
+    const maxFileSize = this.options.maxFileSize ?? DEFAULT_MAX_FILE_SIZE;
+    if (maxFileSize <= 0 || size <= maxFileSize) {
+      this.skippedFiles.delete(relativePath);
+      return false;
+    }
+
+    const reason = `exceeds maxFileSize (${maxFileSize} bytes)`;
+    console.warn(`Skipping ${relativePath}: ${size} bytes ${reason}`);
+    this.skippedFiles.set(relativePath, { path: relativePath, size, reason });
+    return true;
+  }
+
+  /**
+   * Drop what the index has for a file skipped as too large. Kept apart from
+   * skipIfTooLarge so reading a file never writes to the database.
+   */
+  private dropSkipped(relativePath: string): void {
+    const existingFile = this.skippedFiles.has(relativePath) && this.db.getFileByPath(relativePath);
+    if (existingFile) {
+      this.db.deleteFile(existingFile.fileId!);
     }
+  }
 
-    // Read file
-    const content = readFileSync(filePath, 'utf-8');
-    const stats = statSync(filePath);
+  /**
+   * Files left out of the index since the last indexAll, sorted by path
+   */
+  getSkippedFiles(): SkippedFile[] {
+    return Array.from(this.skippedFiles.values()).sort((a, b) => a.path.localeCompare(b.path));
+  }
+
+  /**
+   * Problems that did not stop a file from being indexed, such as a symbol
+   * count over maxSymbolsPerFile, sorted by path
+   */
+  getWarnings(): IndexWarning[] {
+    return Array.from(this.warnings.values()).sort((a, b) => a.path.localeCompare(b.path));
+  }
+
+  /**
+   * Symbolic links the last scan did not follow (see IndexOptions.followSymlinks)
+   */
+  getSkippedSymlinks(): SkippedSymlink[] {
+    return this.fs.skippedSymlinks?.() ?? [];
+  }
+
+  private languageOf(relativePath: string): Language | null {
+    const language = this.parser.getLanguageForFile(relativePath);
+    return language && this.options.languages.includes(language) ? language : null;
+  }
+
+  /**
+   * Parse a file that has been read and replace its records in the database
+   */
+  private storeFile({ relativePath, language, content, stats }: SourceFile): void {
     const contentHash = this.hashContent(content);
 
     // Check if file needs reindexing
     const existingFile = this.db.getFileByPath(relativePath);
     if (existingFile && existingFile.contentHash === contentHash) {
-      // File hasn't changed, skip
+      // File hasn't changed, skip (but remember the new mtime so refreshAll skips it next time)
+      if (existingFile.mtime !== stats.mtimeMs) {
+        this.db.updateFileMtime(existingFile.fileId!, stats.mtimeMs);
+      }
+      return;
+    }
+
+    // Files excluded by build constraints, excludeGenerated or includeTests lose whatever they had in the index
+    const extraction = this.extractSource({ relativePath, language, content, stats });
+    if (!extraction) {
+      if (existingFile) {
+        this.db.deleteFile(existingFile.fileId!);
+      }
       return;
     }
 
@@ -98,6 +643,12 @@ export class Indexer {
       this.db.deleteSymbolsByFile(existingFile.fileId!);
       this.db.deleteCallsByFile(existingFile.fileId!);
       this.db.deleteReferencesByFile(existingFile.fileId!);
+      this.db.deleteImportsByFile(existingFile.fileId!);
+      this.db.deleteDirectivesByFile(existingFile.fileId!);
+      this.db.deleteParseErrorsByFile(existingFile.fileId!);
+      this.db.deleteCommentsByFile(existingFile.fileId!);
+      this.db.deleteCallEdgesByFile(existingFile.fileId!);
+      this.db.deleteNameUsesByFile(existingFile.fileId!);
     }
 
     // Insert/update file record
@@ -107,36 +658,30 @@ export class Indexer {
       contentHash,
       mtime: stats.mtimeMs,
       size: stats.size,
+      packageName: extraction.packageName,
+      isGenerated: extraction.isGenerated,
+      isTest: extraction.isTest,
+      isExternalTest: extraction.isTest && extraction.packageName?.endsWith('_test'),
+      packagePath: extraction.packagePath,
     });
 
-    // Parse AST
-    const parseResult = this.parser.parse(content, language);
-
-    // Extract symbols and calls using appropriate extractor
-    let extraction;
-    if (language === 'go') {
-      extraction = this.goExtractor.extract(parseResult.tree, content, language);
-    } else if (language === 'python') {
-      extraction = this.pythonExtractor.extract(parseResult.tree, content, language);
-    } else if (language === 'rust') {
-      extraction = this.rustExtractor.extract(parseResult.tree, content, language);
-    } else if (language === 'java') {
-      extraction = this.javaExtractor.extract(parseResult.tree, content, language);
-    } else if (language === 'html') {
-      extraction = this.htmlExtractor.extract(parseResult.tree, content, language);
-    } else {
-      extraction = this.tsExtractor.extract(parseResult.tree, content, language);
-    }
-
     // Store symbols
     const symbolMap = new Map<string, number>(); // qualifiedName -> symbolId
     
     this.db.transaction(() => {
+      for (const imp of extraction.imports ?? []) {
+        this.db.insertImport({ ...imp, fileId });
+      }
+      this.db.insertNameUses(fileId, extraction.usedNames ?? []);
+      for (const directive of extraction.directives ?? []) {
+        this.db.insertDirective(fileId, directive);
+      }
+      this.db.insertParseErrors(fileId, extraction.parseErrors);
+      this.db.insertComments(fileId, extraction.comments);
+
       for (const symbol of extraction.symbols) {
-        const symbolId = this.db.insertSymbol({
-          ...symbol,
-          fileId,
-        });
+        const searchName = this.options.nameForms ? snakeCase(symbol.name) : undefined;
+        const symbolId = this.db.insertSymbol({ ...symbol, fileId }, searchName);
         symbolMap.set(symbol.qualifiedName, symbolId);
       }
 
@@ -171,6 +716,22 @@ export class Indexer {
         }
       }
 
+      // Store statically resolved call edges; callees are matched by qualified name at query time
+      for (const edge of extraction.callEdges ?? []) {
+        const callerSymbolId = symbolMap.get(edge.callerQualifiedName);
+        if (callerSymbolId) {
+          this.db.insertCallEdge({
+            callerSymbolId,
+            calleeQualifiedName: edge.calleeQualifiedName,
+            siteFileId: fileId,
+            siteStartLine: edge.siteStartLine,
+            siteStartCol: edge.siteStartCol,
+            siteEndLine: edge.siteEndLine,
+            siteEndCol: edge.siteEndCol,
+          });
+        }
+      }
+
       // Store references
       for (const ref of extraction.references) {
         const targetSymbols = this.db.findSymbolsByName(ref.name);
@@ -190,6 +751,204 @@ export class Indexer {
     });
   }
 
+  /**
+   * Parse a file and extract its symbols, applying the build context,
+   * exportedOnly, generated-file handling, signature hashes, includeSource and
+   * the registered post-processors. Returns null for Go files excluded by build constraints,
+   * by excludeGenerated or, for _test.go files, by includeTests: false.
+   */
+  private extractSource(
+    { relativePath, language, content }: SourceFile
+  ): (ExtractionResult & {
+    isGenerated: boolean;
+    isTest: boolean;
+    packagePath?: string;
+    parseErrors: ParseError[];
+    comments: CommentGroup[];
+  }) | null {
+    // Skip Go files excluded by build constraints for the current build context
+    let buildConstraint: string | undefined;
+    if (language === 'go') {
+      const constraint = evaluateFileConstraints(relativePath, content, this.buildContext);
+      if (!constraint.matches && !this.options.allPlatforms) {
+        return null;
+      }
+      if (this.options.allPlatforms) {
+        buildConstraint = constraint.constraint;
+      }
+    }
+
+    const isGenerated = language === 'go' && isGeneratedGoFile(content);
+    if (isGenerated && this.options.excludeGenerated) {
+      return null;
+    }
+    const isTest = language === 'go' && relativePath.endsWith('_test.go');
+    if (isTest && this.options.includeTests === false) {
+      return null;
+    }
+
+    // Parse AST; tree-sitter recovers from syntax errors, so unless strictParse is set
+    // the declarations that did parse are indexed and the errors recorded
+    const parseResult = this.parser.parse(content, language);
+    const parseErrors = collectParseErrors(parseResult.tree);
+    if (parseErrors.length > 0) {
+      if (this.options.strictParse) {
+        throw new SourceSyntaxError(relativePath, parseErrors);
+      }
+      console.warn(`${relativePath}: ${parseErrors.length} syntax error(s), indexing the declarations that parsed`);
+    }
+    const comments = this.options.includeComments ? collectComments(parseResult.tree) : [];
+
+    // Extract symbols and calls using the extractor registered for the language
+    const extractor = this.extractors.get(language);
+    if (!extractor) {
+      throw new Error(`No extractor registered for language: ${language}`);
+    }
+    const extraction = extractor.extract(parseResult.tree, content, language);
+    this.limitSymbols(relativePath, extraction);
+    normalizeExtraction(extraction, this.options.normalizeForm);
+
+    // Alignment needs every field of a struct, so it runs before exportedOnly drops any
+    if (language === 'go' && this.options.fieldAlignment) {
+      this.annotateFieldAlignment(extraction.symbols);
+    }
+    if (language === 'go' && this.options.exportedOnly) {
+      extraction.symbols = this.exportedSymbols(extraction.symbols);
+    }
+    if (language === 'go') {
+      annotateGoTests(relativePath, extraction);
+    }
+    const dir = posix.dirname(relativePath.split(sep).join('/'));
+    if (language === 'go' && this.modulePath) {
+      qualifyLocalTypeRefs(extraction.symbols, this.modulePath, dir);
+    }
+    const packagePath = language === 'go'
+      ? goPackagePath(this.modulePath, dir, isTest && Boolean(extraction.packageName?.endsWith('_test')))
+      : undefined;
+    assignSignatureHashes(extraction.symbols);
+    assignStableIds(extraction.symbols, relativePath.split(sep).join('/'));
+    // Dropped only now, as a struct's field alignment and hash depend on its fields
+    const includeKinds = this.options.includeKinds ?? [];
+    if (includeKinds.length > 0) {
+      extraction.symbols = extraction.symbols.filter(symbol => includeKinds.includes(symbol.kind));
+    }
+    if (this.options.includeSource) {
+      const lines = content.split('\n');
+      for (const symbol of extraction.symbols) {
+        const source = symbolSource(lines, symbol, language, this.options.includeDocInSource);
+        symbol.details = { ...symbol.details, source };
+      }
+    }
+    if (buildConstraint) {
+      for (const symbol of extraction.symbols) {
+        symbol.details = { ...symbol.details, buildConstraint };
+      }
+    }
+    if (isGenerated) {
+      for (const symbol of extraction.symbols) {
+        symbol.details = { ...symbol.details, generated: true };
+      }
+    }
+    if (isTest) {
+      for (const symbol of extraction.symbols) {
+        symbol.details = { ...symbol.details, testScope: true };
+      }
+    }
+    if (packagePath) {
+      for (const symbol of extraction.symbols) {
+        symbol.details = { ...symbol.details, packagePath };
+      }
+    }
+    for (const symbol of extraction.symbols) {
+      for (const processor of this.postProcessors) {
+        processor.process(symbol, relativePath);
+      }
+    }
+
+    return { ...extraction, isGenerated, isTest, packagePath, parseErrors, comments };
+  }
+
+  /**
+   * Record a warning naming the file and the limit when extraction stopped
+   * at maxSymbolsPerFile. The Go extractor stops by itself; other
+   * extractors' symbols are cut to the first maxSymbolsPerFile here, before
+   * any further work is done on them. A file within the limit forgets an
+   * earlier warning.
+   */
+  private limitSymbols(relativePath: string, extraction: ExtractionResult): void {
+    const maxSymbols = this.options.maxSymbolsPerFile ?? 0;
+    if (maxSymbols <= 0 || (!extraction.truncated && extraction.symbols.length <= maxSymbols)) {
+      this.warnings.delete(relativePath);
+      return;
+    }
+
+    const message = `stopped at maxSymbolsPerFile (${maxSymbols} symbols); later declarations were not indexed`;
+    console.warn(`${relativePath}: ${message}`);
+    this.warnings.set(relativePath, { path: relativePath, message });
+    extraction.symbols = extraction.symbols.slice(0, maxSymbols);
+  }
+
+  /**
+   * Record a fieldAlignment warning on every struct whose fields could be
+   * reordered to make it smaller
+   */
+  private annotateFieldAlignment(symbols: Array<Omit<SymbolRecord, 'fileId' | 'symbolId'>>): void {
+    for (const struct of symbols) {
+      if (struct.kind !== 'struct') continue;
+
+      const fields = symbols
+        .filter(s => s.kind === 'field' && s.qualifiedName === `${struct.qualifiedName}.${s.name}`)
+        .sort((a, b) => (a.details?.fieldIndex ?? 0) - (b.details?.fieldIndex ?? 0));
+      const warning = analyzeFieldAlignment(fields);
+      if (warning) {
+        struct.details = { ...struct.details, fieldAlignment: warning };
+      }
+    }
+  }
+
+  /**
+   * Keep only exported Go symbols whose enclosing type is exported too.
+   * Embedded fields are kept whatever their name: an embedded unexported type
+   * still promotes its exported methods, so downstream method-set resolution
+   * needs to know about it.
+   */
+  private exportedSymbols<T extends Omit<SymbolRecord, 'fileId' | 'symbolId'>>(symbols: T[]): T[] {
+    const dropped = new Set<string>();
+    const isExportedName = (name: string) => /^\p{Lu}/u.test(name);
+
+    return symbols.filter(symbol => {
+      const parentName = symbol.qualifiedName.slice(0, symbol.qualifiedName.lastIndexOf('.'));
+      const receiverType = symbol.details?.receiverType;
+      const keep =
+        !dropped.has(parentName) &&
+        (symbol.exported || symbol.details?.isEmbedded === true) &&
+        (receiverType === undefined || isExportedName(receiverType));
+
+      if (!keep) {
+        dropped.add(symbol.qualifiedName);
+      }
+      return keep;
+    });
+  }
+
+  /**
+   * Whether a file currently has a record in the index
+   */
+  isIndexed(filePath: string): boolean {
+    return this.db.getFileByPath(this.relativePathOf(resolve(this.options.rootDir, filePath))) !== undefined;
+  }
+
+  private symbolsOfFile(relativePath: string): SymbolRecord[] {
+    const file = this.db.getFileByPath(relativePath);
+    return file ? this.db.getSymbolsInFile(file.fileId!) : [];
+  }
+
+  private relativePathOf(filePath: string): string {
+    return filePath.startsWith(this.options.rootDir)
+      ? filePath.slice(this.options.rootDir.length + 1)
+      : filePath;
+  }
+
   private findContainingSymbol(
     symbols: Array<{ qualifiedName: string; startLine: number; endLine: number }>,
     line: number
@@ -211,18 +970,85 @@ export class Indexer {
     return bestMatch;
   }
 
+  /**
+   * Files to index: those matching include, minus exclude globs, minus paths
+   * caught by the gitignore-style ignore rules (see ignore-patterns.ts)
+   */
   private async scanFiles(): Promise<string[]> {
     const patterns = this.options.include || ['**/*'];
     const ignore = this.options.exclude || [];
 
-    const files = await fg(patterns, {
-      cwd: this.options.rootDir,
-      absolute: true,
-      ignore,
-      onlyFiles: true,
-    });
+    const files = (await this.fs.glob(patterns, ignore)).map(file => resolve(this.options.rootDir, file));
+    return files.filter(file => !this.isIgnored(file));
+  }
+
+  private isIgnored(filePath: string): boolean {
+    return this.ignoreMatcher(this.fsPathOf(filePath));
+  }
+
+  /**
+   * Whether scanFiles would list filePath, for files named one at a time.
+   * include and exclude are matched as ignore-patterns.ts matches globs.
+   */
+  private isInScope(filePath: string): boolean {
+    const path = this.fsPathOf(filePath);
+    const matches = (globs: string[]) => globs.some(glob => globToRegExp(glob).test(path));
+    return matches(this.options.include || ['**/*']) && !matches(this.options.exclude || []) && !this.isIgnored(filePath);
+  }
+
+  /**
+   * A path as the file system abstraction expects it: relative to rootDir,
+   * separated by '/'
+   */
+  private fsPathOf(filePath: string): string {
+    const rootDir = resolve(this.options.rootDir);
+    return relative(rootDir, resolve(rootDir, filePath)).split(sep).join('/') || '.';
+  }
+
+  private createBatches<T>(items: T[], batchSize: number): T[][] {
+    const batches: T[][] = [];
+    for (let i = 0; i < items.length; i += batchSize) {
+      batches.push(items.slice(i, i + batchSize));
+    }
+    return batches;
+  }
+
+  /**
+   * The fileSet entry for a file, named by its absolute path. An entry of
+   * the same name and content hash is reused, so files the caller's own
+   * loader added keep the positions it gave them; otherwise the file is
+   * added. Files are added only when tokenRange needs them.
+   */
+  private tokenFileOf(relativePath: string, content: string, contentHash: string): TokenFile {
+    const filename = resolve(this.options.rootDir, relativePath);
+    const file = this.fileSet.file(filename);
+    return file && file.contentHash === contentHash ? file : this.fileSet.addFile(filename, content);
+  }
+
+  /**
+   * A symbol's range as Pos values of fileSet, or null when its file is
+   * gone or has changed since it was indexed. Positions are not stored:
+   * they are worked out from the file's current content, which is read
+   * again.
+   */
+  async tokenRange(symbol: SymbolRecord): Promise<TokenRange | null> {
+    const location = symbol.symbolId !== undefined ? this.db.getSymbolLocation(symbol.symbolId) : undefined;
+    const file = location && this.db.getFileByPath(location.path);
+    if (!location || !file) return null;
+
+    let content: string;
+    try {
+      content = await this.fs.readFile(this.fsPathOf(location.path));
+    } catch {
+      return null;
+    }
+    if (this.hashContent(content) !== file.contentHash) return null;
 
-    return files;
+    const tokenFile = this.tokenFileOf(location.path, content, file.contentHash);
+    return {
+      pos: tokenFile.pos(byteOffsetOf(content, location.startLine, location.startCol)),
+      end: tokenFile.pos(byteOffsetOf(content, location.endLine, location.endCol)),
+    };
   }
 
   private hashContent(content: string): string {
@@ -0,0 +1,25 @@
+/**
+ * snake_case forms of identifiers, for searching across naming conventions
+ */
+
+// One word of a name: an acronym (kept whole, with trailing digits) when not
+// followed by a lower-case letter, a capitalized or lower-case word with
+// trailing digits, a run of digits, or any other run of characters
+const WORD = /\p{Lu}+\p{N}*(?!\p{Ll})|\p{Lu}?\p{Ll}+\p{N}*|\p{N}+|[^\p{Lu}\p{Ll}\p{N}]+/gu;
+
+/**
+ * Split a name into words at underscores, hyphens, spaces and case changes:
+ * 'GetUserByEmail' → Get, User, By, Email; 'HTTPServer' → HTTP, Server;
+ * 'Base64Encode' → Base64, Encode
+ */
+export function nameWords(name: string): string[] {
+  return name.split(/[_\-\s]+/).flatMap(part => part.match(WORD) ?? []);
+}
+
+/**
+ * 'GetUserByEmail' → 'get_user_by_email', 'HTTPServer' → 'http_server'.
+ * Already snake_case or kebab-case names come out in snake_case.
+ */
+export function snakeCase(name: string): string {
+  return nameWords(name).map(word => word.toLowerCase()).join('_');
+}
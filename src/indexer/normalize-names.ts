@@ -0,0 +1,53 @@
+/**
+ * Unicode normalization of identifiers
+ */
+
+import type { ExtractionResult } from '../extractor/language-extractor.js';
+import type { NormalizeForm } from '../core/types.js';
+
+/**
+ * Go allows Unicode identifiers, and the same name can be written precomposed
+ * (é, U+00E9) or with a combining mark (e + U+0301). Both the index and the
+ * queries normalize to one form so the two spellings match.
+ */
+export function normalizeName(name: string, form: NormalizeForm = 'NFC'): string {
+  return form === 'none' ? name : name.normalize(form);
+}
+
+/**
+ * Normalize every name an extraction reports, in place: symbol names,
+ * qualified names, signatures, receiver types and body references, plus the names in calls,
+ * references, call edges, used names and directives. Runs before symbols are
+ * hashed, so the hashes do not depend on the spelling either.
+ */
+export function normalizeExtraction(extraction: ExtractionResult, form: NormalizeForm = 'NFC'): void {
+  if (form === 'none') return;
+  const n = (name: string) => name.normalize(form);
+
+  for (const symbol of extraction.symbols) {
+    symbol.name = n(symbol.name);
+    symbol.qualifiedName = n(symbol.qualifiedName);
+    if (symbol.signature) symbol.signature = n(symbol.signature);
+    const details = symbol.details;
+    if (details?.receiverType) details.receiverType = n(details.receiverType);
+    if (details?.receiver) details.receiver = { ...details.receiver, type: n(details.receiver.type) };
+    if (details?.bodyRefs) details.bodyRefs = Array.from(new Set(details.bodyRefs.map(n))).sort();
+  }
+  for (const call of extraction.calls) {
+    call.callerName = n(call.callerName);
+    call.calleeName = n(call.calleeName);
+  }
+  for (const reference of extraction.references) {
+    reference.name = n(reference.name);
+  }
+  for (const edge of extraction.callEdges ?? []) {
+    edge.callerQualifiedName = n(edge.callerQualifiedName);
+    edge.calleeQualifiedName = n(edge.calleeQualifiedName);
+  }
+  if (extraction.usedNames) {
+    extraction.usedNames = Array.from(new Set(extraction.usedNames.map(n))).sort();
+  }
+  for (const directive of extraction.directives ?? []) {
+    if (directive.symbol) directive.symbol = n(directive.symbol);
+  }
+}
@@ -0,0 +1,203 @@
+/**
+ * File system abstraction the indexer reads source files through
+ *
+ * Paths given to and returned by a SourceFileSystem are relative to the
+ * index root and always use `/` as the separator, whatever the platform.
+ * NodeFileSystem reads the OS file system below rootDir and is the default;
+ * MemoryFileSystem serves files from memory (tests, archives, bundled
+ * sources). Any other store can be indexed by implementing the interface.
+ */
+
+import { lstatSync, readlinkSync, realpathSync, stat as statCallback } from 'fs';
+import { readdir, readFile, stat } from 'fs/promises';
+import { join, posix, relative, sep } from 'path';
+import fg from 'fast-glob';
+import { globToRegExp } from './ignore-patterns.js';
+import type { SkippedSymlink } from '../core/types.js';
+
+export interface SourceFileStats {
+  mtimeMs: number;
+  size: number;
+}
+
+export interface SourceFileSystem {
+  /**
+   * Every file matching one of the include globs and none of the ignore
+   * globs, in any order
+   */
+  glob(patterns: string[], ignore: string[]): Promise<string[]>;
+  readFile(path: string): Promise<string>;
+  /** null when the file does not exist */
+  stat(path: string): Promise<SourceFileStats | null>;
+  /** Names of the files (not directories) directly inside dir ('.' for the root) */
+  readDir(dir: string): Promise<string[]>;
+  /** Symbolic links the last glob did not follow; file systems without links omit this */
+  skippedSymlinks?(): SkippedSymlink[];
+}
+
+/**
+ * The OS file system below rootDir. Symbolic links are not followed unless
+ * followSymlinks is set; then a link to a directory is followed only if no
+ * directory it leads to has been visited already (the tree below rootDir
+ * included), so each real directory is walked at most once and link cycles
+ * end. Links that were not followed are listed by skippedSymlinks.
+ */
+export class NodeFileSystem implements SourceFileSystem {
+  private skipped = new Map<string, SkippedSymlink>();
+
+  constructor(
+    private rootDir: string,
+    private followSymlinks = false
+  ) {}
+
+  async glob(patterns: string[], ignore: string[]): Promise<string[]> {
+    this.skipped.clear();
+    const visited = [realpathSync(this.rootDir)];
+
+    // fast-glob stats a symlink to learn what it points to and treats a link
+    // whose stat fails as broken: it is neither listed as a file nor walked
+    const statLink = (path: string, callback: (error: NodeJS.ErrnoException | null, stats: any) => void): void => {
+      let refusal: SkippedSymlink['reason'] | null = null;
+      try {
+        if (lstatSync(path).isSymbolicLink()) {
+          refusal = this.symlinkRefusal(path, visited);
+        }
+      } catch {
+        // a missing path fails the stat below
+      }
+      if (!refusal) {
+        statCallback(path, callback);
+        return;
+      }
+
+      const relativePath = relative(this.rootDir, path).split(sep).join('/');
+      let target: string;
+      try {
+        target = refusal === 'broken' ? readlinkSync(path) : realpathSync(path);
+      } catch {
+        target = '';
+      }
+      this.skipped.set(relativePath, { path: relativePath, target, reason: refusal });
+      callback(Object.assign(new Error(`Symbolic link not followed: ${relativePath}`), { code: 'ELOOP' }), undefined);
+    };
+
+    return fg(patterns, {
+      cwd: this.rootDir,
+      ignore,
+      onlyFiles: true,
+      followSymbolicLinks: true,
+      fs: { stat: statLink as typeof statCallback },
+    });
+  }
+
+  skippedSymlinks(): SkippedSymlink[] {
+    return Array.from(this.skipped.values()).sort((a, b) => a.path.localeCompare(b.path));
+  }
+
+  /**
+   * Why a symlink must not be followed, or null to follow it. visited holds
+   * the real directories walked so far; a followed directory is added.
+   */
+  private symlinkRefusal(path: string, visited: string[]): SkippedSymlink['reason'] | null {
+    if (!this.followSymlinks) return 'not-followed';
+
+    let target: string;
+    try {
+      target = realpathSync(path);
+    } catch {
+      return 'broken';
+    }
+    if (!lstatSync(target).isDirectory()) return null;
+
+    const within = (dir: string, parent: string) => dir === parent || dir.startsWith(parent.endsWith(sep) ? parent : parent + sep);
+    if (visited.some(dir => within(target, dir) || within(dir, target))) return 'already-visited';
+    visited.push(target);
+    return null;
+  }
+
+  readFile(path: string): Promise<string> {
+    return readFile(join(this.rootDir, path), 'utf-8');
+  }
+
+  async stat(path: string): Promise<SourceFileStats | null> {
+    try {
+      const stats = await stat(join(this.rootDir, path));
+      return stats.isFile() ? { mtimeMs: stats.mtimeMs, size: stats.size } : null;
+    } catch (error) {
+      if ((error as NodeJS.ErrnoException).code === 'ENOENT') return null;
+      throw error;
+    }
+  }
+
+  async readDir(dir: string): Promise<string[]> {
+    const entries = await readdir(join(this.rootDir, dir), { withFileTypes: true });
+    return entries.filter(entry => entry.isFile()).map(entry => entry.name);
+  }
+}
+
+/**
+ * Content of a MemoryFileSystem file; mtimeMs defaults to 0
+ */
+export interface MemoryFile {
+  content: string;
+  mtimeMs?: number;
+}
+
+/**
+ * Files held in memory, keyed by slash-separated path. writeFile and
+ * removeFile let refreshAll see changes. Globs support `*`, `?`, `**` and
+ * character classes but not `{a,b}` alternatives.
+ */
+export class MemoryFileSystem implements SourceFileSystem {
+  private files = new Map<string, Required<MemoryFile>>();
+
+  constructor(files: Record<string, string | MemoryFile> = {}) {
+    for (const [path, file] of Object.entries(files)) {
+      const { content, mtimeMs = 0 } = typeof file === 'string' ? { content: file } : file;
+      this.files.set(normalize(path), { content, mtimeMs });
+    }
+  }
+
+  /**
+   * Add or replace a file; mtimeMs defaults to now so refreshAll picks it up
+   */
+  writeFile(path: string, content: string, mtimeMs = Date.now()): void {
+    this.files.set(normalize(path), { content, mtimeMs });
+  }
+
+  removeFile(path: string): void {
+    this.files.delete(normalize(path));
+  }
+
+  async glob(patterns: string[], ignore: string[]): Promise<string[]> {
+    const included = patterns.map(globToRegExp);
+    const excluded = ignore.map(globToRegExp);
+    return Array.from(this.files.keys()).filter(
+      path => included.some(regex => regex.test(path)) && !excluded.some(regex => regex.test(path))
+    );
+  }
+
+  async readFile(path: string): Promise<string> {
+    const file = this.files.get(normalize(path));
+    if (!file) {
+      throw Object.assign(new Error(`ENOENT: no such file: ${path}`), { code: 'ENOENT' });
+    }
+    return file.content;
+  }
+
+  async stat(path: string): Promise<SourceFileStats | null> {
+    const file = this.files.get(normalize(path));
+    return file ? { mtimeMs: file.mtimeMs, size: Buffer.byteLength(file.content) } : null;
+  }
+
+  async readDir(dir: string): Promise<string[]> {
+    const prefix = normalize(dir);
+    return Array.from(this.files.keys())
+      .filter(path => posix.dirname(path) === prefix)
+      .map(path => posix.basename(path));
+  }
+}
+
+function normalize(path: string): string {
+  return posix.normalize(path.replace(/\\/g, '/')).replace(/^\/+/, '') || '.';
+}
@@ -0,0 +1,71 @@
+/**
+ * Symbol-level differences between two indexing passes of a file
+ */
+
+import type { IndexDelta, SymbolRecord } from '../core/types.js';
+
+export function emptyDelta(): IndexDelta {
+  return { added: [], removed: [], changed: [] };
+}
+
+/**
+ * Key symbols by kind and qualified name. Repeated names (several `init`
+ * functions, say) are told apart by their order of appearance.
+ */
+function keyedSymbols(symbols: SymbolRecord[]): Map<string, SymbolRecord> {
+  const keyed = new Map<string, SymbolRecord>();
+  const seen = new Map<string, number>();
+
+  for (const symbol of symbols) {
+    const base = `${symbol.kind} ${symbol.qualifiedName}`;
+    const occurrence = seen.get(base) ?? 0;
+    seen.set(base, occurrence + 1);
+    keyed.set(`${base}#${occurrence}`, symbol);
+  }
+
+  return keyed;
+}
+
+function sameSymbol(a: SymbolRecord, b: SymbolRecord): boolean {
+  return (
+    a.startLine === b.startLine &&
+    a.startCol === b.startCol &&
+    a.endLine === b.endLine &&
+    a.endCol === b.endCol &&
+    (a.signature ?? '') === (b.signature ?? '') &&
+    Boolean(a.exported) === Boolean(b.exported) &&
+    JSON.stringify(a.details ?? null) === JSON.stringify(b.details ?? null)
+  );
+}
+
+/**
+ * Compare a file's symbols before and after re-indexing. Changed symbols
+ * are reported with their new record.
+ */
+export function diffSymbols(before: SymbolRecord[], after: SymbolRecord[]): IndexDelta {
+  const delta = emptyDelta();
+  const oldSymbols = keyedSymbols(before);
+  const newSymbols = keyedSymbols(after);
+
+  for (const [key, symbol] of newSymbols) {
+    const previous = oldSymbols.get(key);
+    if (!previous) {
+      delta.added.push(symbol);
+    } else if (!sameSymbol(previous, symbol)) {
+      delta.changed.push(symbol);
+    }
+  }
+  for (const [key, symbol] of oldSymbols) {
+    if (!newSymbols.has(key)) {
+      delta.removed.push(symbol);
+    }
+  }
+
+  return delta;
+}
+
+export function mergeDelta(into: IndexDelta, from: IndexDelta): void {
+  into.added.push(...from.added);
+  into.removed.push(...from.removed);
+  into.changed.push(...from.changed);
+}
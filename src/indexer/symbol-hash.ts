@@ -0,0 +1,123 @@
+/**
+ * Position-independent hashes of symbol declarations
+ *
+ * The hash is SHA-256 over a canonical JSON array:
+ *
+ *   [kind, qualifiedName, exported, declaration, members]
+ *
+ * - declaration: for Go, an object holding only the API-relevant details
+ *   keys below, with object keys sorted (the Go signature text includes the
+ *   start of the body, so it is not used); for other languages, the signature
+ *   with runs of whitespace collapsed to one space. Receiver, parameter and
+ *   result names are left out, so renaming them keeps the hash.
+ * - members: hashes of the fields of a struct (or anonymous struct field) and
+ *   the methods of an interface, in declaration order; [] for anything else.
+ *
+ * Line/column positions, doc comments and formatting never take part, so
+ * moving or reformatting a declaration keeps its hash, while changing a
+ * parameter type, a field, a struct tag or a const value changes it.
+ */
+
+import { createHash } from 'crypto';
+import type { SymbolDetails, SymbolRecord } from '../core/types.js';
+
+type HashableSymbol = Pick<SymbolRecord, 'language' | 'kind' | 'qualifiedName' | 'exported' | 'signature' | 'details'>;
+
+const DECLARATION_KEYS: Array<keyof SymbolDetails> = [
+  'typeParams',
+  'receiverType',
+  'isPointerReceiver',
+  'params',
+  'results',
+  'type',
+  'isEmbedded',
+  'tags',
+  'isAlias',
+  'underlying',
+  'embeddedInterfaces',
+  'value',
+];
+
+function canonicalJSON(value: unknown): string {
+  if (Array.isArray(value)) {
+    return `[${value.map(canonicalJSON).join(',')}]`;
+  }
+  if (value && typeof value === 'object') {
+    const entries = Object.entries(value)
+      .filter(([, v]) => v !== undefined)
+      .sort(([a], [b]) => (a < b ? -1 : a > b ? 1 : 0));
+    return `{${entries.map(([k, v]) => `${JSON.stringify(k)}:${canonicalJSON(v)}`).join(',')}}`;
+  }
+  return JSON.stringify(value);
+}
+
+function declarationOf(symbol: HashableSymbol): unknown {
+  if (symbol.language !== 'go') {
+    return (symbol.signature ?? '').replace(/\s+/g, ' ').trim();
+  }
+  const details = symbol.details ?? {};
+  const declaration: Record<string, unknown> = {};
+  for (const key of DECLARATION_KEYS) {
+    if (details[key] !== undefined) declaration[key] = details[key];
+  }
+  // Parameter and result names are not part of the API
+  for (const key of ['params', 'results'] as const) {
+    if (details[key]) declaration[key] = details[key].map(param => ({ ...param, name: undefined }));
+  }
+  return declaration;
+}
+
+/**
+ * Hash one symbol's declaration. memberHashes are the hashes of its fields or
+ * interface methods, in order; a symbol already hashed during indexing
+ * returns its stored hash.
+ */
+export function signatureHash(symbol: HashableSymbol, memberHashes: string[] = []): string {
+  if (symbol.details?.signatureHash && memberHashes.length === 0) {
+    return symbol.details.signatureHash;
+  }
+  const canonical = canonicalJSON([
+    symbol.kind,
+    symbol.qualifiedName,
+    Boolean(symbol.exported),
+    declarationOf(symbol),
+    memberHashes,
+  ]);
+  return createHash('sha256').update(canonical).digest('hex');
+}
+
+/**
+ * Store a signatureHash in the details of every symbol extracted from a
+ * file. Members are hashed before their containers.
+ */
+export function assignSignatureHashes<T extends HashableSymbol>(symbols: T[]): void {
+  const members = new Map<string, T[]>();
+  const containers = new Map<string, T>();
+
+  for (const symbol of symbols) {
+    if (symbol.kind === 'struct' || symbol.kind === 'interface' || symbol.kind === 'field') {
+      containers.set(symbol.qualifiedName, symbol);
+    }
+  }
+  for (const symbol of symbols) {
+    const parentName = symbol.qualifiedName.slice(0, symbol.qualifiedName.lastIndexOf('.'));
+    const parent = containers.get(parentName);
+    const isMember =
+      parent !== undefined &&
+      (symbol.kind === 'field' || (symbol.kind === 'method' && parent.kind === 'interface'));
+    if (isMember) {
+      const siblings = members.get(parentName) ?? [];
+      siblings.push(symbol);
+      members.set(parentName, siblings);
+    }
+  }
+
+  // Deeper qualified names first, so members are done before their parents
+  const depth = (symbol: T) => symbol.qualifiedName.split('.').length;
+  for (const symbol of [...symbols].sort((a, b) => depth(b) - depth(a))) {
+    const memberHashes = (members.get(symbol.qualifiedName) ?? []).map(member => member.details!.signatureHash!);
+    const { signatureHash: _previous, ...details } = symbol.details ?? {};
+    const hash = signatureHash({ ...symbol, details }, memberHashes);
+    symbol.details = { ...details, signatureHash: hash };
+  }
+}
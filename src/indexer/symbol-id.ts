@@ -0,0 +1,70 @@
+/**
+ * Stable symbol IDs that survive re-indexing
+ *
+ * A symbol's ID is the first 16 hex digits of SHA-256 over
+ *
+ *   package directory \0 kind \0 qualifiedName
+ *
+ * where the package directory is the '/'-separated directory of its file
+ * relative to rootDir. Positions take no part, so moving, reformatting or
+ * re-indexing a declaration keeps its ID; renaming it, changing its kind or
+ * moving it to another package gives a new one. Unlike symbolId, which is a
+ * database row id, the ID is the same across runs and machines.
+ *
+ * Collisions between legitimately identical qualified names:
+ * - Go init functions, which a package may declare any number of times, also
+ *   hash the file name, as do symbols nested in them;
+ * - other duplicates within one file (a declaration repeated for different
+ *   build tags in the same file) add their signatureHash, and if that
+ *   matches too, their ordinal in the file (#2, #3, ...);
+ * - the same declaration in different files of a package, as with
+ *   platform-specific files under allPlatforms, shares one ID: each is the
+ *   same symbol built for a different platform.
+ */
+
+import { createHash } from 'crypto';
+import { posix } from 'path';
+import type { SymbolRecord } from '../core/types.js';
+
+type IdentifiableSymbol = Pick<SymbolRecord, 'language' | 'kind' | 'qualifiedName' | 'details'>;
+
+function hashKey(parts: string[]): string {
+  return createHash('sha256').update(parts.join('\0')).digest('hex').slice(0, 16);
+}
+
+function isGoInit(symbol: IdentifiableSymbol): boolean {
+  return symbol.language === 'go' && symbol.kind === 'function' && symbol.qualifiedName.endsWith('.init');
+}
+
+/**
+ * Set details.stableId on every symbol of one file. relativePath is the
+ * file's path relative to rootDir, '/'-separated. Must run after the
+ * signature hashes are assigned.
+ */
+export function assignStableIds(symbols: IdentifiableSymbol[], relativePath: string): void {
+  const dir = posix.dirname(relativePath);
+  const file = posix.basename(relativePath);
+  const keyOf = (symbol: IdentifiableSymbol) => {
+    const parts = [dir, symbol.kind, symbol.qualifiedName];
+    return isGoInit(symbol) ? [...parts, file] : parts;
+  };
+
+  const counts = new Map<string, number>();
+  for (const symbol of symbols) {
+    const key = keyOf(symbol).join('\0');
+    counts.set(key, (counts.get(key) ?? 0) + 1);
+  }
+
+  const seen = new Map<string, number>();
+  for (const symbol of symbols) {
+    let parts = keyOf(symbol);
+    if (counts.get(parts.join('\0'))! > 1) {
+      parts = [...parts, symbol.details?.signatureHash ?? ''];
+      const key = parts.join('\0');
+      const ordinal = (seen.get(key) ?? 0) + 1;
+      seen.set(key, ordinal);
+      if (ordinal > 1) parts = [...parts, `#${ordinal}`];
+    }
+    symbol.details = { ...symbol.details, stableId: hashKey(parts) };
+  }
+}
@@ -0,0 +1,43 @@
+/**
+ * Source text of a symbol, for the includeSource option
+ */
+
+import type { Language, SymbolRecord } from '../core/types.js';
+
+type Span = Pick<SymbolRecord, 'startLine' | 'startCol' | 'endLine' | 'endCol'>;
+
+/**
+ * Whether a line, trimmed, belongs to a comment block above a declaration
+ */
+function isCommentLine(line: string, language: Language): boolean {
+  const trimmed = line.trim();
+  if (language === 'python') return trimmed.startsWith('#');
+  if (language === 'html') return false;
+  return trimmed.startsWith('//') || trimmed.startsWith('/*') || trimmed.startsWith('*');
+}
+
+/**
+ * The exact text between a symbol's start and end positions, tabs and
+ * all. With includeDoc, the comment lines directly above the declaration
+ * (no blank line in between) are included as well, from the start of their
+ * first line.
+ */
+export function symbolSource(lines: string[], span: Span, language: Language, includeDoc = false): string {
+  let startLine = span.startLine;
+  let startCol = span.startCol;
+  if (includeDoc) {
+    while (startLine > 1 && isCommentLine(lines[startLine - 2], language)) {
+      startLine--;
+      startCol = 1;
+    }
+  }
+
+  if (startLine === span.endLine) {
+    return lines[startLine - 1].slice(startCol - 1, span.endCol - 1);
+  }
+  return [
+    lines[startLine - 1].slice(startCol - 1),
+    ...lines.slice(startLine, span.endLine - 1),
+    lines[span.endLine - 1].slice(0, span.endCol - 1),
+  ].join('\n');
+}
@@ -0,0 +1,70 @@
+/**
+ * Every comment of a file with its position, grouped the way go/ast groups
+ * them, for tools that reattach comments after rewriting code
+ */
+
+import type Parser from 'tree-sitter';
+import type { Comment, CommentGroup } from '../core/types.js';
+
+function toComment(node: Parser.SyntaxNode): Comment {
+  return {
+    startLine: node.startPosition.row + 1,
+    startCol: node.startPosition.column + 1,
+    endLine: node.endPosition.row + 1,
+    endCol: node.endPosition.column + 1,
+    text: node.text,
+  };
+}
+
+/**
+ * The comments of a parsed tree in source order, wherever they appear
+ * (function bodies included). A group is a run of comments with no code and
+ * no blank line between them; a comment trailing code on its line forms a
+ * group of its own, like `x := 1 // note`. Doc comments are included too:
+ * the groups are independent of how docs are attached to symbols.
+ */
+export function collectComments(tree: Parser.Tree): CommentGroup[] {
+  const groups: Comment[][] = [];
+  let open: Comment[] | null = null; // group the next comment may join
+  let lastTokenRow = -1; // row the last non-comment token ended on
+
+  const visit = (node: Parser.SyntaxNode): void => {
+    if (node.type === 'comment') {
+      const comment = toComment(node);
+      const trailing = node.startPosition.row === lastTokenRow;
+      const last = open?.[open.length - 1];
+      if (open && last && !trailing && comment.startLine <= last.endLine + 1) {
+        open.push(comment);
+      } else {
+        open = [comment];
+        groups.push(open);
+      }
+      if (trailing) open = null;
+      return;
+    }
+    if (node.childCount === 0) {
+      // Newline tokens that terminate Go statements are not code
+      if (node.text.trim()) {
+        open = null;
+        lastTokenRow = node.endPosition.row;
+      }
+      return;
+    }
+    for (const child of node.children) {
+      visit(child);
+    }
+  };
+  visit(tree.rootNode);
+
+  return groups.map(comments => {
+    const first = comments[0];
+    const last = comments[comments.length - 1];
+    return {
+      startLine: first.startLine,
+      startCol: first.startCol,
+      endLine: last.endLine,
+      endCol: last.endCol,
+      comments,
+    };
+  });
+}
@@ -0,0 +1,59 @@
+/**
+ * Syntax errors tree-sitter recovered from while parsing
+ */
+
+import type Parser from 'tree-sitter';
+import type { ParseError } from '../core/types.js';
+
+// Past this many errors a file is hopeless; the rest add nothing
+const MAX_PARSE_ERRORS = 100;
+
+function snippet(text: string): string {
+  const line = text.split('\n')[0].trim();
+  return line.length > 30 ? `${line.slice(0, 30)}...` : line;
+}
+
+/**
+ * Every syntax error in a parsed tree, in source order: ERROR nodes wrap
+ * text the parser skipped to recover, missing nodes stand for tokens it
+ * assumed. Declarations outside those nodes parsed normally, so a file with
+ * errors still yields symbols for the rest.
+ */
+export function collectParseErrors(tree: Parser.Tree): ParseError[] {
+  const errors: ParseError[] = [];
+
+  const visit = (node: Parser.SyntaxNode): void => {
+    if (errors.length >= MAX_PARSE_ERRORS || !node.hasError) return;
+
+    if (node.type === 'ERROR' || node.isMissing) {
+      errors.push({
+        line: node.startPosition.row + 1,
+        column: node.startPosition.column + 1,
+        message: node.isMissing ? `missing ${node.type}` : `unexpected "${snippet(node.text)}"`,
+      });
+      return;
+    }
+    for (const child of node.children) {
+      visit(child);
+    }
+  };
+  visit(tree.rootNode);
+
+  return errors;
+}
+
+/**
+ * Thrown with strictParse for a file that has syntax errors, instead of
+ * indexing the parts that parsed
+ */
+export class SourceSyntaxError extends Error {
+  constructor(
+    public readonly path: string,
+    public readonly errors: ParseError[]
+  ) {
+    const [first] = errors;
+    const more = errors.length > 1 ? ` (and ${errors.length - 1} more)` : '';
+    super(`${path}:${first.line}:${first.column}: ${first.message}${more}`);
+    this.name = 'SourceSyntaxError';
+  }
+}
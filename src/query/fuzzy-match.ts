@@ -0,0 +1,103 @@
+/**
+ * Subsequence fuzzy matching for "go to symbol" style search
+ */
+
+import type { ScoreConfig, SymbolKind, SymbolRecord } from '../core/types.js';
+
+const MATCH = 16;
+const CONSECUTIVE_BONUS = 12;
+const BOUNDARY_BONUS = 10;
+const FIRST_CHAR_BONUS = 8;
+const CASE_BONUS = 1;
+const GAP_PENALTY = 2;
+const LEADING_GAP_PENALTY = 1;
+
+// Types first, then functions and methods; variables, constants and fields
+// rarely are what "go to symbol" is after
+const DEFAULT_KIND_BOOSTS: Partial<Record<SymbolKind, number>> = {
+  struct: 8,
+  interface: 8,
+  class: 8,
+  type: 8,
+  function: 6,
+  method: 4,
+};
+
+/**
+ * Whether name[index] starts a word: the first character, a character after
+ * a separator, an upper-case letter after a lower-case one ("GetUser" → U),
+ * or the first digit of a number
+ */
+function isWordStart(name: string, index: number): boolean {
+  if (index === 0) return true;
+  const prev = name[index - 1];
+  const ch = name[index];
+  if (!/[A-Za-z0-9]/.test(prev)) return true;
+  if (/[A-Z]/.test(ch) && /[a-z0-9]/.test(prev)) return true;
+  if (/[0-9]/.test(ch) && !/[0-9]/.test(prev)) return true;
+  // last upper-case letter of an acronym followed by a word: "HTTPServer" → S
+  return /[A-Z]/.test(ch) && /[A-Z]/.test(prev) && /[a-z]/.test(name[index + 1] ?? '');
+}
+
+/**
+ * Score how well query matches name as a case-insensitive subsequence, or
+ * null when it does not match. Matches that are consecutive or fall on word
+ * starts score higher, characters skipped between matches cost a little, so
+ * "gus" ranks GetUser above GetStatus. The alignment with the best score is
+ * used.
+ */
+export function fuzzyScore(query: string, name: string): number | null {
+  const q = query.toLowerCase();
+  const n = name.toLowerCase();
+  if (q.length === 0 || q.length > n.length) return null;
+
+  // best[j]: best score with the current query character matched at name[j]
+  let best: Array<number | null> = new Array(n.length).fill(null);
+  for (let j = 0; j < n.length; j++) {
+    if (n[j] !== q[0]) continue;
+    best[j] = charScore(query, name, 0, j) - j * LEADING_GAP_PENALTY;
+  }
+
+  for (let i = 1; i < q.length; i++) {
+    const next: Array<number | null> = new Array(n.length).fill(null);
+    for (let j = i; j < n.length; j++) {
+      if (n[j] !== q[i]) continue;
+      let score: number | null = null;
+      for (let k = i - 1; k < j; k++) {
+        const prev = best[k];
+        if (prev === null) continue;
+        const link = k === j - 1 ? CONSECUTIVE_BONUS : -(j - k - 1) * GAP_PENALTY;
+        if (score === null || prev + link > score) score = prev + link;
+      }
+      if (score !== null) next[j] = score + charScore(query, name, i, j);
+    }
+    best = next;
+  }
+
+  let result: number | null = null;
+  for (const score of best) {
+    if (score !== null && (result === null || score > result)) result = score;
+  }
+  return result;
+}
+
+function charScore(query: string, name: string, queryIndex: number, nameIndex: number): number {
+  let score = MATCH;
+  if (nameIndex === 0) score += FIRST_CHAR_BONUS;
+  if (isWordStart(name, nameIndex)) score += BOUNDARY_BONUS;
+  if (query[queryIndex] === name[nameIndex]) score += CASE_BONUS;
+  return score;
+}
+
+/**
+ * What config adds to (or takes from) a symbol's match score: exported
+ * symbols and types, functions and methods rank above unexported
+ * variables, constants and fields of similar match
+ */
+export function symbolBoost(symbol: SymbolRecord, config: ScoreConfig = {}): number {
+  const kindBoosts = { ...DEFAULT_KIND_BOOSTS, ...config.kindBoosts };
+  let boost = kindBoosts[symbol.kind] ?? 0;
+  if (symbol.exported) boost += config.exportedBoost ?? 10;
+  if (symbol.details?.testScope) boost -= config.testPenalty ?? 0;
+  return boost;
+}
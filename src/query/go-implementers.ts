@@ -0,0 +1,187 @@
+/**
+ * Interface satisfaction between the Go types of one package
+ */
+
+import type { Implementer, SymbolDetails, SymbolRecord } from '../core/types.js';
+import { baseTypeName, membersOf, promotedMembers } from './go-promotion.js';
+
+type MethodSet = Map<string, string>; // method name → signature key
+
+// Methods of the predeclared interfaces an interface may embed
+const PREDECLARED_INTERFACES: Record<string, MethodSet> = {
+  error: new Map([['Error', '() string']]),
+  any: new Map(),
+};
+
+/**
+ * Parameter and result types of a method, without their names, so
+ * `Validate(u User) error` and `Validate(User) error` compare equal. Reads
+ * as Go, since whyNotImplements shows keys in its reasons: '(User) error',
+ * '(string) (int, error)', and '()' for no parameters and no results. Until
+ * whyNotImplements, results were always parenthesized ('() (string)',
+ * '() ()'); keys from before then do not compare equal to these.
+ */
+function signatureKey(details: SymbolDetails | undefined): string {
+  const params = (details?.params ?? []).map(p => `${p.isVariadic ? '...' : ''}${p.type}`);
+  const results = (details?.results ?? []).map(p => p.type);
+  const resultList = results.length === 0 ? '' : results.length === 1 ? ` ${results[0]}` : ` (${results.join(', ')})`;
+  return `(${params.join(', ')})${resultList}`;
+}
+
+/**
+ * Method sets of the types declared in one package, computed from its
+ * symbols. Types outside the package cannot be looked into, so anything
+ * depending on them is unresolvable (null).
+ */
+class PackageMethodSets {
+  private types = new Map<string, SymbolRecord>();
+
+  constructor(private symbols: SymbolRecord[]) {
+    for (const symbol of symbols) {
+      if (symbol.kind === 'struct' || symbol.kind === 'interface' || symbol.kind === 'type') {
+        this.types.set(symbol.name, symbol);
+      }
+    }
+  }
+
+  /**
+   * The methods an interface requires, its embedded interfaces included, or
+   * null when it embeds an interface from another package or a type-set
+   * element that cannot be resolved
+   */
+  interfaceMethods(iface: SymbolRecord, seen = new Set<string>()): MethodSet | null {
+    const methods: MethodSet = new Map();
+    if (seen.has(iface.qualifiedName)) return methods;
+    seen.add(iface.qualifiedName);
+
+    for (const method of membersOf(iface, 'method', this.symbols)) {
+      methods.set(method.name, signatureKey(method.details));
+    }
+    for (const name of iface.details?.embeddedInterfaces ?? []) {
+      const embedded = this.types.get(baseTypeName(name));
+      const embeddedMethods = name in PREDECLARED_INTERFACES
+        ? PREDECLARED_INTERFACES[name]
+        : embedded?.kind === 'interface' && !name.includes('.')
+          ? this.interfaceMethods(embedded, seen)
+          : null;
+      if (!embeddedMethods) return null;
+      for (const [methodName, key] of embeddedMethods) {
+        methods.set(methodName, key);
+      }
+    }
+    return methods;
+  }
+
+  /**
+   * Method set of T (pointer = false) or *T (pointer = true): methods with a
+   * value receiver belong to both, methods with a pointer receiver only to
+   * *T. Methods promoted from embedded fields, as promotedMembers finds
+   * them, follow the same rule, except that a *E embedded anywhere along
+   * the way promotes all of E's methods to T as well.
+   */
+  concreteMethods(type: SymbolRecord, pointer: boolean): MethodSet {
+    const methods: MethodSet = new Map();
+    for (const method of this.symbols) {
+      if (method.kind !== 'method' || method.details?.receiverType !== type.name) continue;
+      if (method.details.isPointerReceiver && !pointer) continue;
+      methods.set(method.name, signatureKey(method.details));
+    }
+
+    for (const { member, via } of promotedMembers(type, this.symbols)) {
+      if (member.kind !== 'method') continue;
+      if (member.details?.isPointerReceiver && !pointer && !this.embedsPointer(type, via)) continue;
+      methods.set(member.name, signatureKey(member.details));
+    }
+    return methods;
+  }
+
+  // Whether one of the embedded fields via names, starting from type, is a pointer *E
+  private embedsPointer(type: SymbolRecord, via: string[]): boolean {
+    let current: SymbolRecord | undefined = type;
+    for (const name of via) {
+      const fieldType: string | undefined = current &&
+        membersOf(current, 'field', this.symbols).find(field => field.name === name)?.details?.type;
+      if (!fieldType) return false;
+      if (fieldType.startsWith('*')) return true;
+      current = this.types.get(baseTypeName(fieldType));
+    }
+    return false;
+  }
+
+  concreteTypes(): SymbolRecord[] {
+    return Array.from(this.types.values()).filter(
+      type => type.kind !== 'interface' && !type.details?.isAlias && type.details?.typeKind !== 'interface'
+    );
+  }
+}
+
+function byName<T>(entries: Iterable<[string, T]>): Array<[string, T]> {
+  return Array.from(entries).sort(([a], [b]) => (a < b ? -1 : a > b ? 1 : 0));
+}
+
+function satisfies(methods: MethodSet, required: MethodSet): boolean {
+  for (const [name, key] of required) {
+    if (methods.get(name) !== key) return false;
+  }
+  return true;
+}
+
+/**
+ * The concrete types of iface's package whose method set satisfies iface,
+ * matching method names and parameter/result types textually. A type whose
+ * value satisfies it is reported with pointer = false; one that needs a
+ * pointer-receiver method, so only *T satisfies it, with pointer = true.
+ * symbols must be all symbols of iface's package. Empty when iface embeds
+ * an interface that cannot be resolved within the package.
+ */
+export function findImplementers(iface: SymbolRecord, symbols: SymbolRecord[]): Implementer[] {
+  const sets = new PackageMethodSets(symbols);
+  const required = sets.interfaceMethods(iface);
+  if (!required) return [];
+
+  const implementers: Implementer[] = [];
+  for (const type of sets.concreteTypes()) {
+    if (satisfies(sets.concreteMethods(type, false), required)) {
+      implementers.push({ type, pointer: false });
+    } else if (satisfies(sets.concreteMethods(type, true), required)) {
+      implementers.push({ type, pointer: true });
+    }
+  }
+  return implementers.sort((a, b) => (a.type.name < b.type.name ? -1 : a.type.name > b.type.name ? 1 : 0));
+}
+
+/**
+ * Why type does not satisfy iface, one human-readable reason per method
+ * that is missing, has a different signature, or needs a pointer receiver
+ * (so only *T satisfies it), e.g. 'missing method Close() error'; empty
+ * when type satisfies iface. Signatures are compared, and written, as
+ * findImplementers compares them (see signatureKey). typeSymbols and
+ * ifaceSymbols are all symbols of the packages declaring type and iface.
+ */
+export function whyNotImplements(
+  type: SymbolRecord,
+  typeSymbols: SymbolRecord[],
+  iface: SymbolRecord,
+  ifaceSymbols: SymbolRecord[]
+): string[] {
+  const required = new PackageMethodSets(ifaceSymbols).interfaceMethods(iface);
+  if (!required) {
+    return [`interface ${iface.name} embeds an interface that cannot be resolved, so its method set is unknown`];
+  }
+
+  const typeSets = new PackageMethodSets(typeSymbols);
+  const valueMethods = typeSets.concreteMethods(type, false);
+  const pointerMethods = typeSets.concreteMethods(type, true);
+  const reasons: string[] = [];
+  for (const [name, wanted] of byName(required)) {
+    const found = pointerMethods.get(name);
+    if (found === undefined) {
+      reasons.push(`missing method ${name}${wanted}`);
+    } else if (found !== wanted) {
+      reasons.push(`method ${name} has signature ${name}${found}, interface wants ${name}${wanted}`);
+    } else if (!valueMethods.has(name)) {
+      reasons.push(`method ${name} has a pointer receiver, so only *${type.name} implements ${iface.name}`);
+    }
+  }
+  return reasons;
+}
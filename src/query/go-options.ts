@@ -0,0 +1,47 @@
+/**
+ * Recognizing the functional options pattern in Go
+ *
+ *   type Option func(*Server)
+ *   func WithTimeout(d time.Duration) Option { return func(s *Server) { s.timeout = d } }
+ *   func NewServer(opts ...Option) *Server
+ *
+ * The pattern is a naming and typing convention, not a language feature, so
+ * it is matched by heuristics:
+ * - the option type is a Go type declaration (named anything; Option and
+ *   XxxOption are common) that is a function type, an interface (the
+ *   `interface{ apply(*Server) }` variant), or an alias of another
+ *   package's type (`type Option = grpc.DialOption`), whose shape is not
+ *   known here; structs and other types are not option types;
+ * - an option constructor is a package-level function, not a method or a
+ *   function literal bound to a local (includeLocals), with exactly one
+ *   result, of exactly the option type as its file spells it:
+ *   `Option` in the type's package, `server.Option` where imported, with any
+ *   type arguments of a generic option type (`Option[T]`). Functions
+ *   also returning an error, and ones returning `*Option` or `[]Option`, are
+ *   left out. The name is not checked: With* is usual, but combinators such
+ *   as `func Chain(opts ...Option) Option` build options too.
+ */
+
+import type { SymbolRecord } from '../core/types.js';
+
+/**
+ * Whether a symbol can be the option type of the pattern
+ */
+export function isOptionType(symbol: SymbolRecord): boolean {
+  if (symbol.language !== 'go' || !['interface', 'type'].includes(symbol.kind)) return false;
+  const details = symbol.details ?? {};
+  const underlying = details.underlying ?? '';
+  return details.typeKind === 'interface' || /^func\s*\(/.test(underlying) ||
+    (Boolean(details.isAlias) && /^[\p{L}_][\p{L}\p{N}_]*\.[\p{L}_][\p{L}\p{N}_]*$/u.test(underlying));
+}
+
+/**
+ * Whether symbol is a function returning only the option type spelled
+ * `spelling`
+ */
+export function isOptionConstructor(symbol: SymbolRecord, spelling: string): boolean {
+  const results = symbol.details?.results ?? [];
+  if (symbol.language !== 'go' || symbol.kind !== 'function' || symbol.details?.scope || results.length !== 1) return false;
+  const type = results[0].type;
+  return type === spelling || type.startsWith(`${spelling}[`);
+}
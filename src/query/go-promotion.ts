@@ -0,0 +1,112 @@
+/**
+ * Fields and methods promoted onto a Go type through embedded fields
+ */
+
+import type { PromotedMember, SymbolRecord } from '../core/types.js';
+
+interface Embedding {
+  type: SymbolRecord;
+  via: string[]; // embedded field names leading to type
+}
+
+// 'Base[T]' → 'Base', '*Base' → 'Base'
+export function baseTypeName(type: string): string {
+  return type.replace(/^\*/, '').replace(/\[.*\]$/, '');
+}
+
+/**
+ * The fields of a struct or the methods of an interface declared directly
+ * in it, i.e. those qualified by its name with no further dot
+ */
+export function membersOf(type: SymbolRecord, kind: 'method' | 'field', symbols: SymbolRecord[]): SymbolRecord[] {
+  const prefix = `${type.qualifiedName}.`;
+  return symbols.filter(
+    member => member.kind === kind && member.qualifiedName.startsWith(prefix) && !member.qualifiedName.slice(prefix.length).includes('.')
+  );
+}
+
+/**
+ * The members a type declares itself: fields (embedded ones included, under
+ * their type's name) and methods for a struct or defined type; for an
+ * interface its methods, with those of the interfaces it embeds
+ */
+function ownMembers(
+  type: SymbolRecord,
+  symbols: SymbolRecord[],
+  types: Map<string, SymbolRecord>,
+  seen = new Set<string>()
+): SymbolRecord[] {
+  if (seen.has(type.qualifiedName)) return [];
+  seen.add(type.qualifiedName);
+
+  if (type.kind === 'interface') {
+    const embedded = (type.details?.embeddedInterfaces ?? [])
+      .filter(name => !name.includes('.'))
+      .map(name => types.get(baseTypeName(name)))
+      .filter((iface): iface is SymbolRecord => iface?.kind === 'interface');
+    return [...membersOf(type, 'method', symbols), ...embedded.flatMap(iface => ownMembers(iface, symbols, types, seen))];
+  }
+  const methods = symbols.filter(symbol => symbol.kind === 'method' && symbol.details?.receiverType === type.name);
+  return [...membersOf(type, 'field', symbols), ...methods];
+}
+
+/**
+ * The fields and methods reachable on type through its embedded fields, by
+ * Go's selector rules: the shallowest member of a name wins, so a member
+ * declared on type itself shadows every promoted one of that name, and a
+ * name found more than once at its shallowest depth is ambiguous and left
+ * out. symbols must be all symbols of type's package; embedded types from
+ * other packages cannot be looked into and contribute nothing. Sorted by
+ * depth, then name.
+ */
+export function promotedMembers(type: SymbolRecord, symbols: SymbolRecord[]): PromotedMember[] {
+  const types = new Map<string, SymbolRecord>();
+  for (const symbol of symbols) {
+    if (symbol.kind === 'struct' || symbol.kind === 'interface' || symbol.kind === 'type') {
+      types.set(symbol.name, symbol);
+    }
+  }
+
+  const promoted: PromotedMember[] = [];
+  const resolved = new Set<string>(); // names settled at a shallower depth
+  const visited = new Set<string>(); // types expanded at a shallower depth
+  let level: Embedding[] = [{ type, via: [] }];
+
+  for (let depth = 0; level.length > 0; depth++) {
+    const found = new Map<string, PromotedMember[]>();
+    const next: Embedding[] = [];
+    const expanded = new Set<string>();
+
+    for (const { type: current, via } of level) {
+      if (visited.has(current.qualifiedName)) continue;
+      expanded.add(current.qualifiedName);
+
+      for (const member of ownMembers(current, symbols, types)) {
+        // An embedded field is looked into even when its own name is shadowed
+        const embeddedType = member.kind === 'field' && member.details?.isEmbedded ? member.details.type : undefined;
+        const embedded = embeddedType && !embeddedType.includes('.') ? types.get(baseTypeName(embeddedType)) : undefined;
+        if (embedded) {
+          next.push({ type: embedded, via: [...via, member.name] });
+        }
+
+        if (resolved.has(member.name)) continue;
+        const candidates = found.get(member.name) ?? [];
+        candidates.push({ member, depth, via });
+        found.set(member.name, candidates);
+      }
+    }
+
+    for (const [name, candidates] of found) {
+      resolved.add(name);
+      if (depth > 0 && candidates.length === 1) {
+        promoted.push(candidates[0]);
+      }
+    }
+    for (const name of expanded) {
+      visited.add(name);
+    }
+    level = next;
+  }
+
+  return promoted.sort((a, b) => a.depth - b.depth || (a.member.name < b.member.name ? -1 : a.member.name > b.member.name ? 1 : 0));
+}
@@ -0,0 +1,66 @@
+/**
+ * Identical anonymous struct types across the index
+ */
+
+import type { AnonymousShape, InlineStruct, SymbolRecord } from '../core/types.js';
+
+/**
+ * The canonical form of an anonymous struct: its fields' names and types in
+ * order, with tags left out, `X, Y int` written as two fields and embedded
+ * fields as their type: 'struct{ Name string; Email string }'
+ */
+export function shapeKey(struct: InlineStruct): string {
+  const fields = struct.fields.map(field => (field.isEmbedded ? field.type : `${field.name} ${field.type}`));
+  return fields.length > 0 ? `struct{ ${fields.join('; ')} }` : 'struct{}';
+}
+
+/**
+ * Every distinct anonymous struct shape among symbols, with where it
+ * occurs: as the type (or element type) of a struct field or variable, of
+ * a function's parameter or result, or nested in another anonymous struct.
+ * A struct field declared directly as `struct{...}` has its nested fields
+ * indexed as symbols of their own, which are visited in turn, so its inline
+ * copy is not descended into. Structs cut off by maxNestedStructDepth are
+ * left out. Sorted by number of occurrences, most first, then shape.
+ */
+export function anonymousShapes(symbols: SymbolRecord[], pathOf: (symbol: SymbolRecord) => string): AnonymousShape[] {
+  const shapes = new Map<string, AnonymousShape>();
+
+  const add = (struct: InlineStruct, symbol: SymbolRecord, descend: boolean, param?: string): void => {
+    if (struct.truncated) return;
+    const shape = shapeKey(struct);
+    const entry = shapes.get(shape) ?? { shape, fields: struct.fields, occurrences: [] };
+    entry.occurrences.push({
+      path: pathOf(symbol),
+      qualifiedName: symbol.qualifiedName,
+      startLine: symbol.startLine,
+      startCol: symbol.startCol,
+      ...(param !== undefined ? { param } : {}),
+    });
+    shapes.set(shape, entry);
+
+    if (!descend) return;
+    for (const field of struct.fields) {
+      if (field.inlineStruct) add(field.inlineStruct, symbol, true, param);
+    }
+  };
+
+  for (const symbol of symbols) {
+    const details = symbol.details;
+    if (!details) continue;
+    if (details.inlineStruct) {
+      const expanded = symbol.kind === 'field' && details.type?.startsWith('struct{');
+      add(details.inlineStruct, symbol, !expanded);
+    }
+    (details.params ?? []).forEach((param, index) => {
+      if (param.inlineStruct) add(param.inlineStruct, symbol, true, param.name || `param ${index}`);
+    });
+    (details.results ?? []).forEach((result, index) => {
+      if (result.inlineStruct) add(result.inlineStruct, symbol, true, result.name || `result ${index}`);
+    });
+  }
+
+  return [...shapes.values()].sort((a, b) =>
+    b.occurrences.length - a.occurrences.length || (a.shape < b.shape ? -1 : a.shape > b.shape ? 1 : 0)
+  );
+}
@@ -0,0 +1,38 @@
+/**
+ * Whether a Go symbol mentions a type in its declaration or body
+ */
+
+import type { SymbolRecord } from '../core/types.js';
+
+// The type texts a symbol's declaration is made of
+function declaredTypes(symbol: SymbolRecord): string[] {
+  const details = symbol.details ?? {};
+  return [
+    details.type,
+    details.underlying,
+    details.receiver?.type,
+    ...(details.params ?? []).map(param => param.type),
+    ...(details.results ?? []).map(result => result.type),
+    ...(details.typeParams ?? []).map(param => param.constraint),
+    ...(details.embeddedInterfaces ?? []),
+  ].filter((text): text is string => Boolean(text));
+}
+
+/**
+ * Whether symbol uses the type spelled `spelling` where it is declared: the
+ * bare name (`User`) in the type's own package, `pkg.User` elsewhere. The
+ * type may appear anywhere in a type text (`map[int]*User`,
+ * `func(User) error`) and counts in the body or initializer when named
+ * there, including as the operand of a method expression (`User.Validate`),
+ * the target of a type assertion (`v.(*User)`) or a type switch case
+ * (`case *User:`).
+ */
+export function usesType(symbol: SymbolRecord, spelling: string): boolean {
+  const escaped = spelling.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+  const mention = new RegExp(`(?<![\\p{L}\\p{N}_.])${escaped}(?![\\p{L}\\p{N}_])`, 'u');
+  if (declaredTypes(symbol).some(text => mention.test(text))) return true;
+
+  const refs = [...(symbol.details?.bodyRefs ?? []), ...(symbol.details?.initRefs ?? [])];
+  const qualified = spelling.includes('.');
+  return refs.some(ref => ref === spelling || (!qualified && ref.startsWith(`${spelling}.`)));
+}
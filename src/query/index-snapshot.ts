@@ -0,0 +1,169 @@
+/**
+ * Frozen, read-only view of the index
+ */
+
+import { CodeDatabase } from '../storage/database.js';
+import { QueryEngine } from './query-engine.js';
+import type {
+  Conflict,
+  FindOptions,
+  FileDirective,
+  FileParseError,
+  CommentGroup,
+  Implementer,
+  PromotedMember,
+  ImportRecord,
+  NormalizeForm,
+  QuerySymbolOptions,
+  ScoredSymbol,
+  ScoreConfig,
+  AnonymousShape,
+  SymbolPage,
+  SymbolRecord,
+} from '../core/types.js';
+
+/**
+ * The index as it was when CodeIndex.snapshot() was called. It is an
+ * in-memory copy, so a reindexAll/refreshAll/updateFile running at the same
+ * time never shows through half-done, and every query on the snapshot sees
+ * the same state. Close it when done to free the memory.
+ */
+export class IndexSnapshot {
+  private queryEngine: QueryEngine;
+
+  constructor(private db: CodeDatabase, normalizeForm?: NormalizeForm, nameForms?: boolean) {
+    this.queryEngine = new QueryEngine(db, normalizeForm, nameForms);
+  }
+
+  lookup(qualifiedName: string): SymbolRecord | null {
+    return this.queryEngine.lookup(qualifiedName);
+  }
+
+  lookupStableId(stableId: string): SymbolRecord | null {
+    return this.queryEngine.lookupStableId(stableId);
+  }
+
+  find(pattern: string, options: FindOptions = {}): SymbolRecord[] {
+    return this.queryEngine.find(pattern, options);
+  }
+
+  prefixSearch(prefix: string, limit: number, options: FindOptions = {}): SymbolRecord[] {
+    return this.queryEngine.prefixSearch(prefix, limit, options);
+  }
+
+  findNormalized(query: string, options: FindOptions = {}): SymbolRecord[] {
+    return this.queryEngine.findNormalized(query, options);
+  }
+
+  findPage(pattern: string, offset: number, limit: number, options: FindOptions = {}): SymbolPage {
+    return this.queryEngine.findPage(pattern, offset, limit, options);
+  }
+
+  fuzzyFind(query: string, limit: number, config: ScoreConfig = {}): ScoredSymbol[] {
+    return this.queryEngine.fuzzyFind(query, limit, config);
+  }
+
+  findSymbol(options: QuerySymbolOptions): SymbolRecord | null {
+    return this.queryEngine.findSymbol(options);
+  }
+
+  findSymbols(options: QuerySymbolOptions): SymbolRecord[] {
+    return this.queryEngine.findSymbols(options);
+  }
+
+  methodsOf(typeName: string): SymbolRecord[] {
+    return this.queryEngine.methodsOf(typeName);
+  }
+
+  implementers(interfaceName: string): Implementer[] {
+    return this.queryEngine.implementers(interfaceName);
+  }
+
+  entrypoints(): SymbolRecord[] {
+    return this.queryEngine.entrypoints();
+  }
+
+  whyNotImplements(typeName: string, interfaceName: string): string[] {
+    return this.queryEngine.whyNotImplements(typeName, interfaceName);
+  }
+
+  complexFunctions(threshold: number): SymbolRecord[] {
+    return this.queryEngine.complexFunctions(threshold);
+  }
+
+  resolveFieldPath(typeName: string, ...path: string[]): SymbolRecord {
+    return this.queryEngine.resolveFieldPath(typeName, path);
+  }
+
+  promotedMembers(typeName: string): PromotedMember[] {
+    return this.queryEngine.promotedMembers(typeName);
+  }
+
+  bodyReferences(qualifiedName: string): SymbolRecord[] {
+    return this.queryEngine.bodyReferences(qualifiedName);
+  }
+
+  callers(qualifiedName: string): SymbolRecord[] {
+    return this.queryEngine.callers(qualifiedName);
+  }
+
+  importsOf(path: string): ImportRecord[] {
+    return this.queryEngine.importsOf(path);
+  }
+
+  directives(name?: string): FileDirective[] {
+    return this.queryEngine.directives(name);
+  }
+
+  parseErrors(path?: string): FileParseError[] {
+    return this.queryEngine.parseErrors(path);
+  }
+
+  usagesOfType(typeName: string): SymbolRecord[] {
+    return this.queryEngine.usagesOfType(typeName);
+  }
+
+  localsOf(name: string): SymbolRecord[] {
+    return this.queryEngine.localsOf(name);
+  }
+
+  optionConstructors(optionType: string): SymbolRecord[] {
+    return this.queryEngine.optionConstructors(optionType);
+  }
+
+  fluentMethods(typeName: string): SymbolRecord[] {
+    return this.queryEngine.fluentMethods(typeName);
+  }
+
+  anonymousShapes(): AnonymousShape[] {
+    return this.queryEngine.anonymousShapes();
+  }
+
+  symbolAt(path: string, line: number, col: number): SymbolRecord | null {
+    return this.queryEngine.symbolAt(path, line, col);
+  }
+
+  orphanMethods(): SymbolRecord[] {
+    return this.queryEngine.orphanMethods();
+  }
+
+  comments(path: string): CommentGroup[] {
+    return this.queryEngine.comments(path);
+  }
+
+  conflicts(): Conflict[] {
+    return this.queryEngine.conflicts();
+  }
+
+  symbolToString(symbol: SymbolRecord): string {
+    return this.queryEngine.symbolToString(symbol);
+  }
+
+  canonicalSignature(symbol: SymbolRecord): string {
+    return this.queryEngine.canonicalSignature(symbol);
+  }
+
+  close(): void {
+    this.db.close();
+  }
+}
@@ -0,0 +1,62 @@
+/**
+ * In-memory name index for prefix search
+ *
+ * The index holds every symbol name as a lower-cased key in one sorted
+ * array: a flattened trie, where the names under a prefix node are one
+ * contiguous run found by binary search. A lookup costs O(log n + k) for k
+ * results, against the full scan a LIKE 'prefix%' query needs when matching
+ * case-insensitively, and the array takes a few dozen bytes per symbol.
+ */
+
+import type { SymbolKind } from '../core/types.js';
+
+export interface PrefixEntry {
+  key: string; // lower-cased name
+  kind: SymbolKind;
+  name: string;
+  qualifiedName: string;
+  symbolId: number;
+}
+
+function compare(a: PrefixEntry, b: PrefixEntry): number {
+  if (a.key !== b.key) return a.key < b.key ? -1 : 1;
+  if (a.name !== b.name) return a.name < b.name ? -1 : 1;
+  if (a.qualifiedName !== b.qualifiedName) return a.qualifiedName < b.qualifiedName ? -1 : 1;
+  return a.symbolId - b.symbolId;
+}
+
+export function prefixKey(name: string): string {
+  return name.toLowerCase();
+}
+
+export class PrefixIndex {
+  private entries: PrefixEntry[];
+
+  constructor(symbols: Array<Omit<PrefixEntry, 'key'>>) {
+    this.entries = symbols.map(symbol => ({ ...symbol, key: prefixKey(symbol.name) })).sort(compare);
+  }
+
+  get size(): number {
+    return this.entries.length;
+  }
+
+  /**
+   * The entries whose name starts with prefix, ignoring case, ordered by
+   * lower-cased name, then name, qualified name and symbol ID. With
+   * caseSensitive, the run is filtered down to exact-case matches.
+   */
+  *search(prefix: string, caseSensitive = false): Generator<PrefixEntry> {
+    const key = prefixKey(prefix);
+    let low = 0;
+    let high = this.entries.length;
+    while (low < high) {
+      const mid = (low + high) >>> 1;
+      if (this.entries[mid].key < key) low = mid + 1;
+      else high = mid;
+    }
+    for (let i = low; i < this.entries.length && this.entries[i].key.startsWith(key); i++) {
+      const entry = this.entries[i];
+      if (!caseSensitive || entry.name.startsWith(prefix)) yield entry;
+    }
+  }
+}
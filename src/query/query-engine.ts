@@ -2,23 +2,81 @@
  * Query engine for code index
  */
 
+import { dirname } from 'path';
 import { CodeDatabase } from '../storage/database.js';
+import { canonicalSignature, symbolToString } from './symbol-string.js';
+import { fuzzyScore, symbolBoost } from './fuzzy-match.js';
+import { findImplementers, whyNotImplements } from './go-implementers.js';
+import { promotedMembers } from './go-promotion.js';
+import { anonymousShapes } from './go-shapes.js';
+import { usesType } from './go-type-usages.js';
+import { isOptionConstructor, isOptionType } from './go-options.js';
+import { PrefixIndex } from './prefix-index.js';
+import { defaultPackageName } from '../extractor/go-type-ref.js';
+import { normalizeName } from '../indexer/normalize-names.js';
+import { snakeCase } from '../indexer/name-case.js';
+import { markOrphanReceivers } from '../indexer/go-receivers.js';
 import type { EmbeddingsGenerator } from '../embeddings/embeddings-generator.js';
 import type {
   QuerySymbolOptions,
+  FindOptions,
+  ScoredSymbol,
+  ScoreConfig,
+  AnonymousShape,
+  SymbolPage,
+  IndexStats,
+  UnusedExportedOptions,
+  ImportRecord,
+  FileDirective,
+  FileParseError,
+  CommentGroup,
+  Conflict,
+  Implementer,
+  PromotedMember,
   CallChainOptions,
   CallNode,
   Location,
   SymbolRecord,
   Language,
   SymbolKind,
+  NormalizeForm,
+  FileRecord,
 } from '../core/types.js';
 
+// Kinds of Go symbols declared at package level, which bodyReferences resolves to
+const PACKAGE_LEVEL_KINDS = new Set<SymbolKind>(['function', 'struct', 'interface', 'type', 'constant', 'variable']);
+
+// The key grouping Go files into packages: the import path recorded when
+// indexing, or directory and package clause for files indexed before it was
+function packageKey(file: FileRecord): string {
+  return file.packagePath ?? `${dirname(file.path)}\0${file.packageName}`;
+}
+
+// Whether an import path names the package of file. Without a module path
+// to build full import paths from, a path ending in the file's directory is
+// taken to name it.
+function importsPackage(importPath: string, file: FileRecord): boolean {
+  const dir = dirname(file.path);
+  if (file.packagePath && file.packagePath !== dir) return importPath === file.packagePath;
+  return importPath === dir || importPath.endsWith(`/${dir}`);
+}
+
 export class QueryEngine {
-  constructor(private db: CodeDatabase) {}
+  private prefixIndex: { token: string; index: PrefixIndex } | null = null;
+
+  /**
+   * normalizeForm must match the one the index was built with: names passed
+   * to queries are normalized the same way as the indexed names. nameForms
+   * tells whether the index was built with the nameForms option.
+   */
+  constructor(private db: CodeDatabase, private normalizeForm: NormalizeForm = 'NFC', private nameForms = false) {}
+
+  private normalize(name: string): string {
+    return normalizeName(name, this.normalizeForm);
+  }
 
   findSymbol(options: QuerySymbolOptions): SymbolRecord | null {
-    const symbols = this.db.findSymbolsByName(options.name, options.language);
+    const symbols = this.db.findSymbolsByName(this.normalize(options.name), options.language);
     
     if (symbols.length === 0) {
       return null;
@@ -48,7 +106,634 @@ export class QueryEngine {
   }
 
   findSymbols(options: QuerySymbolOptions): SymbolRecord[] {
-    return this.db.findSymbolsByName(options.name, options.language);
+    return this.db.findSymbolsByName(this.normalize(options.name), options.language);
+  }
+
+  /**
+   * Exact, case-sensitive lookup by qualified name, e.g. "example.UserService.GetUser",
+   * or by a Go name qualified with the package's import path (see
+   * FileRecord.packagePath), e.g. "github.com/acme/shop/util.Helper", which
+   * tells apart packages sharing a name. Returns the first match, or null if
+   * nothing matches.
+   */
+  lookup(qualifiedName: string): SymbolRecord | null {
+    const name = this.normalize(qualifiedName);
+    const symbols = this.db.findSymbolsByQualifiedName(name);
+    if (symbols.length > 0) return symbols[0];
+
+    // The import path ends at one of the dots after its last slash ("gopkg.in/yaml.v3.Node")
+    for (let dot = name.indexOf('.', name.lastIndexOf('/') + 1); dot > 0; dot = name.indexOf('.', dot + 1)) {
+      const found = this.db.findSymbolsByPackagePath(name.slice(0, dot), name.slice(dot + 1));
+      if (found.length > 0) return found[0];
+    }
+    return null;
+  }
+
+  /**
+   * The symbol with a given details.stableId (see symbol-id.ts), or null
+   */
+  lookupStableId(stableId: string): SymbolRecord | null {
+    return this.db.findSymbolsByStableId(stableId)[0] ?? null;
+  }
+
+  /**
+   * Groups of Go declarations that share a qualified name within one package
+   * directory: a function declared twice, or a type and a function with the
+   * same name. A method never clashes with a package function because its
+   * qualified name includes the receiver type. `init` functions may repeat
+   * and are ignored. Under allPlatforms, declarations guarded by different
+   * build constraints are alternatives rather than conflicts.
+   */
+  conflicts(): Conflict[] {
+    const groups = new Map<string, Conflict>();
+
+    for (const entry of this.db.findDuplicateGoSymbols()) {
+      const { symbol } = entry;
+      if (symbol.kind === 'function' && symbol.name === 'init') continue;
+      if (symbol.details?.scope) continue; // locals of the same name in different blocks
+
+      const dir = dirname(entry.path);
+      const key = `${dir}\0${symbol.qualifiedName}\0${symbol.details?.buildConstraint ?? ''}`;
+      const group = groups.get(key) ?? { qualifiedName: symbol.qualifiedName, dir, symbols: [] };
+      group.symbols.push(entry);
+      groups.set(key, group);
+    }
+
+    return Array.from(groups.values()).filter(group => group.symbols.length > 1);
+  }
+
+  /**
+   * Imports of a file in source order, or an empty list if it isn't indexed
+   */
+  importsOf(path: string): ImportRecord[] {
+    const file = this.db.getFileByPath(path);
+    return file ? this.db.getImportsByFile(file.fileId!) : [];
+  }
+
+  /**
+   * Directive comments (//go:generate, //go:embed, //nolint, ...) across the
+   * index, or only those with the given name, ordered by file and line
+   */
+  directives(name?: string): FileDirective[] {
+    return this.db.findDirectives(name);
+  }
+
+  /**
+   * Syntax errors recorded while indexing, for every file or the one at path
+   */
+  parseErrors(path?: string): FileParseError[] {
+    return this.db.findParseErrors(path);
+  }
+
+  /**
+   * All comments of the file at path, grouped, when indexed with includeComments
+   */
+  comments(path: string): CommentGroup[] {
+    return this.db.findComments(path);
+  }
+
+  /**
+   * Find symbols whose name matches a glob pattern such as "Get*" or "?etUser",
+   * optionally restricted to some kinds. Matching is case-insensitive unless
+   * caseSensitive is set; an empty pattern returns every symbol of the kinds.
+   */
+  find(pattern: string, options: FindOptions = {}): SymbolRecord[] {
+    return this.db.findSymbolsByPattern(this.normalize(pattern), options.kinds, options.caseSensitive);
+  }
+
+  /**
+   * Up to limit symbols whose name starts with prefix, ignoring case unless
+   * options.caseSensitive is set, ordered by lower-cased name, then name and
+   * qualified name. Backed by an in-memory index of all names, built on the
+   * first call and rebuilt after the database changes, so a lookup does not
+   * scan the symbol table.
+   */
+  prefixSearch(prefix: string, limit: number, options: FindOptions = {}): SymbolRecord[] {
+    if (!Number.isInteger(limit) || limit < 0) {
+      throw new RangeError(`Invalid limit: ${limit}`);
+    }
+    const token = this.db.changeToken();
+    if (this.prefixIndex?.token !== token) {
+      this.prefixIndex = { token, index: new PrefixIndex(this.db.getSymbolNames()) };
+    }
+
+    const kinds = options.kinds?.length ? new Set(options.kinds) : null;
+    const results: SymbolRecord[] = [];
+    if (limit === 0) return results;
+    for (const entry of this.prefixIndex.index.search(this.normalize(prefix), options.caseSensitive)) {
+      if (kinds && !kinds.has(entry.kind)) continue;
+      const symbol = this.db.getSymbolById(entry.symbolId);
+      if (symbol) results.push(symbol);
+      if (results.length === limit) break;
+    }
+    return results;
+  }
+
+  /**
+   * Find symbols by the snake_case form of their name, whatever convention
+   * query is written in: 'get_user_by_email', 'get-user-by-email' and
+   * 'GetUserByEmail' all find GetUserByEmail, and 'http_server' finds
+   * HTTPServer. `*` and `?` wildcards work as in find. Throws unless the
+   * index was built with the nameForms option.
+   */
+  findNormalized(query: string, options: FindOptions = {}): SymbolRecord[] {
+    if (!this.nameForms) {
+      throw new Error('findNormalized requires the nameForms option');
+    }
+    return this.db.findSymbolsBySearchName(snakeCase(this.normalize(query)), options.kinds);
+  }
+
+  /**
+   * Page through find's results: up to limit symbols starting at offset, in
+   * find's order (name, then qualified name), with the total match count
+   */
+  findPage(pattern: string, offset: number, limit: number, options: FindOptions = {}): SymbolPage {
+    if (!Number.isInteger(offset) || offset < 0 || !Number.isInteger(limit) || limit < 0) {
+      throw new RangeError(`Invalid page: offset ${offset}, limit ${limit}`);
+    }
+    return this.db.findSymbolsPageByPattern(this.normalize(pattern), options.kinds ?? [], options.caseSensitive ?? false, offset, limit);
+  }
+
+  /**
+   * The limit symbols whose names best match query as a subsequence, best
+   * first: the name's match score (see fuzzyScore) weighted by exported
+   * status, kind and test scope (see ScoreConfig). Ties go to the shorter
+   * name, then alphabetical order. An empty query matches nothing.
+   */
+  fuzzyFind(query: string, limit: number, config: ScoreConfig = {}): ScoredSymbol[] {
+    if (!query || limit <= 0) return [];
+
+    const normalized = this.normalize(query);
+    const scored: ScoredSymbol[] = [];
+    for (const symbol of this.db.findSymbolsByPattern('')) {
+      const matchScore = fuzzyScore(normalized, symbol.name);
+      if (matchScore !== null) scored.push({ symbol, score: matchScore + symbolBoost(symbol, config), matchScore });
+    }
+    scored.sort((a, b) =>
+      b.score - a.score ||
+      a.symbol.name.length - b.symbol.name.length ||
+      (a.symbol.name < b.symbol.name ? -1 : a.symbol.name > b.symbol.name ? 1 : 0)
+    );
+    return scored.slice(0, limit);
+  }
+
+  /**
+   * Methods declared directly on a type, for value and pointer receivers
+   * alike. Accepts a bare type name ("UserService") or a package-qualified
+   * one ("example.UserService"). Promoted methods of embedded fields are not
+   * included.
+   */
+  methodsOf(name: string): SymbolRecord[] {
+    const typeName = this.normalize(name);
+    const baseName = typeName.slice(typeName.lastIndexOf('.') + 1);
+    const methods = this.db.findMethodsByReceiver(baseName);
+    if (!typeName.includes('.')) {
+      return methods;
+    }
+    return methods.filter(method => method.qualifiedName === `${typeName}.${method.name}`);
+  }
+
+  /**
+   * The local symbols (see IndexOptions.includeLocals) directly inside a
+   * function, method or named function literal, in source order. Accepts a
+   * qualified name ("example.UserService.GetUser"), or a bare name, which
+   * covers every function or method of that name.
+   */
+  localsOf(name: string): SymbolRecord[] {
+    const normalized = this.normalize(name);
+    const scopes = (normalized.includes('.') ? this.db.findSymbolsByQualifiedName(normalized) : this.db.findSymbolsByName(normalized))
+      .filter(symbol => symbol.kind === 'function' || symbol.kind === 'method');
+    return scopes.flatMap(scope =>
+      this.db
+        .getSymbolsInFile(scope.fileId)
+        .filter(symbol => symbol.details?.scope === scope.qualifiedName &&
+          symbol.startLine >= scope.startLine && symbol.endLine <= scope.endLine)
+        .sort((a, b) => a.startLine - b.startLine || a.startCol - b.startCol)
+    );
+  }
+
+  /**
+   * Builder-style methods of a type (see methodsOf for the name forms): those
+   * with a single result of exactly their receiver's type, so calls can be
+   * chained, e.g. `func (b *Builder) Name(n string) *Builder`. A pointer
+   * receiver must return the pointer and a value receiver the value.
+   */
+  fluentMethods(name: string): SymbolRecord[] {
+    return this.methodsOf(name).filter(method => {
+      const receiver = method.details?.receiver;
+      const results = method.details?.results ?? [];
+      return receiver !== undefined && results.length === 1 &&
+        results[0].type === `${receiver.isPointer ? '*' : ''}${receiver.type}`;
+    });
+  }
+
+  /**
+   * Concrete types whose method set satisfies a Go interface, looked for in
+   * the interface's own package (same directory and package clause). Accepts
+   * a bare interface name ("Validator"), which searches every interface with
+   * that name, or a package-qualified one ("example.Validator"). See
+   * findImplementers for how methods are matched.
+   */
+  implementers(interfaceName: string): Implementer[] {
+    const name = this.normalize(interfaceName);
+    const interfaces = (name.includes('.') ? this.db.findSymbolsByQualifiedName(name) : this.db.findSymbolsByName(name, 'go'))
+      .filter(symbol => symbol.kind === 'interface' && symbol.language === 'go');
+    if (interfaces.length === 0) return [];
+
+    const files = this.db.getAllFiles();
+    const implementers: Implementer[] = [];
+    for (const iface of interfaces) {
+      const packageSymbols = this.packageSymbolsOf(iface, files);
+      if (!packageSymbols) continue;
+      implementers.push(...findImplementers(iface, packageSymbols));
+    }
+    return implementers;
+  }
+
+  /**
+   * Where Go programs start: the main function of every `package main` and
+   * the init functions of every package, ordered by file and line
+   */
+  entrypoints(): SymbolRecord[] {
+    const files = new Map(this.db.getAllFiles().map(file => [file.fileId, file]));
+    const pathOf = (symbol: SymbolRecord) => files.get(symbol.fileId)?.path ?? '';
+    const mains = this.db.findSymbolsByName('main', 'go')
+      .filter(symbol => symbol.kind === 'function' && !symbol.details?.scope &&
+        files.get(symbol.fileId)?.packageName === 'main');
+    // Locals (includeLocals) such as `init := func() {...}` are not entrypoints
+    const inits = this.db.findSymbolsByName('init', 'go').filter(symbol => symbol.kind === 'function' && !symbol.details?.scope);
+    return [...mains, ...inits].sort((a, b) =>
+      (pathOf(a) < pathOf(b) ? -1 : pathOf(a) > pathOf(b) ? 1 : 0) || a.startLine - b.startLine
+    );
+  }
+
+  /**
+   * Each distinct anonymous struct shape in the index with every place it is
+   * written (see anonymousShapes), most frequent first: shapes repeated
+   * often are candidates for a named type
+   */
+  anonymousShapes(): AnonymousShape[] {
+    const files = new Map(this.db.getAllFiles().map(file => [file.fileId, file.path]));
+    const symbols = this.db.getAllSymbols().filter(symbol => symbol.language === 'go');
+    return anonymousShapes(symbols, symbol => files.get(symbol.fileId) ?? '');
+  }
+
+  /**
+   * The innermost symbol whose range contains line:col of the file at path
+   * (relative to rootDir): inside a function body, the function; inside a
+   * struct field, the field rather than its struct. Null when the position
+   * is outside every symbol.
+   */
+  symbolAt(path: string, line: number, col: number): SymbolRecord | null {
+    return this.db.findInnermostSymbolAt(path, line, col) ?? null;
+  }
+
+  /**
+   * Go methods whose receiver type is not declared anywhere in their package
+   * (same directory and package clause), with details.orphanReceiver set,
+   * sorted by qualified name. The type is looked for across all the
+   * package's files, so file and declaration order do not matter.
+   */
+  orphanMethods(): SymbolRecord[] {
+    const packages = new Map<string, FileRecord[]>();
+    for (const file of this.db.getAllFiles()) {
+      if (file.language !== 'go') continue;
+      const key = packageKey(file);
+      packages.set(key, [...(packages.get(key) ?? []), file]);
+    }
+
+    const orphans: SymbolRecord[] = [];
+    for (const files of packages.values()) {
+      orphans.push(...markOrphanReceivers(files.flatMap(file => this.db.getSymbolsInFile(file.fileId!))));
+    }
+    return orphans.sort((a, b) => (a.qualifiedName < b.qualifiedName ? -1 : a.qualifiedName > b.qualifiedName ? 1 : 0));
+  }
+
+  /**
+   * Reasons a Go type does not satisfy an interface, such as "missing method
+   * Validate() error" or a method whose signature differs; empty when it
+   * does. Both names may be bare or package-qualified; with several matches
+   * the first one is used. See whyNotImplements.
+   */
+  whyNotImplements(typeName: string, interfaceName: string): string[] {
+    const findGo = (name: string) =>
+      (name.includes('.') ? this.db.findSymbolsByQualifiedName(name) : this.db.findSymbolsByName(name, 'go'))
+        .filter(symbol => symbol.language === 'go');
+    const type = findGo(this.normalize(typeName)).find(symbol => symbol.kind === 'struct' || symbol.kind === 'type');
+    const iface = findGo(this.normalize(interfaceName)).find(symbol => symbol.kind === 'interface');
+    if (!type) return [`no Go type named ${typeName}`];
+    if (!iface) return [`no Go interface named ${interfaceName}`];
+
+    const files = this.db.getAllFiles();
+    const typeSymbols = this.packageSymbolsOf(type, files);
+    const ifaceSymbols = this.packageSymbolsOf(iface, files);
+    if (!typeSymbols || !ifaceSymbols) return [];
+    return whyNotImplements(type, typeSymbols, iface, ifaceSymbols);
+  }
+
+  /**
+   * Functions and methods whose cyclomatic complexity (recorded with the
+   * computeComplexity option) is at least threshold, most complex first
+   */
+  complexFunctions(threshold: number): SymbolRecord[] {
+    return this.db.getAllSymbols()
+      .filter(symbol => symbol.details?.cyclomatic !== undefined && symbol.details.cyclomatic >= threshold)
+      .sort((a, b) =>
+        b.details!.cyclomatic! - a.details!.cyclomatic! ||
+        (a.qualifiedName < b.qualifiedName ? -1 : a.qualifiedName > b.qualifiedName ? 1 : 0)
+      );
+  }
+
+  /**
+   * Fields and methods a Go type gets from its embedded fields, following
+   * Go's shadowing rules (see promotedMembers). Accepts a bare type name,
+   * which combines every type with that name, or a package-qualified one
+   * ("example.Employee"). Embedded types must be in the same package.
+   */
+  promotedMembers(typeName: string): PromotedMember[] {
+    const name = this.normalize(typeName);
+    const types = (name.includes('.') ? this.db.findSymbolsByQualifiedName(name) : this.db.findSymbolsByName(name, 'go'))
+      .filter(symbol => (symbol.kind === 'struct' || symbol.kind === 'type') && symbol.language === 'go');
+    if (types.length === 0) return [];
+
+    const files = this.db.getAllFiles();
+    const members: PromotedMember[] = [];
+    for (const type of types) {
+      const packageSymbols = this.packageSymbolsOf(type, files);
+      if (!packageSymbols) continue;
+      members.push(...promotedMembers(type, packageSymbols));
+    }
+    return members;
+  }
+
+  /**
+   * Resolve a path of field names, such as ['ContactInfo', 'EmergencyContact',
+   * 'Address', 'Street'] or the dotted 'ContactInfo.EmergencyContact.Address.Street',
+   * starting at a Go struct type, and return the leaf field (its type is in
+   * details.type). Each step may enter an anonymous nested struct, the named
+   * struct type a field refers to (through pointers, slices and arrays, and
+   * into imported packages that are indexed), or a field promoted from an
+   * embedded struct. Throws an error naming the segment that failed.
+   */
+  resolveFieldPath(typeName: string, path: string[]): SymbolRecord {
+    const name = this.normalize(typeName);
+    const root = (name.includes('.') ? this.db.findSymbolsByQualifiedName(name) : this.db.findSymbolsByName(name, 'go'))
+      .find(symbol => symbol.kind === 'struct' && symbol.language === 'go');
+    if (!root) {
+      throw new Error(`No Go struct type named ${typeName}`);
+    }
+    const segments = path.flatMap(part => this.normalize(part).split('.')).filter(Boolean);
+    if (segments.length === 0) {
+      throw new Error(`Empty field path for ${typeName}`);
+    }
+
+    let container = root; // struct type, or field holding an anonymous struct, whose fields are searched
+    let walked = root.name;
+    for (const [i, segment] of segments.entries()) {
+      const field =
+        this.db.findSymbolsByQualifiedName(`${container.qualifiedName}.${segment}`)
+          .find(symbol => symbol.kind === 'field' && symbol.fileId === container.fileId) ??
+        (container.kind === 'struct'
+          ? this.promotedMembers(container.qualifiedName).find(({ member }) => member.kind === 'field' && member.name === segment)?.member
+          : undefined);
+      if (!field) {
+        throw new Error(`Cannot resolve "${segment}" in ${walked}: no such field`);
+      }
+      walked = `${walked}.${segment}`;
+      if (i === segments.length - 1) {
+        return field;
+      }
+
+      const hasNestedFields = this.db.getSymbolsInFile(field.fileId)
+        .some(symbol => symbol.kind === 'field' && symbol.qualifiedName.startsWith(`${field.qualifiedName}.`));
+      const next = hasNestedFields ? field : this.namedStructOf(field);
+      if (!next && field.details?.truncated) {
+        throw new Error(`Cannot resolve "${segments[i + 1]}" in ${walked}: nesting beyond maxNestedStructDepth is not indexed`);
+      }
+      if (!next) {
+        throw new Error(
+          `Cannot resolve "${segments[i + 1]}" in ${walked}: its type ${field.details?.type ?? '(unknown)'} is not an indexed struct`
+        );
+      }
+      container = next;
+    }
+    throw new Error(`Empty field path for ${typeName}`); // unreachable: the loop returns at the last segment
+  }
+
+  /**
+   * The struct type symbol a field's type refers to (details.typeRef), found
+   * in the field's own package or in the indexed package of its import path
+   */
+  private namedStructOf(field: SymbolRecord): SymbolRecord | null {
+    const ref = field.details?.typeRef;
+    if (!ref) return null;
+
+    const files = this.db.getAllFiles().filter(f => f.language === 'go');
+    const file = files.find(f => f.fileId === field.fileId);
+    if (!file) return null;
+    const importPath = ref.importPath;
+    const packageFiles = ref.isLocal
+      ? files.filter(f => packageKey(f) === packageKey(file))
+      : importPath
+        ? files.filter(f => importsPackage(importPath, f))
+        : [];
+    if (packageFiles.length === 0) return null;
+
+    const fileIds = new Set(packageFiles.map(f => f.fileId));
+    return this.db.findSymbolsByQualifiedName(`${packageFiles[0].packageName}.${ref.name}`)
+      .find(symbol => symbol.kind === 'struct' && fileIds.has(symbol.fileId)) ?? null;
+  }
+
+  /**
+   * All symbols of the Go package a symbol belongs to: the files in its
+   * directory with the same package clause. Null if its file is gone.
+   */
+  private packageSymbolsOf(symbol: SymbolRecord, files: FileRecord[]): SymbolRecord[] | null {
+    const file = files.find(f => f.fileId === symbol.fileId);
+    if (!file) return null;
+    return files
+      .filter(f => f.language === 'go' && packageKey(f) === packageKey(file))
+      .flatMap(f => this.db.getSymbolsInFile(f.fileId!));
+  }
+
+  /**
+   * The package-level declarations a Go function or method uses in its body
+   * (see bodyRefs): functions it calls, types it mentions, variables and
+   * constants it reads. Plain names are looked up in the function's own
+   * package; `pkg.Name` in the indexed package whose directory ends the
+   * import path of pkg. Sorted by qualified name, without the function itself.
+   */
+  bodyReferences(qualifiedName: string): SymbolRecord[] {
+    const fn = this.lookup(qualifiedName);
+    const refs = fn?.details?.bodyRefs;
+    if (!fn || !refs) return [];
+
+    const files = this.db.getAllFiles().filter(f => f.language === 'go');
+    const file = files.find(f => f.fileId === fn.fileId);
+    if (!file) return [];
+    const imports = this.db.getImportsByFile(file.fileId!);
+
+    const declarationsIn = (packageFiles: FileRecord[], name: string): SymbolRecord[] => {
+      const fileIds = new Set(packageFiles.map(f => f.fileId));
+      return packageFiles.length === 0 ? [] : this.db
+        .findSymbolsByQualifiedName(`${packageFiles[0].packageName}.${name}`)
+        .filter(symbol => fileIds.has(symbol.fileId) && PACKAGE_LEVEL_KINDS.has(symbol.kind));
+    };
+    const ownPackage = files.filter(f => packageKey(f) === packageKey(file));
+
+    const found = new Map<number, SymbolRecord>();
+    for (const ref of refs) {
+      const dot = ref.indexOf('.');
+      const qualifier = dot < 0 ? undefined : ref.slice(0, dot);
+      const name = ref.slice(dot + 1);
+      const imp = qualifier === undefined ? undefined : imports.find(i =>
+        i.alias ? i.alias === qualifier : defaultPackageName(i.path) === qualifier
+      );
+      const symbols = imp
+        ? declarationsIn(files.filter(f => importsPackage(imp.path, f)), name)
+        : declarationsIn(ownPackage, qualifier ?? name); // `GlobalService.Start()` reads GlobalService
+      for (const symbol of symbols) {
+        if (symbol.symbolId !== fn.symbolId) found.set(symbol.symbolId!, symbol);
+      }
+    }
+    return Array.from(found.values()).sort((a, b) =>
+      a.qualifiedName < b.qualifiedName ? -1 : a.qualifiedName > b.qualifiedName ? 1 : 0
+    );
+  }
+
+  /**
+   * Every Go symbol that uses a type, for impact analysis: functions and
+   * methods naming it in their signature (receiver included) or body, type
+   * assertions (`v.(*User)`) and type switch cases included, also inside
+   * function literals, which count for the enclosing function; fields,
+   * variables and constants of a type built from it (`map[int]*User`),
+   * interface methods and types defined in terms of it.
+   * Other packages are searched through their imports of the type's package,
+   * where it is spelled `pkg.User`. Accepts a bare type name, which covers
+   * every type of that name, or a package-qualified one. Deduplicated and
+   * sorted by qualified name, without the type itself or locals
+   * (includeLocals), whose uses count for their enclosing function.
+   */
+  usagesOfType(typeName: string): SymbolRecord[] {
+    const name = this.normalize(typeName);
+    const types = (name.includes('.') ? this.db.findSymbolsByQualifiedName(name) : this.db.findSymbolsByName(name, 'go'))
+      .filter(symbol => symbol.language === 'go' && ['struct', 'interface', 'type'].includes(symbol.kind));
+    const files = this.db.getAllFiles().filter(f => f.language === 'go');
+
+    const found = new Map<number, SymbolRecord>();
+    for (const type of types) {
+      for (const [fileId, spelling] of this.typeSpellings(type, files)) {
+        for (const symbol of this.db.getSymbolsInFile(fileId)) {
+          if (symbol.symbolId !== type.symbolId && !symbol.details?.scope && usesType(symbol, spelling)) {
+            found.set(symbol.symbolId!, symbol);
+          }
+        }
+      }
+    }
+    return Array.from(found.values()).sort((a, b) =>
+      a.qualifiedName < b.qualifiedName ? -1 : a.qualifiedName > b.qualifiedName ? 1 : 0
+    );
+  }
+
+  /**
+   * Functions that build values of a functional-options type, such as
+   * `func WithTimeout(d time.Duration) Option`, for documenting how a
+   * constructor can be configured. Accepts "Option" or "server.Option". See
+   * go-options.ts for the heuristic, which recognizes a convention rather
+   * than a language feature. Sorted by qualified name.
+   */
+  optionConstructors(optionType: string): SymbolRecord[] {
+    const name = this.normalize(optionType);
+    const types = (name.includes('.') ? this.db.findSymbolsByQualifiedName(name) : this.db.findSymbolsByName(name, 'go'))
+      .filter(isOptionType);
+    const files = this.db.getAllFiles().filter(f => f.language === 'go');
+
+    const found = new Map<number, SymbolRecord>();
+    for (const type of types) {
+      for (const [fileId, spelling] of this.typeSpellings(type, files)) {
+        for (const symbol of this.db.getSymbolsInFile(fileId)) {
+          if (isOptionConstructor(symbol, spelling)) found.set(symbol.symbolId!, symbol);
+        }
+      }
+    }
+    return Array.from(found.values()).sort((a, b) =>
+      a.qualifiedName < b.qualifiedName ? -1 : a.qualifiedName > b.qualifiedName ? 1 : 0
+    );
+  }
+
+  /**
+   * How each Go file able to name a type spells it, by file ID: the bare
+   * name in the type's own package, `pkg.Name` in files importing that
+   * package under any name but _ or .
+   */
+  private typeSpellings(type: SymbolRecord, files: FileRecord[]): Map<number, string> {
+    const spellings = new Map<number, string>();
+    const typeFile = files.find(f => f.fileId === type.fileId);
+    if (!typeFile) return spellings;
+
+    for (const file of files) {
+      if (packageKey(file) === packageKey(typeFile)) {
+        spellings.set(file.fileId!, type.name);
+        continue;
+      }
+      const imp = this.db.getImportsByFile(file.fileId!).find(i => importsPackage(i.path, typeFile));
+      const qualifier = imp && (imp.alias ?? defaultPackageName(imp.path));
+      if (qualifier && qualifier !== '_' && qualifier !== '.') spellings.set(file.fileId!, `${qualifier}.${type.name}`);
+    }
+    return spellings;
+  }
+
+  /**
+   * Exported Go symbols that no indexed file uses. Use is matched by name:
+   * a call, a value or type mention, a selected field or method, or a
+   * composite literal key anywhere except the symbol's own declaration.
+   * Only the indexed files are considered, so symbols used by importers
+   * outside the index are reported too; and a name used for one symbol
+   * counts for every symbol with that name. Functions go test runs
+   * (details.testKind) are never reported.
+   */
+  unusedExported(options: UnusedExportedOptions = {}): SymbolRecord[] {
+    const kinds: SymbolKind[] = options.kinds ?? ['function', 'method', 'struct', 'interface', 'type', 'constant', 'variable', 'field'];
+    return this.db
+      .findUnusedExportedGoSymbols(kinds)
+      .filter(({ path }) => options.includeTestFiles || !path.endsWith('_test.go'))
+      .filter(({ symbol }) => !symbol.details?.testKind)
+      .map(({ symbol }) => symbol);
+  }
+
+  /**
+   * Functions and methods that call the given qualified name, deduplicated.
+   * Edges come from statically resolvable call sites only (see
+   * GoExtractor.extractCallEdges): calls through interface values or function
+   * variables are missed.
+   */
+  callers(qualifiedName: string): SymbolRecord[] {
+    return this.db.findCallers(this.normalize(qualifiedName));
+  }
+
+  /**
+   * A symbol's declaration rebuilt from the index; struct fields and
+   * interface methods are looked up in the symbol's file
+   */
+  symbolToString(symbol: SymbolRecord): string {
+    return symbolToString(symbol, this.membersOf(symbol));
+  }
+
+  /**
+   * symbolToString without receiver, parameter and result names (see
+   * canonicalSignature), for comparing declarations across versions
+   */
+  canonicalSignature(symbol: SymbolRecord): string {
+    return canonicalSignature(symbol, this.membersOf(symbol));
+  }
+
+  // Direct members of a symbol (struct fields, interface methods) in source order
+  private membersOf(symbol: SymbolRecord): SymbolRecord[] {
+    const prefix = `${symbol.qualifiedName}.`;
+    return this.db
+      .getSymbolsInFile(symbol.fileId)
+      .filter(member => member.qualifiedName.startsWith(prefix) && !member.qualifiedName.slice(prefix.length).includes('.'))
+      .sort((a, b) => a.startLine - b.startLine || a.startCol - b.startCol);
   }
 
   getDefinition(symbolId: number): Location | null {
@@ -138,17 +823,18 @@ export class QueryEngine {
 
   getObjectProperties(objectName: string, language?: string): SymbolRecord[] {
     // Find the class/interface/struct
-    const symbols = this.db.findSymbolsByName(objectName, language);
+    const symbols = this.db.findSymbolsByName(this.normalize(objectName), language);
     
     if (symbols.length === 0) {
       return [];
     }
 
-    // Try to find a class, interface, or struct
+    // Try to find a class, interface, or struct (or a Go defined type, which can have methods too)
     const classSymbol = symbols.find(s => 
       s.kind === 'class' || 
       s.kind === 'interface' ||
-      s.kind === 'struct'
+      s.kind === 'struct' ||
+      (s.kind === 'type' && s.language === 'go')
     );
     
     if (!classSymbol) {
@@ -199,20 +885,53 @@ export class QueryEngine {
     return byQualifiedName;
   }
 
-  // Statistics
-  getStats(): {
-    totalFiles: number;
-    totalSymbols: number;
-    totalCalls: number;
-    totalReferences: number;
-  } {
-    // This would require additional queries, simplified for MVP
-    return {
-      totalFiles: 0,
-      totalSymbols: 0,
-      totalCalls: 0,
-      totalReferences: 0,
+  /**
+   * Counts of files and symbols for an overview of the index
+   */
+  getStats(): IndexStats {
+    const stats: IndexStats = {
+      files: this.db.countFiles(),
+      symbols: 0,
+      byKind: {},
+      exported: 0,
+      unexported: 0,
+      linesCovered: 0,
+      maxStructDepth: 0,
     };
+
+    for (const { kind, exported, count } of this.db.countSymbolsByKind()) {
+      stats.symbols += count;
+      stats.byKind[kind] = (stats.byKind[kind] ?? 0) + count;
+      if (exported) {
+        stats.exported += count;
+      } else {
+        stats.unexported += count;
+      }
+    }
+
+    // Union of line ranges per file, so a method inside a class counts once
+    let fileId = -1;
+    let coveredTo = 0;
+    for (const range of this.db.getSymbolLineRanges()) {
+      if (range.fileId !== fileId) {
+        fileId = range.fileId;
+        coveredTo = 0;
+      }
+      const from = Math.max(range.startLine, coveredTo + 1);
+      if (range.endLine >= from) {
+        stats.linesCovered += range.endLine - from + 1;
+        coveredTo = range.endLine;
+      }
+    }
+
+    // pkg.Struct.Field is depth 0; each anonymous struct field adds a level
+    for (const field of this.db.findSymbolsByPattern('', ['field'])) {
+      if (field.language === 'go') {
+        stats.maxStructDepth = Math.max(stats.maxStructDepth, field.qualifiedName.split('.').length - 3);
+      }
+    }
+
+    return stats;
   }
 
   /**
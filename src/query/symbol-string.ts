@@ -0,0 +1,114 @@
+/**
+ * Readable declarations rebuilt from indexed symbol data
+ */
+
+import type { Param, SymbolRecord, TypeParam } from '../core/types.js';
+
+type DescribedSymbol = Pick<SymbolRecord, 'language' | 'kind' | 'name' | 'qualifiedName' | 'signature' | 'details'>;
+
+/**
+ * Render a symbol the way it would be declared, e.g.
+ * `func (s *UserService) GetUser(id int) (*User, error)`. Go declarations are
+ * rebuilt from the structured details rather than the source text; structs
+ * and interfaces span several lines, one per member, and members must be
+ * passed in (the fields of a struct, the methods of an interface). Symbols of
+ * other languages fall back to their signature.
+ */
+export function symbolToString(symbol: DescribedSymbol, members: DescribedSymbol[] = []): string {
+  return declarationString(symbol, members, true);
+}
+
+/**
+ * symbolToString without the names that are not part of a Go function's
+ * API: receiver variables, parameters and results are reduced to their
+ * types, so `func (s *Service) Get(id int) (u *User, err error)` becomes
+ * `func (*Service) Get(int) (*User, error)` and renaming any of them leaves
+ * it unchanged. Applies to the methods of an interface too; field names,
+ * type parameters and declarations of other kinds and languages are kept
+ * as symbolToString renders them.
+ */
+export function canonicalSignature(symbol: DescribedSymbol, members: DescribedSymbol[] = []): string {
+  return declarationString(symbol, members, false);
+}
+
+// named: whether receiver, parameter and result names are rendered
+function declarationString(symbol: DescribedSymbol, members: DescribedSymbol[], named: boolean): string {
+  if (symbol.language !== 'go') {
+    return symbol.signature?.replace(/\s+/g, ' ').trim() || `${symbol.kind} ${symbol.qualifiedName}`;
+  }
+
+  const details = symbol.details ?? {};
+  const name = `${symbol.name}${typeParamsString(details.typeParams)}`;
+
+  switch (symbol.kind) {
+    case 'function':
+    case 'method': {
+      const receiver = details.receiver;
+      const receiverText = receiver
+        ? `(${receiver.name && named ? `${receiver.name} ` : ''}${receiver.isPointer ? '*' : ''}${receiver.type}) `
+        : '';
+      // Interface methods have neither receiver nor `func` keyword
+      const keyword = symbol.kind === 'method' && !receiver ? '' : 'func ';
+      return `${keyword}${receiverText}${name}${funcSignature(details.params, details.results, named)}`;
+    }
+
+    case 'struct': {
+      const fields = members.filter(member => member.kind === 'field').map(fieldString);
+      return fields.length > 0 ? `type ${name} struct {\n${indent(fields)}\n}` : `type ${name} struct{}`;
+    }
+
+    case 'interface': {
+      const elements = [
+        ...(details.embeddedInterfaces ?? []),
+        ...members.filter(member => member.kind === 'method').map(member => declarationString(member, [], named)),
+      ];
+      return elements.length > 0 ? `type ${name} interface {\n${indent(elements)}\n}` : `type ${name} interface{}`;
+    }
+
+    case 'type':
+      return `type ${name}${details.isAlias ? ' =' : ''} ${details.underlying ?? ''}`.trimEnd();
+
+    case 'field':
+      return fieldString(symbol);
+
+    case 'constant':
+    case 'variable': {
+      const keyword = symbol.kind === 'constant' ? 'const' : 'var';
+      const type = details.type && !details.typeInferred ? ` ${details.type}` : '';
+      const value = details.value !== undefined ? ` = ${details.value}` : '';
+      return `${keyword} ${symbol.name}${type}${value}`;
+    }
+
+    default:
+      return `${symbol.kind} ${symbol.qualifiedName}`;
+  }
+}
+
+function typeParamsString(typeParams: TypeParam[] | undefined): string {
+  if (!typeParams || typeParams.length === 0) return '';
+  return `[${typeParams.map(param => `${param.name} ${param.constraint}`.trimEnd()).join(', ')}]`;
+}
+
+function paramString(param: Param, named: boolean): string {
+  const type = `${param.isVariadic ? '...' : ''}${param.type}`;
+  return param.name && named ? `${param.name} ${type}` : type;
+}
+
+function funcSignature(params: Param[] = [], results: Param[] = [], named: boolean): string {
+  const paramList = `(${params.map(param => paramString(param, named)).join(', ')})`;
+  if (results.length === 0) return paramList;
+  if (results.length === 1 && (!results[0].name || !named)) return `${paramList} ${results[0].type}`;
+  return `${paramList} (${results.map(result => paramString(result, named)).join(', ')})`;
+}
+
+function fieldString(field: DescribedSymbol): string {
+  const details = field.details ?? {};
+  const type = details.type ?? '';
+  const declaration = details.isEmbedded ? type : `${field.name} ${type}`.trimEnd();
+  const tags = Object.entries(details.tags ?? {}).map(([key, value]) => `${key}:${JSON.stringify(value)}`);
+  return tags.length > 0 ? `${declaration} \`${tags.join(' ')}\`` : declaration;
+}
+
+function indent(lines: string[]): string {
+  return lines.map(line => `\t${line.replace(/\n/g, '\n\t')}`).join('\n');
+}
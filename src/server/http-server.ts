@@ -0,0 +1,135 @@
+/**
+ * Read-only JSON API over an index
+ *
+ *   GET /symbols?name=&kind=&offset=&limit=   find (name is a glob, kind may repeat)
+ *   GET /symbols/{qualifiedName}              lookup; 404 when unknown
+ *   GET /symbol-at?path=&line=&col=           innermost symbol at a position; 404 outside any
+ *   GET /stats                                index statistics
+ *
+ * Every response allows cross-origin requests, so browser tools can call the
+ * API directly. The handlers only call CodeIndex query methods.
+ */
+
+import { createServer } from 'http';
+import type { IncomingMessage, Server, ServerResponse } from 'http';
+import type { CodeIndex } from '../index.js';
+import type { SymbolKind } from '../core/types.js';
+
+const DEFAULT_PAGE_SIZE = 100;
+
+const CORS_HEADERS = {
+  'Access-Control-Allow-Origin': '*',
+  'Access-Control-Allow-Methods': 'GET, OPTIONS',
+  'Access-Control-Allow-Headers': 'Content-Type',
+};
+
+export interface ServeOptions {
+  port: number;
+  host?: string; // 默认 127.0.0.1，只接受本机请求
+}
+
+class HttpError extends Error {
+  constructor(public readonly status: number, message: string) {
+    super(message);
+  }
+}
+
+function sendJSON(res: ServerResponse, status: number, body: unknown): void {
+  res.writeHead(status, { ...CORS_HEADERS, 'Content-Type': 'application/json; charset=utf-8' });
+  res.end(JSON.stringify(body));
+}
+
+function intParam(url: URL, name: string, fallback: number): number {
+  const raw = url.searchParams.get(name);
+  if (raw === null) return fallback;
+  const value = Number(raw);
+  if (!Number.isInteger(value) || value < 0) {
+    throw new HttpError(400, `${name} must be a non-negative integer`);
+  }
+  return value;
+}
+
+// A malformed request URL or %-escape is the client's mistake, not the server's
+function parseURL(raw: string): URL {
+  try {
+    return new URL(raw, 'http://localhost');
+  } catch {
+    throw new HttpError(400, `Malformed URL: ${raw}`);
+  }
+}
+
+function decodeSegment(segment: string): string {
+  try {
+    return decodeURIComponent(segment);
+  } catch {
+    throw new HttpError(400, `Malformed path segment: ${segment}`);
+  }
+}
+
+async function route(index: CodeIndex, req: IncomingMessage): Promise<unknown> {
+  const url = parseURL(req.url ?? '/');
+  const path = url.pathname.replace(/\/+$/, '');
+
+  if (path === '/stats') {
+    return index.stats();
+  }
+  if (path === '/symbols') {
+    const kinds = url.searchParams.getAll('kind') as SymbolKind[];
+    const offset = intParam(url, 'offset', 0);
+    const limit = intParam(url, 'limit', DEFAULT_PAGE_SIZE);
+    return index.findPage(url.searchParams.get('name') ?? '', offset, limit, { kinds });
+  }
+  if (path === '/symbol-at') {
+    const file = url.searchParams.get('path') ?? '';
+    const line = intParam(url, 'line', 1);
+    const col = intParam(url, 'col', 1);
+    const symbol = await index.symbolAt(file, line, col);
+    if (!symbol) {
+      throw new HttpError(404, `No symbol at ${file}:${line}:${col}`);
+    }
+    return symbol;
+  }
+  if (path.startsWith('/symbols/')) {
+    const qualifiedName = decodeSegment(path.slice('/symbols/'.length));
+    const symbol = await index.lookup(qualifiedName);
+    if (!symbol) {
+      throw new HttpError(404, `Symbol not found: ${qualifiedName}`);
+    }
+    return symbol;
+  }
+  throw new HttpError(404, `Not found: ${url.pathname}`);
+}
+
+/**
+ * Create an HTTP server answering queries against index and start listening.
+ * Resolves once the server is listening; close it with server.close().
+ */
+export function serve(index: CodeIndex, options: ServeOptions): Promise<Server> {
+  const server = createServer((req, res) => {
+    if (req.method === 'OPTIONS') {
+      res.writeHead(204, CORS_HEADERS);
+      res.end();
+      return;
+    }
+    if (req.method !== 'GET') {
+      sendJSON(res, 405, { error: `Method not allowed: ${req.method}` });
+      return;
+    }
+
+    route(index, req).then(
+      body => sendJSON(res, 200, body),
+      error => {
+        const status = error instanceof HttpError ? error.status : 500;
+        sendJSON(res, status, { error: error instanceof Error ? error.message : String(error) });
+      }
+    );
+  });
+
+  return new Promise((resolve, reject) => {
+    server.once('error', reject);
+    server.listen(options.port, options.host ?? '127.0.0.1', () => {
+      server.off('error', reject);
+      resolve(server);
+    });
+  });
+}
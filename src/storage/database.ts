@@ -9,24 +9,93 @@ import type {
   FileRecord,
   SymbolRecord,
   CallRecord,
+  CallEdgeRecord,
   ReferenceRecord,
+  ImportRecord,
+  Directive,
+  FileDirective,
+  FileParseError,
+  ParseError,
+  Comment,
+  CommentGroup,
   Location,
+  SymbolKind,
 } from '../core/types.js';
 
+type SymbolRow = Omit<SymbolRecord, 'details'> & { details: string | null };
+type FileRow = Omit<FileRecord, 'isGenerated' | 'isTest' | 'isExternalTest'> & {
+  isGenerated: number;
+  isTest: number;
+  isExternalTest: number;
+};
+
+function toFileRecord(row: FileRow): FileRecord {
+  return { ...row, isGenerated: row.isGenerated === 1, isTest: row.isTest === 1, isExternalTest: row.isExternalTest === 1 };
+}
+
+// Tables that hang off files and symbols, with the columns importFrom must
+// point at the copied rows; a row referring to a file or symbol that was
+// not copied is dropped
+const DEPENDENT_TABLES: Array<{ table: string; id?: string; fileColumns: string[]; symbolColumns: string[] }> = [
+  { table: 'calls', id: 'call_id', fileColumns: ['site_file_id'], symbolColumns: ['caller_symbol_id', 'callee_symbol_id'] },
+  { table: 'call_edges', id: 'edge_id', fileColumns: ['site_file_id'], symbolColumns: ['caller_symbol_id'] },
+  { table: 'symbol_references', id: 'ref_id', fileColumns: ['from_file_id'], symbolColumns: ['to_symbol_id'] },
+  { table: 'name_uses', fileColumns: ['file_id'], symbolColumns: [] },
+  { table: 'file_imports', id: 'import_id', fileColumns: ['file_id'], symbolColumns: [] },
+  { table: 'file_directives', id: 'directive_id', fileColumns: ['file_id'], symbolColumns: [] },
+  { table: 'parse_errors', fileColumns: ['file_id'], symbolColumns: [] },
+  { table: 'file_comments', fileColumns: ['file_id'], symbolColumns: [] },
+  { table: 'symbol_embeddings', fileColumns: [], symbolColumns: ['symbol_id'] },
+];
+
 export class CodeDatabase {
   private db: Database.Database;
 
-  constructor(dbPath: string) {
+  /**
+   * Open (or create) the database at dbPath. Passing the bytes of a
+   * serialized database instead opens a private in-memory copy of it.
+   */
+  constructor(source: string | Buffer) {
+    if (typeof source !== 'string') {
+      // An image of a WAL database carries file format version 2 at header
+      // bytes 18-19, which an in-memory database cannot open; mark it rollback-journal
+      const image = Buffer.from(source);
+      image[18] = 1;
+      image[19] = 1;
+      this.db = new Database(image);
+      this.init();
+      return;
+    }
+
     // Ensure directory exists
-    const dir = dirname(dbPath);
+    const dir = dirname(source);
     mkdirSync(dir, { recursive: true });
     
-    this.db = new Database(dbPath);
+    this.db = new Database(source);
     this.db.pragma('journal_mode = WAL');
     this.db.pragma('synchronous = NORMAL');
+    this.init();
+  }
+
+  // Set-up shared by on-disk databases and in-memory copies
+  private init(): void {
+    // SQLite's lower() only folds ASCII; identifiers such as Café need full Unicode case folding
+    this.db.function('unicode_lower', { deterministic: true }, (text: unknown) =>
+      typeof text === 'string' ? text.toLowerCase() : text
+    );
     this.initSchema();
   }
 
+  /**
+   * A read-only, in-memory copy of the database as of now. Later writes to
+   * this database do not show up in the copy, and the copy cannot be written.
+   */
+  snapshot(): CodeDatabase {
+    const copy = new CodeDatabase(this.serialize());
+    copy.db.pragma('query_only = ON');
+    return copy;
+  }
+
   private initSchema() {
     this.db.exec(`
       CREATE TABLE IF NOT EXISTS files (
@@ -36,6 +105,11 @@ export class CodeDatabase {
         content_hash TEXT NOT NULL,
         mtime INTEGER NOT NULL,
         size INTEGER NOT NULL,
+        package_name TEXT,
+        is_generated INTEGER NOT NULL DEFAULT 0,
+        is_test INTEGER NOT NULL DEFAULT 0,
+        is_external_test INTEGER NOT NULL DEFAULT 0,
+        package_path TEXT,
         indexed_at INTEGER DEFAULT (strftime('%s', 'now'))
       );
 
@@ -59,6 +133,9 @@ export class CodeDatabase {
         chunk_summary TEXT,
         summary_tokens INTEGER,
         summarized_at INTEGER,
+        details TEXT,
+        receiver_type TEXT,
+        search_name TEXT,
         FOREIGN KEY (file_id) REFERENCES files(file_id) ON DELETE CASCADE
       );
 
@@ -84,6 +161,22 @@ export class CodeDatabase {
       CREATE INDEX IF NOT EXISTS idx_calls_caller ON calls(caller_symbol_id);
       CREATE INDEX IF NOT EXISTS idx_calls_callee ON calls(callee_symbol_id);
 
+      CREATE TABLE IF NOT EXISTS call_edges (
+        edge_id INTEGER PRIMARY KEY AUTOINCREMENT,
+        caller_symbol_id INTEGER NOT NULL,
+        callee_qualified_name TEXT NOT NULL,
+        site_file_id INTEGER NOT NULL,
+        site_start_line INTEGER NOT NULL,
+        site_start_col INTEGER NOT NULL,
+        site_end_line INTEGER NOT NULL,
+        site_end_col INTEGER NOT NULL,
+        FOREIGN KEY (caller_symbol_id) REFERENCES symbols(symbol_id) ON DELETE CASCADE,
+        FOREIGN KEY (site_file_id) REFERENCES files(file_id) ON DELETE CASCADE
+      );
+
+      CREATE INDEX IF NOT EXISTS idx_call_edges_callee ON call_edges(callee_qualified_name);
+      CREATE INDEX IF NOT EXISTS idx_call_edges_file ON call_edges(site_file_id);
+
       CREATE TABLE IF NOT EXISTS symbol_references (
         ref_id INTEGER PRIMARY KEY AUTOINCREMENT,
         from_file_id INTEGER NOT NULL,
@@ -97,6 +190,15 @@ export class CodeDatabase {
         FOREIGN KEY (to_symbol_id) REFERENCES symbols(symbol_id) ON DELETE CASCADE
       );
 
+      CREATE TABLE IF NOT EXISTS name_uses (
+        file_id INTEGER NOT NULL,
+        name TEXT NOT NULL,
+        PRIMARY KEY (file_id, name),
+        FOREIGN KEY (file_id) REFERENCES files(file_id) ON DELETE CASCADE
+      );
+
+      CREATE INDEX IF NOT EXISTS idx_name_uses_name ON name_uses(name);
+
       CREATE INDEX IF NOT EXISTS idx_refs_symbol ON symbol_references(to_symbol_id);
       CREATE INDEX IF NOT EXISTS idx_refs_file ON symbol_references(from_file_id);
 
@@ -114,13 +216,87 @@ export class CodeDatabase {
 
       CREATE INDEX IF NOT EXISTS idx_emb_model ON symbol_embeddings(model);
       CREATE INDEX IF NOT EXISTS idx_emb_chunk_hash ON symbol_embeddings(chunk_hash);
+
+      CREATE TABLE IF NOT EXISTS file_imports (
+        import_id INTEGER PRIMARY KEY AUTOINCREMENT,
+        file_id INTEGER NOT NULL,
+        path TEXT NOT NULL,
+        alias TEXT,
+        FOREIGN KEY (file_id) REFERENCES files(file_id) ON DELETE CASCADE
+      );
+
+      CREATE INDEX IF NOT EXISTS idx_imports_file ON file_imports(file_id);
+      CREATE INDEX IF NOT EXISTS idx_imports_path ON file_imports(path);
+
+      CREATE TABLE IF NOT EXISTS file_directives (
+        directive_id INTEGER PRIMARY KEY AUTOINCREMENT,
+        file_id INTEGER NOT NULL,
+        name TEXT NOT NULL,
+        args TEXT NOT NULL,
+        line INTEGER NOT NULL,
+        symbol_qualified_name TEXT,
+        FOREIGN KEY (file_id) REFERENCES files(file_id) ON DELETE CASCADE
+      );
+
+      CREATE INDEX IF NOT EXISTS idx_directives_file ON file_directives(file_id);
+      CREATE INDEX IF NOT EXISTS idx_directives_name ON file_directives(name);
+
+      CREATE TABLE IF NOT EXISTS parse_errors (
+        file_id INTEGER NOT NULL,
+        line INTEGER NOT NULL,
+        col INTEGER NOT NULL,
+        message TEXT NOT NULL,
+        FOREIGN KEY (file_id) REFERENCES files(file_id) ON DELETE CASCADE
+      );
+
+      CREATE INDEX IF NOT EXISTS idx_parse_errors_file ON parse_errors(file_id);
+
+      CREATE TABLE IF NOT EXISTS file_comments (
+        file_id INTEGER NOT NULL,
+        group_no INTEGER NOT NULL,
+        start_line INTEGER NOT NULL,
+        start_col INTEGER NOT NULL,
+        end_line INTEGER NOT NULL,
+        end_col INTEGER NOT NULL,
+        text TEXT NOT NULL,
+        FOREIGN KEY (file_id) REFERENCES files(file_id) ON DELETE CASCADE
+      );
+
+      CREATE INDEX IF NOT EXISTS idx_file_comments_file ON file_comments(file_id);
     `);
 
     // Ensure new columns exist on existing databases (migration-safe)
-    this.ensureSummaryColumns();
+    this.ensureSymbolColumns();
+    this.ensureFileColumns();
+
+    // Indexes on migrated columns can only be created once the columns exist
+    this.db.exec('CREATE INDEX IF NOT EXISTS idx_symbols_receiver ON symbols(receiver_type)');
+    this.db.exec('CREATE INDEX IF NOT EXISTS idx_symbols_search_name ON symbols(search_name)');
+    this.db.exec('CREATE INDEX IF NOT EXISTS idx_files_package_path ON files(package_path)');
+  }
+
+  private ensureFileColumns(): void {
+    const columns = this.db.prepare("PRAGMA table_info(files)").all() as Array<{ name: string }>;
+    const columnNames = new Set(columns.map(c => c.name));
+
+    if (!columnNames.has('package_name')) {
+      this.db.exec('ALTER TABLE files ADD COLUMN package_name TEXT');
+    }
+    if (!columnNames.has('is_generated')) {
+      this.db.exec('ALTER TABLE files ADD COLUMN is_generated INTEGER NOT NULL DEFAULT 0');
+    }
+    if (!columnNames.has('is_test')) {
+      this.db.exec('ALTER TABLE files ADD COLUMN is_test INTEGER NOT NULL DEFAULT 0');
+    }
+    if (!columnNames.has('is_external_test')) {
+      this.db.exec('ALTER TABLE files ADD COLUMN is_external_test INTEGER NOT NULL DEFAULT 0');
+    }
+    if (!columnNames.has('package_path')) {
+      this.db.exec('ALTER TABLE files ADD COLUMN package_path TEXT');
+    }
   }
 
-  private ensureSummaryColumns(): void {
+  private ensureSymbolColumns(): void {
     const columns = this.db.prepare("PRAGMA table_info(symbols)").all() as Array<{ name: string }>;
     const columnNames = new Set(columns.map(c => c.name));
 
@@ -137,6 +313,15 @@ export class CodeDatabase {
     if (!columnNames.has('summarized_at')) {
       alterStatements.push('ALTER TABLE symbols ADD COLUMN summarized_at INTEGER');
     }
+    if (!columnNames.has('details')) {
+      alterStatements.push('ALTER TABLE symbols ADD COLUMN details TEXT');
+    }
+    if (!columnNames.has('receiver_type')) {
+      alterStatements.push('ALTER TABLE symbols ADD COLUMN receiver_type TEXT');
+    }
+    if (!columnNames.has('search_name')) {
+      alterStatements.push('ALTER TABLE symbols ADD COLUMN search_name TEXT');
+    }
 
     if (alterStatements.length > 0) {
       this.db.transaction(() => {
@@ -150,47 +335,118 @@ export class CodeDatabase {
   // File operations
   insertFile(file: FileRecord): number {
     const stmt = this.db.prepare(`
-      INSERT INTO files (path, language, content_hash, mtime, size)
-      VALUES (?, ?, ?, ?, ?)
+      INSERT INTO files (path, language, content_hash, mtime, size, package_name, is_generated, is_test, is_external_test, package_path)
+      VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
       ON CONFLICT(path) DO UPDATE SET
         content_hash = excluded.content_hash,
         mtime = excluded.mtime,
         size = excluded.size,
+        package_name = excluded.package_name,
+        is_generated = excluded.is_generated,
+        is_test = excluded.is_test,
+        is_external_test = excluded.is_external_test,
+        package_path = excluded.package_path,
         indexed_at = strftime('%s', 'now')
       RETURNING file_id
     `);
-    const result = stmt.get(file.path, file.language, file.contentHash, file.mtime, file.size) as { file_id: number };
+    const result = stmt.get(
+      file.path,
+      file.language,
+      file.contentHash,
+      file.mtime,
+      file.size,
+      file.packageName || null,
+      file.isGenerated ? 1 : 0,
+      file.isTest ? 1 : 0,
+      file.isExternalTest ? 1 : 0,
+      file.packagePath || null
+    ) as { file_id: number };
     return result.file_id;
   }
 
   getFileByPath(path: string): FileRecord | undefined {
     const stmt = this.db.prepare(`
-      SELECT file_id as fileId, path, language, content_hash as contentHash, mtime, size
+      SELECT file_id as fileId, path, language, content_hash as contentHash, mtime, size,
+             package_name as packageName, is_generated as isGenerated,
+             is_test as isTest, is_external_test as isExternalTest,
+             package_path as packagePath
       FROM files WHERE path = ?
     `);
-    return stmt.get(path) as FileRecord | undefined;
+    const row = stmt.get(path) as FileRow | undefined;
+    return row && toFileRecord(row);
+  }
+
+  updateFileMtime(fileId: number, mtime: number): void {
+    this.db.prepare('UPDATE files SET mtime = ? WHERE file_id = ?').run(mtime, fileId);
   }
 
   deleteFile(fileId: number): void {
-    this.db.prepare('DELETE FROM files WHERE file_id = ?').run(fileId);
+    // foreign_keys is off, so ON DELETE CASCADE never fires; remove dependents explicitly
+    this.db.transaction(() => {
+      this.deleteCallsByFile(fileId);
+      this.deleteCallEdgesByFile(fileId);
+      this.deleteReferencesByFile(fileId);
+      this.deleteNameUsesByFile(fileId);
+      this.deleteImportsByFile(fileId);
+      this.deleteDirectivesByFile(fileId);
+      this.deleteParseErrorsByFile(fileId);
+      this.deleteCommentsByFile(fileId);
+      this.deleteSymbolsByFile(fileId);
+      this.db.prepare('DELETE FROM files WHERE file_id = ?').run(fileId);
+    })();
   }
 
   getAllFiles(): FileRecord[] {
     const stmt = this.db.prepare(`
-      SELECT file_id as fileId, path, language, content_hash as contentHash, mtime, size
+      SELECT file_id as fileId, path, language, content_hash as contentHash, mtime, size,
+             package_name as packageName, is_generated as isGenerated,
+             is_test as isTest, is_external_test as isExternalTest,
+             package_path as packagePath
       FROM files
     `);
-    return stmt.all() as FileRecord[];
+    return (stmt.all() as FileRow[]).map(toFileRecord);
+  }
+
+  countFiles(): number {
+    const row = this.db.prepare('SELECT COUNT(*) as count FROM files').get() as { count: number };
+    return row.count;
   }
 
   // Symbol operations
-  insertSymbol(symbol: SymbolRecord): number {
+  /**
+   * Number of symbols per kind, split into exported and unexported
+   */
+  countSymbolsByKind(): Array<{ kind: SymbolKind; exported: boolean; count: number }> {
+    const rows = this.db.prepare(`
+      SELECT kind, exported, COUNT(*) as count
+      FROM symbols
+      GROUP BY kind, exported
+    `).all() as Array<{ kind: SymbolKind; exported: number; count: number }>;
+    return rows.map(row => ({ ...row, exported: row.exported === 1 }));
+  }
+
+  /**
+   * Line range of every symbol, ordered by file then start line
+   */
+  getSymbolLineRanges(): Array<{ fileId: number; startLine: number; endLine: number }> {
+    return this.db.prepare(`
+      SELECT file_id as fileId, start_line as startLine, end_line as endLine
+      FROM symbols
+      ORDER BY file_id, start_line
+    `).all() as Array<{ fileId: number; startLine: number; endLine: number }>;
+  }
+
+  /**
+   * searchName is the snake_case form findSymbolsBySearchName matches; only
+   * stored with the nameForms option
+   */
+  insertSymbol(symbol: SymbolRecord, searchName?: string): number {
     const stmt = this.db.prepare(`
       INSERT INTO symbols (
         file_id, language, kind, name, qualified_name,
         start_line, start_col, end_line, end_col, signature, exported,
-        chunk_hash, chunk_summary, summary_tokens, summarized_at
-      ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        chunk_hash, chunk_summary, summary_tokens, summarized_at, details, receiver_type, search_name
+      ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
     `);
     const result = stmt.run(
       symbol.fileId,
@@ -207,7 +463,10 @@ export class CodeDatabase {
       symbol.chunkHash || null,
       symbol.chunkSummary || null,
       symbol.summaryTokens || null,
-      symbol.summarizedAt || null
+      symbol.summarizedAt || null,
+      symbol.details ? JSON.stringify(symbol.details) : null,
+      symbol.details?.receiverType || null,
+      searchName ?? null
     );
     return result.lastInsertRowid as number;
   }
@@ -219,7 +478,7 @@ export class CodeDatabase {
              start_col as startCol, end_line as endLine, end_col as endCol,
              signature, exported, chunk_hash as chunkHash,
              chunk_summary as chunkSummary, summary_tokens as summaryTokens,
-             summarized_at as summarizedAt
+             summarized_at as summarizedAt, details
       FROM symbols WHERE name = ?
     `;
     const params: any[] = [name];
@@ -230,7 +489,193 @@ export class CodeDatabase {
     }
 
     const stmt = this.db.prepare(query);
-    return stmt.all(...params) as SymbolRecord[];
+    return (stmt.all(...params) as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  findSymbolsByQualifiedName(qualifiedName: string): SymbolRecord[] {
+    const stmt = this.db.prepare(`
+      SELECT symbol_id as symbolId, file_id as fileId, language, kind, name,
+             qualified_name as qualifiedName, start_line as startLine,
+             start_col as startCol, end_line as endLine, end_col as endCol,
+             signature, exported, chunk_hash as chunkHash,
+             chunk_summary as chunkSummary, summary_tokens as summaryTokens,
+             summarized_at as summarizedAt, details
+      FROM symbols WHERE qualified_name = ?
+      ORDER BY symbol_id
+    `);
+    return (stmt.all(qualifiedName) as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  /**
+   * Symbols of the Go package whose files have package_path packagePath,
+   * with qualifiedName the package name followed by name:
+   * ('example.com/m/util', 'Helper') finds util.Helper of that package only
+   */
+  findSymbolsByPackagePath(packagePath: string, name: string): SymbolRecord[] {
+    const stmt = this.db.prepare(`
+      SELECT s.symbol_id as symbolId, s.file_id as fileId, s.language, s.kind, s.name,
+             s.qualified_name as qualifiedName, s.start_line as startLine,
+             s.start_col as startCol, s.end_line as endLine, s.end_col as endCol,
+             s.signature, s.exported, s.chunk_hash as chunkHash,
+             s.chunk_summary as chunkSummary, s.summary_tokens as summaryTokens,
+             s.summarized_at as summarizedAt, s.details
+      FROM symbols s
+      JOIN files f ON f.file_id = s.file_id
+      WHERE f.package_path = ? AND s.qualified_name = f.package_name || '.' || ?
+      ORDER BY s.symbol_id
+    `);
+    return (stmt.all(packagePath, name) as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  /**
+   * Symbols whose details.stableId is stableId; several only for the same
+   * declaration in different files of a package (see symbol-id.ts)
+   */
+  findSymbolsByStableId(stableId: string): SymbolRecord[] {
+    const stmt = this.db.prepare(`
+      SELECT symbol_id as symbolId, file_id as fileId, language, kind, name,
+             qualified_name as qualifiedName, start_line as startLine,
+             start_col as startCol, end_line as endLine, end_col as endCol,
+             signature, exported, chunk_hash as chunkHash,
+             chunk_summary as chunkSummary, summary_tokens as summaryTokens,
+             summarized_at as summarizedAt, details
+      FROM symbols WHERE json_extract(details, '$.stableId') = ?
+      ORDER BY symbol_id
+    `);
+    return (stmt.all(stableId) as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  /**
+   * Match symbol names against a glob pattern (`*` and `?` wildcards).
+   * An empty pattern matches every name.
+   */
+  findSymbolsByPattern(pattern: string, kinds: SymbolKind[] = [], caseSensitive = false): SymbolRecord[] {
+    const { where, params } = this.patternFilter(pattern, kinds, caseSensitive);
+    const stmt = this.db.prepare(`
+      SELECT symbol_id as symbolId, file_id as fileId, language, kind, name,
+             qualified_name as qualifiedName, start_line as startLine,
+             start_col as startCol, end_line as endLine, end_col as endCol,
+             signature, exported, chunk_hash as chunkHash,
+             chunk_summary as chunkSummary, summary_tokens as summaryTokens,
+             summarized_at as summarizedAt, details
+      FROM symbols WHERE ${where}
+      ORDER BY name, qualified_name, symbol_id
+    `);
+    return (stmt.all(...params) as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  /**
+   * One page of findSymbolsByPattern's results, in the same order, plus the
+   * number of matches across all pages. symbol_id breaks ties, so pages never
+   * overlap or skip a symbol while the index is unchanged.
+   */
+  findSymbolsPageByPattern(
+    pattern: string,
+    kinds: SymbolKind[],
+    caseSensitive: boolean,
+    offset: number,
+    limit: number
+  ): { symbols: SymbolRecord[]; total: number } {
+    const { where, params } = this.patternFilter(pattern, kinds, caseSensitive);
+    const { total } = this.db.prepare(`SELECT COUNT(*) as total FROM symbols WHERE ${where}`).get(...params) as {
+      total: number;
+    };
+    const stmt = this.db.prepare(`
+      SELECT symbol_id as symbolId, file_id as fileId, language, kind, name,
+             qualified_name as qualifiedName, start_line as startLine,
+             start_col as startCol, end_line as endLine, end_col as endCol,
+             signature, exported, chunk_hash as chunkHash,
+             chunk_summary as chunkSummary, summary_tokens as summaryTokens,
+             summarized_at as summarizedAt, details
+      FROM symbols WHERE ${where}
+      ORDER BY name, qualified_name, symbol_id
+      LIMIT ? OFFSET ?
+    `);
+    const rows = stmt.all(...params, limit, offset) as SymbolRow[];
+    return { symbols: rows.map(row => this.toSymbolRecord(row)), total };
+  }
+
+  /**
+   * Match the snake_case search names stored with the nameForms option
+   * against a glob pattern, ordered like findSymbolsByPattern
+   */
+  findSymbolsBySearchName(pattern: string, kinds: SymbolKind[] = []): SymbolRecord[] {
+    let where = 'search_name GLOB ?';
+    const params: any[] = [pattern.replace(/\[/g, '[[]')];
+    if (kinds.length > 0) {
+      where += ` AND kind IN (${kinds.map(() => '?').join(', ')})`;
+      params.push(...kinds);
+    }
+    const stmt = this.db.prepare(`
+      SELECT symbol_id as symbolId, file_id as fileId, language, kind, name,
+             qualified_name as qualifiedName, start_line as startLine,
+             start_col as startCol, end_line as endLine, end_col as endCol,
+             signature, exported, chunk_hash as chunkHash,
+             chunk_summary as chunkSummary, summary_tokens as summaryTokens,
+             summarized_at as summarizedAt, details
+      FROM symbols WHERE ${where}
+      ORDER BY name, qualified_name, symbol_id
+    `);
+    return (stmt.all(...params) as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  private patternFilter(pattern: string, kinds: SymbolKind[], caseSensitive: boolean): { where: string; params: any[] } {
+    let where = '1 = 1';
+    const params: any[] = [];
+
+    if (pattern) {
+      // '[' starts a character class in GLOB; match it literally
+      const glob = pattern.replace(/\[/g, '[[]');
+      where += caseSensitive ? ' AND name GLOB ?' : ' AND unicode_lower(name) GLOB unicode_lower(?)';
+      params.push(glob);
+    }
+    if (kinds.length > 0) {
+      where += ` AND kind IN (${kinds.map(() => '?').join(', ')})`;
+      params.push(...kinds);
+    }
+    return { where, params };
+  }
+
+  /**
+   * Go symbols whose qualified name is declared more than once, with the
+   * path of the declaring file
+   */
+  findDuplicateGoSymbols(): Array<{ path: string; symbol: SymbolRecord }> {
+    const stmt = this.db.prepare(`
+      SELECT s.symbol_id as symbolId, s.file_id as fileId, s.language, s.kind, s.name,
+             s.qualified_name as qualifiedName, s.start_line as startLine,
+             s.start_col as startCol, s.end_line as endLine, s.end_col as endCol,
+             s.signature, s.exported, s.chunk_hash as chunkHash,
+             s.chunk_summary as chunkSummary, s.summary_tokens as summaryTokens,
+             s.summarized_at as summarizedAt, s.details, f.path
+      FROM symbols s
+      JOIN files f ON s.file_id = f.file_id
+      WHERE s.language = 'go' AND s.qualified_name IN (
+        SELECT qualified_name FROM symbols
+        WHERE language = 'go'
+        GROUP BY qualified_name
+        HAVING COUNT(*) > 1
+      )
+      ORDER BY s.qualified_name, s.symbol_id
+    `);
+    return (stmt.all() as Array<SymbolRow & { path: string }>).map(({ path, ...row }) => ({
+      path,
+      symbol: this.toSymbolRecord(row),
+    }));
+  }
+
+  findMethodsByReceiver(receiverType: string): SymbolRecord[] {
+    const stmt = this.db.prepare(`
+      SELECT symbol_id as symbolId, file_id as fileId, language, kind, name,
+             qualified_name as qualifiedName, start_line as startLine,
+             start_col as startCol, end_line as endLine, end_col as endCol,
+             signature, exported, chunk_hash as chunkHash,
+             chunk_summary as chunkSummary, summary_tokens as summaryTokens,
+             summarized_at as summarizedAt, details
+      FROM symbols WHERE kind = 'method' AND receiver_type = ?
+      ORDER BY symbol_id
+    `);
+    return (stmt.all(receiverType) as SymbolRow[]).map(row => this.toSymbolRecord(row));
   }
 
   getAllSymbols(): SymbolRecord[] {
@@ -240,10 +685,20 @@ export class CodeDatabase {
              start_col as startCol, end_line as endLine, end_col as endCol,
              signature, exported, chunk_hash as chunkHash,
              chunk_summary as chunkSummary, summary_tokens as summaryTokens,
-             summarized_at as summarizedAt
+             summarized_at as summarizedAt, details
       FROM symbols
     `);
-    return stmt.all() as SymbolRecord[];
+    return (stmt.all() as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  /**
+   * ID, kind, name and qualified name of every symbol, for the in-memory
+   * prefix index
+   */
+  getSymbolNames(): Array<{ symbolId: number; kind: SymbolKind; name: string; qualifiedName: string }> {
+    return this.db.prepare(`
+      SELECT symbol_id as symbolId, kind, name, qualified_name as qualifiedName FROM symbols
+    `).all() as Array<{ symbolId: number; kind: SymbolKind; name: string; qualifiedName: string }>;
   }
 
   getSymbolById(symbolId: number): SymbolRecord | undefined {
@@ -253,10 +708,11 @@ export class CodeDatabase {
              start_col as startCol, end_line as endLine, end_col as endCol,
              signature, exported, chunk_hash as chunkHash,
              chunk_summary as chunkSummary, summary_tokens as summaryTokens,
-             summarized_at as summarizedAt
+             summarized_at as summarizedAt, details
       FROM symbols WHERE symbol_id = ?
     `);
-    return stmt.get(symbolId) as SymbolRecord | undefined;
+    const row = stmt.get(symbolId) as SymbolRow | undefined;
+    return row ? this.toSymbolRecord(row) : undefined;
   }
 
   getSymbolsInFile(fileId: number): SymbolRecord[] {
@@ -266,10 +722,39 @@ export class CodeDatabase {
              start_col as startCol, end_line as endLine, end_col as endCol,
              signature, exported, chunk_hash as chunkHash,
              chunk_summary as chunkSummary, summary_tokens as summaryTokens,
-             summarized_at as summarizedAt
+             summarized_at as summarizedAt, details
       FROM symbols WHERE file_id = ?
     `);
-    return stmt.all(fileId) as SymbolRecord[];
+    return (stmt.all(fileId) as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  /**
+   * The innermost symbol of the file at path whose range contains line:col
+   * (1-based; the end position is exclusive). Ranges nest, so the one that
+   * starts last, then ends first, is the smallest.
+   */
+  findInnermostSymbolAt(path: string, line: number, col: number): SymbolRecord | undefined {
+    const row = this.db.prepare(`
+      SELECT s.symbol_id as symbolId, s.file_id as fileId, s.language, s.kind, s.name,
+             s.qualified_name as qualifiedName, s.start_line as startLine,
+             s.start_col as startCol, s.end_line as endLine, s.end_col as endCol,
+             s.signature, s.exported, s.chunk_hash as chunkHash,
+             s.chunk_summary as chunkSummary, s.summary_tokens as summaryTokens,
+             s.summarized_at as summarizedAt, s.details
+      FROM symbols s
+      JOIN files f ON f.file_id = s.file_id
+      WHERE f.path = ?
+        AND (s.start_line < ? OR (s.start_line = ? AND s.start_col <= ?))
+        AND (s.end_line > ? OR (s.end_line = ? AND s.end_col > ?))
+      ORDER BY s.start_line DESC, s.start_col DESC, s.end_line, s.end_col, s.symbol_id
+      LIMIT 1
+    `).get(path, line, line, col, line, line, col) as SymbolRow | undefined;
+    return row ? this.toSymbolRecord(row) : undefined;
+  }
+
+  private toSymbolRecord(row: SymbolRow): SymbolRecord {
+    const { details, ...symbol } = row;
+    return details ? { ...symbol, details: JSON.parse(details) } : symbol;
   }
 
   deleteSymbolsByFile(fileId: number): void {
@@ -309,11 +794,11 @@ export class CodeDatabase {
              start_col as startCol, end_line as endLine, end_col as endCol,
              signature, exported, chunk_hash as chunkHash,
              chunk_summary as chunkSummary, summary_tokens as summaryTokens,
-             summarized_at as summarizedAt
+             summarized_at as summarizedAt, details
       FROM symbols
       WHERE chunk_summary IS NULL
     `);
-    return stmt.all() as SymbolRecord[];
+    return (stmt.all() as SymbolRow[]).map(row => this.toSymbolRecord(row));
   }
 
   // Call operations
@@ -362,6 +847,48 @@ export class CodeDatabase {
     this.db.prepare('DELETE FROM calls WHERE site_file_id = ?').run(fileId);
   }
 
+  insertCallEdge(edge: CallEdgeRecord): number {
+    const stmt = this.db.prepare(`
+      INSERT INTO call_edges (
+        caller_symbol_id, callee_qualified_name, site_file_id,
+        site_start_line, site_start_col, site_end_line, site_end_col
+      ) VALUES (?, ?, ?, ?, ?, ?, ?)
+    `);
+    const result = stmt.run(
+      edge.callerSymbolId,
+      edge.calleeQualifiedName,
+      edge.siteFileId,
+      edge.siteStartLine,
+      edge.siteStartCol,
+      edge.siteEndLine,
+      edge.siteEndCol
+    );
+    return result.lastInsertRowid as number;
+  }
+
+  /**
+   * Distinct symbols with at least one call edge to the given qualified name
+   */
+  findCallers(calleeQualifiedName: string): SymbolRecord[] {
+    const stmt = this.db.prepare(`
+      SELECT DISTINCT s.symbol_id as symbolId, s.file_id as fileId, s.language, s.kind, s.name,
+             s.qualified_name as qualifiedName, s.start_line as startLine,
+             s.start_col as startCol, s.end_line as endLine, s.end_col as endCol,
+             s.signature, s.exported, s.chunk_hash as chunkHash,
+             s.chunk_summary as chunkSummary, s.summary_tokens as summaryTokens,
+             s.summarized_at as summarizedAt, s.details
+      FROM call_edges e
+      JOIN symbols s ON s.symbol_id = e.caller_symbol_id
+      WHERE e.callee_qualified_name = ?
+      ORDER BY s.qualified_name, s.symbol_id
+    `);
+    return (stmt.all(calleeQualifiedName) as SymbolRow[]).map(row => this.toSymbolRecord(row));
+  }
+
+  deleteCallEdgesByFile(fileId: number): void {
+    this.db.prepare('DELETE FROM call_edges WHERE site_file_id = ?').run(fileId);
+  }
+
   // Reference operations
   insertReference(ref: ReferenceRecord): number {
     const stmt = this.db.prepare(`
@@ -397,6 +924,213 @@ export class CodeDatabase {
     this.db.prepare('DELETE FROM symbol_references WHERE from_file_id = ?').run(fileId);
   }
 
+  /**
+   * Record the exported-looking names a file uses (see ExtractionResult.usedNames)
+   */
+  insertNameUses(fileId: number, names: string[]): void {
+    const stmt = this.db.prepare('INSERT OR IGNORE INTO name_uses (file_id, name) VALUES (?, ?)');
+    for (const name of names) {
+      stmt.run(fileId, name);
+    }
+  }
+
+  deleteNameUsesByFile(fileId: number): void {
+    this.db.prepare('DELETE FROM name_uses WHERE file_id = ?').run(fileId);
+  }
+
+  /**
+   * Exported Go symbols whose name no indexed file uses (see name_uses),
+   * ordered by file and position
+   */
+  findUnusedExportedGoSymbols(kinds: SymbolKind[]): Array<{ path: string; symbol: SymbolRecord }> {
+    const rows = this.db.prepare(`
+      SELECT s.symbol_id as symbolId, s.file_id as fileId, s.language, s.kind, s.name,
+             s.qualified_name as qualifiedName, s.start_line as startLine,
+             s.start_col as startCol, s.end_line as endLine, s.end_col as endCol,
+             s.signature, s.exported, s.chunk_hash as chunkHash,
+             s.chunk_summary as chunkSummary, s.summary_tokens as summaryTokens,
+             s.summarized_at as summarizedAt, s.details, f.path
+      FROM symbols s JOIN files f ON f.file_id = s.file_id
+      WHERE s.language = 'go' AND s.exported = 1
+        AND s.kind IN (${kinds.map(() => '?').join(', ')})
+        AND NOT EXISTS (SELECT 1 FROM name_uses u WHERE u.name = s.name)
+      ORDER BY f.path, s.start_line, s.start_col
+    `).all(...kinds) as Array<SymbolRow & { path: string }>;
+    return rows.map(({ path, ...row }) => ({ path, symbol: this.toSymbolRecord(row) }));
+  }
+
+  // Import operations
+  insertImport(imp: ImportRecord): number {
+    const stmt = this.db.prepare(`
+      INSERT INTO file_imports (file_id, path, alias)
+      VALUES (?, ?, ?)
+    `);
+    const result = stmt.run(imp.fileId, imp.path, imp.alias || null);
+    return result.lastInsertRowid as number;
+  }
+
+  getImportsByFile(fileId: number): ImportRecord[] {
+    const stmt = this.db.prepare(`
+      SELECT import_id as importId, file_id as fileId, path, alias
+      FROM file_imports WHERE file_id = ?
+      ORDER BY import_id
+    `);
+    const rows = stmt.all(fileId) as Array<ImportRecord & { alias: string | null }>;
+    return rows.map(({ alias, ...imp }) => {
+      if (!alias) return imp;
+      return { ...imp, alias, isBlank: alias === '_', isDot: alias === '.' };
+    });
+  }
+
+  deleteImportsByFile(fileId: number): void {
+    this.db.prepare('DELETE FROM file_imports WHERE file_id = ?').run(fileId);
+  }
+
+  // Directive operations
+  insertDirective(fileId: number, directive: Directive): void {
+    this.db.prepare(`
+      INSERT INTO file_directives (file_id, name, args, line, symbol_qualified_name)
+      VALUES (?, ?, ?, ?, ?)
+    `).run(fileId, directive.name, directive.args, directive.line, directive.symbol ?? null);
+  }
+
+  /**
+   * Directives of all files, or only those with the given name, ordered by
+   * path and line
+   */
+  findDirectives(name?: string): FileDirective[] {
+    const rows = this.db.prepare(`
+      SELECT f.path, d.name, d.args, d.line, d.symbol_qualified_name as symbol
+      FROM file_directives d
+      JOIN files f ON f.file_id = d.file_id
+      ${name === undefined ? '' : 'WHERE d.name = ?'}
+      ORDER BY f.path, d.line
+    `).all(...(name === undefined ? [] : [name])) as Array<FileDirective & { symbol: string | null }>;
+    return rows.map(({ symbol, ...directive }) => (symbol ? { ...directive, symbol } : directive));
+  }
+
+  deleteDirectivesByFile(fileId: number): void {
+    this.db.prepare('DELETE FROM file_directives WHERE file_id = ?').run(fileId);
+  }
+
+  // Parse error operations
+  insertParseErrors(fileId: number, errors: ParseError[]): void {
+    const stmt = this.db.prepare('INSERT INTO parse_errors (file_id, line, col, message) VALUES (?, ?, ?, ?)');
+    for (const error of errors) {
+      stmt.run(fileId, error.line, error.column, error.message);
+    }
+  }
+
+  /**
+   * Syntax errors of all files, or of the file at path, ordered by path and
+   * position
+   */
+  findParseErrors(path?: string): FileParseError[] {
+    return this.db.prepare(`
+      SELECT f.path, e.line, e.col as column, e.message
+      FROM parse_errors e
+      JOIN files f ON f.file_id = e.file_id
+      ${path === undefined ? '' : 'WHERE f.path = ?'}
+      ORDER BY f.path, e.line, e.col
+    `).all(...(path === undefined ? [] : [path])) as FileParseError[];
+  }
+
+  deleteParseErrorsByFile(fileId: number): void {
+    this.db.prepare('DELETE FROM parse_errors WHERE file_id = ?').run(fileId);
+  }
+
+  // Comment operations
+  insertComments(fileId: number, groups: CommentGroup[]): void {
+    const stmt = this.db.prepare(`
+      INSERT INTO file_comments (file_id, group_no, start_line, start_col, end_line, end_col, text)
+      VALUES (?, ?, ?, ?, ?, ?, ?)
+    `);
+    groups.forEach((group, groupNo) => {
+      for (const comment of group.comments) {
+        stmt.run(fileId, groupNo, comment.startLine, comment.startCol, comment.endLine, comment.endCol, comment.text);
+      }
+    });
+  }
+
+  /**
+   * Comment groups of the file at path in source order; empty for files
+   * indexed without includeComments
+   */
+  findComments(path: string): CommentGroup[] {
+    const rows = this.db.prepare(`
+      SELECT c.group_no as groupNo, c.start_line as startLine, c.start_col as startCol,
+             c.end_line as endLine, c.end_col as endCol, c.text
+      FROM file_comments c
+      JOIN files f ON f.file_id = c.file_id
+      WHERE f.path = ?
+      ORDER BY c.group_no, c.start_line, c.start_col
+    `).all(path) as Array<Comment & { groupNo: number }>;
+
+    const groups: CommentGroup[] = [];
+    let lastGroupNo = -1;
+    for (const { groupNo, ...comment } of rows) {
+      if (groupNo !== lastGroupNo) {
+        groups.push({ startLine: comment.startLine, startCol: comment.startCol, endLine: 0, endCol: 0, comments: [] });
+        lastGroupNo = groupNo;
+      }
+      const group = groups[groups.length - 1];
+      group.endLine = comment.endLine;
+      group.endCol = comment.endCol;
+      group.comments.push(comment);
+    }
+    return groups;
+  }
+
+  deleteCommentsByFile(fileId: number): void {
+    this.db.prepare('DELETE FROM file_comments WHERE file_id = ?').run(fileId);
+  }
+
+  /**
+   * Copy the files of another database into this one, with everything that
+   * belongs to them, renaming each file to rewritePath(path). Files for which
+   * rewritePath returns null are left out. Ids are reassigned, so rows of
+   * both databases can live side by side.
+   */
+  importFrom(source: CodeDatabase, rewritePath: (path: string) => string | null): void {
+    const insertRow = (table: string, row: Record<string, unknown>): number => {
+      const columns = Object.keys(row);
+      const result = this.db
+        .prepare(`INSERT INTO ${table} (${columns.join(', ')}) VALUES (${columns.map(() => '?').join(', ')})`)
+        .run(...columns.map(column => row[column]));
+      return result.lastInsertRowid as number;
+    };
+
+    this.transaction(() => {
+      const fileIds = new Map<number, number>();
+      for (const { file_id, ...file } of source.db.prepare('SELECT * FROM files').all() as Array<Record<string, unknown>>) {
+        const path = rewritePath(file.path as string);
+        if (path !== null) {
+          fileIds.set(file_id as number, insertRow('files', { ...file, path }));
+        }
+      }
+
+      const symbolIds = new Map<number, number>();
+      for (const { symbol_id, ...symbol } of source.db.prepare('SELECT * FROM symbols').all() as Array<Record<string, unknown>>) {
+        const fileId = fileIds.get(symbol.file_id as number);
+        if (fileId !== undefined) {
+          symbolIds.set(symbol_id as number, insertRow('symbols', { ...symbol, file_id: fileId }));
+        }
+      }
+
+      for (const { table, id, fileColumns, symbolColumns } of DEPENDENT_TABLES) {
+        for (const row of source.db.prepare(`SELECT * FROM ${table}`).all() as Array<Record<string, unknown>>) {
+          if (id) delete row[id];
+          const remapped = [
+            ...fileColumns.map(column => [column, fileIds.get(row[column] as number)] as const),
+            ...symbolColumns.map(column => [column, symbolIds.get(row[column] as number)] as const),
+          ];
+          if (remapped.some(([, value]) => value === undefined)) continue;
+          insertRow(table, { ...row, ...Object.fromEntries(remapped) });
+        }
+      }
+    });
+  }
+
   // Location lookup
   getSymbolLocation(symbolId: number): Location | undefined {
     const stmt = this.db.prepare(`
@@ -409,6 +1143,18 @@ export class CodeDatabase {
     return stmt.get(symbolId) as Location | undefined;
   }
 
+  /**
+   * A token that changes whenever the database may have changed: rows
+   * written through this connection, or commits made through any other
+   * connection to the same file. Caches built from the database compare it
+   * to know when to rebuild.
+   */
+  changeToken(): string {
+    const changes = (this.db.prepare('SELECT total_changes() as n').get() as { n: number }).n;
+    const version = this.db.pragma('data_version', { simple: true }) as number;
+    return `${changes}:${version}`;
+  }
+
   // Transaction support
   beginTransaction(): void {
     this.db.prepare('BEGIN').run();
@@ -433,7 +1179,13 @@ export class CodeDatabase {
     this.db.transaction(() => {
       this.db.exec(`
         DELETE FROM symbol_references;
+        DELETE FROM name_uses;
+        DELETE FROM file_imports;
+        DELETE FROM file_directives;
+        DELETE FROM parse_errors;
+        DELETE FROM file_comments;
         DELETE FROM calls;
+        DELETE FROM call_edges;
         DELETE FROM symbols;
         DELETE FROM files;
       `);
@@ -546,7 +1298,7 @@ export class CodeDatabase {
              s.start_col as startCol, s.end_line as endLine, s.end_col as endCol,
              s.signature, s.exported, s.chunk_hash as chunkHash,
              s.chunk_summary as chunkSummary, s.summary_tokens as summaryTokens,
-             s.summarized_at as summarizedAt
+             s.summarized_at as summarizedAt, s.details
       FROM symbols s
       WHERE s.chunk_summary IS NOT NULL
         AND s.chunk_hash IS NOT NULL
@@ -555,7 +1307,7 @@ export class CodeDatabase {
           WHERE e.symbol_id = s.symbol_id AND e.model = ? AND e.chunk_hash = s.chunk_hash
         )
     `);
-    return stmt.all(model) as SymbolRecord[];
+    return (stmt.all(model) as SymbolRow[]).map(row => this.toSymbolRecord(row));
   }
 
   hasEmbedding(symbolId: number, model: string): boolean {
@@ -567,6 +1319,13 @@ export class CodeDatabase {
     return !!stmt.get(symbolId, model);
   }
 
+  /**
+   * The database as bytes, as stored in a database file
+   */
+  serialize(): Buffer {
+    return this.db.serialize();
+  }
+
   close(): void {
     this.db.close();
   }
@@ -0,0 +1,68 @@
+/**
+ * Binary cache file of the whole index, for fast startup
+ *
+ * Layout: the magic bytes "CIDXCACHE", a uint32 (big-endian) cache version,
+ * then the serialized SQLite database. The database records each file's
+ * mtime and size, so after loading a cache refreshAll only reparses files
+ * that changed since it was saved.
+ */
+
+import { mkdirSync, readFileSync, renameSync, writeFileSync } from 'fs';
+import { dirname } from 'path';
+import type { CodeDatabase } from './database.js';
+
+const MAGIC = Buffer.from('CIDXCACHE', 'ascii');
+
+/**
+ * Bump whenever the schema or the meaning of stored data changes, so caches
+ * written by older versions are rejected instead of misread
+ */
+export const CACHE_VERSION = 28;
+
+/**
+ * Thrown by readCache for a file that is not an index cache or was written
+ * with a different CACHE_VERSION. Rebuild the index and save a new cache.
+ */
+export class StaleCacheError extends Error {
+  constructor(
+    public readonly path: string,
+    public readonly version: number | null
+  ) {
+    super(
+      version === null
+        ? `${path} is not a codeindex cache`
+        : `${path} is a version ${version} cache, expected version ${CACHE_VERSION}`
+    );
+    this.name = 'StaleCacheError';
+  }
+}
+
+/**
+ * Write the database to a cache file. The file is replaced atomically, so a
+ * crash mid-write leaves the previous cache intact.
+ */
+export function writeCache(db: CodeDatabase, path: string): void {
+  const header = Buffer.alloc(MAGIC.length + 4);
+  MAGIC.copy(header);
+  header.writeUInt32BE(CACHE_VERSION, MAGIC.length);
+
+  mkdirSync(dirname(path), { recursive: true });
+  const tmpPath = `${path}.tmp`;
+  writeFileSync(tmpPath, Buffer.concat([header, db.serialize()]));
+  renameSync(tmpPath, path);
+}
+
+/**
+ * Read a cache file and return the serialized database it holds
+ */
+export function readCache(path: string): Buffer {
+  const data = readFileSync(path);
+  if (data.length < MAGIC.length + 4 || !data.subarray(0, MAGIC.length).equals(MAGIC)) {
+    throw new StaleCacheError(path, null);
+  }
+  const version = data.readUInt32BE(MAGIC.length);
+  if (version !== CACHE_VERSION) {
+    throw new StaleCacheError(path, version);
+  }
+  return data.subarray(MAGIC.length + 4);
+}
@@ -0,0 +1,56 @@
+/**
+ * Combining indexes built from separate roots into one
+ */
+
+import { posix, relative, resolve, sep } from 'path';
+import type { CodeDatabase } from './database.js';
+import type { MergeConflict } from '../core/types.js';
+
+/**
+ * The deepest directory that contains every one of dirs
+ */
+export function commonRoot(dirs: string[]): string {
+  const [first, ...rest] = dirs.map(dir => resolve(dir).split(sep));
+  let length = first.length;
+  for (const parts of rest) {
+    let i = 0;
+    while (i < length && i < parts.length && parts[i] === first[i]) i++;
+    length = i;
+  }
+  return first.slice(0, length).join(sep) || sep;
+}
+
+/**
+ * Copy every source database into target, rebasing file paths from the
+ * source's rootDir onto rootDir. Each package directory comes from the first
+ * source that has it: later sources' files in that directory are left out
+ * and the directory is reported as a conflict.
+ */
+export function mergeInto(
+  target: CodeDatabase,
+  rootDir: string,
+  sources: Array<{ rootDir: string; db: CodeDatabase }>
+): MergeConflict[] {
+  const owners = new Map<string, number>(); // merged directory → index of the source it came from
+  const conflicts = new Map<string, MergeConflict>();
+
+  sources.forEach((source, i) => {
+    const prefix = relative(rootDir, resolve(source.rootDir)).split(sep).join('/');
+    target.importFrom(source.db, path => {
+      const merged = prefix ? posix.join(prefix, path) : path;
+      const dir = posix.dirname(merged);
+      const owner = owners.get(dir) ?? i;
+      if (owner === i) {
+        owners.set(dir, i);
+        return merged;
+      }
+
+      const conflict = conflicts.get(dir) ?? { dir, roots: [sources[owner].rootDir] };
+      if (!conflict.roots.includes(source.rootDir)) conflict.roots.push(source.rootDir);
+      conflicts.set(dir, conflict);
+      return null;
+    });
+  });
+
+  return Array.from(conflicts.values()).sort((a, b) => (a.dir < b.dir ? -1 : a.dir > b.dir ? 1 : 0));
+}
@@ -11,6 +11,7 @@ export interface WatchOptions {
   rootDir: string;
   include?: string[];
   exclude?: string[];
+  followSymlinks?: boolean; // 是否跟随符号链接，默认 false（与索引一致）
   debounceMs?: number; // 防抖延迟，默认 500ms
   batchIntervalMs?: number; // 批量索引间隔（毫秒），默认 10 分钟
   minChangeLines?: number; // 最小变更行数才触发索引，默认 0（每次都索引）
@@ -45,6 +46,7 @@ export class FileWatcher {
       rootDir,
       include = ['**/*'],
       exclude = ['**/node_modules/**', '**/.git/**', '**/dist/**', '**/build/**'],
+      followSymlinks = false,
       debounceMs = 500, // 默认值，会被配置文件或 CLI 参数覆盖
       batchIntervalMs = 10 * 60 * 1000, // 默认 10 分钟，会被配置文件或 CLI 参数覆盖
       minChangeLines = 0, // 默认不限制变更行数，会被配置文件或 CLI 参数覆盖
@@ -89,6 +91,7 @@ export class FileWatcher {
         pollInterval: 100,
       },
       cwd: absoluteRootDir,
+      followSymlinks,
       alwaysStat: false,
       usePolling: false, // 优先使用文件系统事件，如果失败会自动降级到轮询
       depth: 99, // 监听深层目录
@@ -0,0 +1,128 @@
+/**
+ * Stream of index updates driven by file system events
+ */
+
+import chokidar from 'chokidar';
+import { existsSync } from 'fs';
+import { relative, resolve } from 'path';
+import { Indexer } from '../indexer/indexer.js';
+import type { IndexDelta } from '../core/types.js';
+
+export type IndexChangeKind = 'created' | 'modified' | 'deleted';
+
+export interface IndexEvent {
+  path: string; // 相对 rootDir 的路径
+  change: IndexChangeKind;
+  delta: IndexDelta;
+}
+
+export interface IndexEventStreamOptions {
+  rootDir: string;
+  exclude?: string[];
+  followSymlinks?: boolean; // 是否跟随符号链接，默认 false（与索引一致）
+  debounceMs?: number; // 同一文件连续变更的合并窗口，默认 200ms（覆盖编辑器的临时文件 + rename 保存）
+  signal?: AbortSignal; // 取消后停止监听，迭代正常结束
+  onError?: (error: Error) => void;
+}
+
+/**
+ * Watch rootDir and yield one event per settled file change, after the index
+ * has been updated. Rapid successive events for a path (an atomic save is an
+ * unlink followed by an add) are merged, and the change kind is decided by
+ * comparing the index before the update with the disk after it, so an atomic
+ * save reports `modified`. Files the indexer ignores produce no events.
+ *
+ * Iteration ends when the signal aborts or the consumer stops iterating;
+ * either way the watcher and pending timers are released.
+ */
+export async function* watchIndexEvents(
+  indexer: Indexer,
+  options: IndexEventStreamOptions
+): AsyncGenerator<IndexEvent, void, undefined> {
+  const {
+    rootDir,
+    exclude = ['**/node_modules/**', '**/.git/**'],
+    followSymlinks = false,
+    debounceMs = 200,
+    signal,
+    onError,
+  } = options;
+  const absoluteRootDir = resolve(rootDir);
+
+  const queue: IndexEvent[] = [];
+  const timers = new Map<string, NodeJS.Timeout>();
+  let wake: (() => void) | null = null;
+  let processing = Promise.resolve();
+
+  const notify = () => {
+    wake?.();
+    wake = null;
+  };
+
+  const update = async (absolutePath: string): Promise<void> => {
+    const path = relative(absoluteRootDir, absolutePath);
+    const indexedBefore = indexer.isIndexed(absolutePath);
+    const delta = await indexer.updateFile(absolutePath);
+    const exists = existsSync(absolutePath);
+    const indexedAfter = indexer.isIndexed(absolutePath);
+
+    if (!indexedBefore && !indexedAfter) {
+      return; // Unsupported language, excluded by build constraints, or a temp file
+    }
+    const change: IndexChangeKind = !exists || !indexedAfter ? 'deleted' : indexedBefore ? 'modified' : 'created';
+    queue.push({ path, change, delta });
+    notify();
+  };
+
+  const schedule = (filePath: string) => {
+    const absolutePath = resolve(absoluteRootDir, filePath);
+    clearTimeout(timers.get(absolutePath));
+    timers.set(
+      absolutePath,
+      setTimeout(() => {
+        timers.delete(absolutePath);
+        // Serialize updates so two files never write to the database at once
+        processing = processing
+          .then(() => update(absolutePath))
+          .catch(error => {
+            const err = error instanceof Error ? error : new Error(String(error));
+            onError ? onError(err) : console.error('[Watcher] ❌ Error:', err.message);
+          });
+      }, debounceMs)
+    );
+  };
+
+  const watcher = chokidar.watch(absoluteRootDir, {
+    ignored: exclude,
+    persistent: true,
+    ignoreInitial: true,
+    cwd: absoluteRootDir,
+    followSymlinks,
+  });
+  watcher.on('add', schedule);
+  watcher.on('change', schedule);
+  watcher.on('unlink', schedule);
+  watcher.on('error', (error: unknown) => {
+    const err = error instanceof Error ? error : new Error(String(error));
+    onError ? onError(err) : console.error('[Watcher] ❌ Error:', err.message);
+  });
+
+  signal?.addEventListener('abort', notify, { once: true });
+
+  try {
+    while (!signal?.aborted) {
+      const event = queue.shift();
+      if (event) {
+        yield event;
+      } else {
+        await new Promise<void>(resolve => (wake = resolve));
+      }
+    }
+  } finally {
+    signal?.removeEventListener('abort', notify);
+    timers.forEach(timer => clearTimeout(timer));
+    timers.clear();
+    await watcher.close();
+    await processing;
+  }
+}